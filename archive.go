@@ -0,0 +1,211 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultArchiveWorkers  = 1
+	DefaultArchiveAttempts = 3
+	DefaultArchiveBackoff  = time.Second
+	DefaultArchiveQueue    = 0 // unbounded
+)
+
+// Archiver processes a rotated log file after rotation, e.g. by compressing,
+// moving, uploading, or deleting it. If the Writer signs rotated files, the
+// signature sidecar is at path + SignatureExt and is the Archiver's to handle.
+type Archiver interface {
+	Archive(ctx context.Context, path string) error
+}
+
+// ArchiverFunc adapts an ordinary function to the Archiver interface.
+type ArchiverFunc func(ctx context.Context, path string) error
+
+// Archive calls f(ctx, path).
+func (f ArchiverFunc) Archive(ctx context.Context, path string) error {
+	return f(ctx, path)
+}
+
+// WithArchiver configures the Writer to pass every rotated file to a. Archiving
+// runs in the background on a pool of workers (see WithArchiveWorkers), so it
+// never delays writes. Failed attempts are retried (see WithArchiveRetry); files
+// that still fail are left in place and the error is counted in Stats, kept by
+// LastError, and passed to the error handler.
+// Retention and bundling leave files alone while they wait for or are being
+// archived. Close cancels the context passed to the Archiver and waits for the
+// attempts in progress; files still queued are left in place.
+//
+// A Writer has a single Archiver; WithArchiver and WithUploader replace each other.
+func WithArchiver(a Archiver) Option {
+	return func(w *Writer) {
+		w.archiver = a
+	}
+}
+
+// WithArchiveWorkers sets the number of rotated files archived concurrently.
+// With more than one worker, files may finish archiving out of rotation order.
+func WithArchiveWorkers(n int) Option {
+	return func(w *Writer) {
+		w.archiveWorkers = n
+	}
+}
+
+// WithArchiveQueue limits how many rotated files may wait for an archive
+// worker. When the queue is full, e.g. because an uploader is down during a
+// burst of rotations, further files are left in place rather than queued and an
+// error is reported as for failed archiving; retention still applies to them.
+// Zero, the default, leaves the queue unbounded. See ArchiveQueueLen.
+func WithArchiveQueue(n int) Option {
+	return func(w *Writer) {
+		w.archiveQueue = n
+	}
+}
+
+// WithArchiveRetry sets how many times archiving a file is attempted and the
+// delay before the first retry, which doubles after each further failure.
+func WithArchiveRetry(attempts int, backoff time.Duration) Option {
+	return func(w *Writer) {
+		w.archiveAttempts = attempts
+		w.archiveBackoff = backoff
+	}
+}
+
+// archivePool archives rotated files on a fixed number of background workers.
+type archivePool struct {
+	a        Archiver
+	attempts int
+	backoff  time.Duration
+	limit    int
+	fail     func(error) // reports files that couldn't be archived
+
+	pending atomic.Int64 // queued plus in progress
+
+	ctx    context.Context // canceled by close
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []string
+	busy   map[string]bool // base names of files queued or in progress
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newArchivePool(a Archiver, workers, attempts, limit int, backoff time.Duration, fail func(error)) *archivePool {
+	p := &archivePool{a: a, attempts: attempts, backoff: backoff, limit: limit, fail: fail, busy: make(map[string]bool)}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.cond = sync.NewCond(&p.mu)
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// push queues path for archiving without blocking. It reports false if the
+// queue is full.
+func (p *archivePool) push(path string) bool {
+	p.mu.Lock()
+	if p.limit > 0 && len(p.queue) >= p.limit {
+		p.mu.Unlock()
+		return false
+	}
+	p.queue = append(p.queue, path)
+	p.busy[filepath.Base(path)] = true
+	p.pending.Add(1)
+	p.mu.Unlock()
+	p.cond.Signal()
+	return true
+}
+
+// archiving returns the base names of the files queued or being archived. A
+// file's ".gz" name counts too, since CompressArchiver replaces the file with
+// it before passing it on.
+func (p *archivePool) archiving() map[string]bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	busy := make(map[string]bool, 2*len(p.busy))
+	for name := range p.busy {
+		busy[name] = true
+		busy[name+".gz"] = true
+	}
+	return busy
+}
+
+// close cancels archiving in progress, leaves queued files in place, and
+// stops the workers.
+func (p *archivePool) close() {
+	p.mu.Lock()
+	p.closed = true
+	for _, path := range p.queue {
+		delete(p.busy, filepath.Base(path))
+	}
+	p.pending.Add(-int64(len(p.queue)))
+	p.queue = nil
+	p.mu.Unlock()
+	p.cancel()
+	p.cond.Broadcast()
+	p.wg.Wait()
+}
+
+func (p *archivePool) work() {
+	defer p.wg.Done()
+	for {
+		p.mu.Lock()
+		for len(p.queue) == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		if len(p.queue) == 0 {
+			p.mu.Unlock()
+			return
+		}
+		path := p.queue[0]
+		p.queue = p.queue[1:]
+		p.mu.Unlock()
+		if err := p.archive(path); err != nil {
+			p.fail(fmt.Errorf("failed to archive %q: %w", path, err))
+		}
+		p.mu.Lock()
+		delete(p.busy, filepath.Base(path))
+		p.mu.Unlock()
+		p.pending.Add(-1)
+	}
+}
+
+// archive runs the Archiver on path, retrying with exponential backoff.
+func (p *archivePool) archive(path string) error {
+	var err error
+	delay := p.backoff
+	for i := 0; i < p.attempts; i++ {
+		if err = p.a.Archive(p.ctx, path); err == nil {
+			return nil
+		}
+		if i < p.attempts-1 {
+			select {
+			case <-time.After(delay):
+			case <-p.ctx.Done():
+				return err
+			}
+			delay *= 2
+		}
+	}
+	return err
+}
+
+// archiving returns the base names of the rotated files queued for or being
+// archived, which retention and bundling must leave alone.
+func (w *Writer) archiving() map[string]bool {
+	if w.archives == nil {
+		return make(map[string]bool)
+	}
+	return w.archives.archiving()
+}
@@ -0,0 +1,212 @@
+package rlog
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memUploader records uploaded file contents by base name.
+type memUploader struct {
+	mu    sync.Mutex
+	files map[string]string
+}
+
+func (u *memUploader) Upload(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.files[filepath.Base(path)] = string(data)
+	return nil
+}
+
+// TestUploader verifies that rotated files are uploaded and then removed locally.
+func TestUploader(t *testing.T) {
+	up := &memUploader{files: make(map[string]string)}
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithMaxFileSize(10), WithUploader(up))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for _, msg := range []string{"abcdef", "ghijkl", "mnopqr"} { // rotates twice
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	waitArchived(t, w)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	names, err := logFiles(OSFS{}, tempDir)
+	if err != nil {
+		t.Fatalf("failed to list log files: %v", err)
+	}
+	if len(names) != 1 || names[0] != "latest.log" {
+		t.Errorf("expected only latest.log to remain locally, got %v", names)
+	}
+	if len(up.files) != 2 {
+		t.Fatalf("expected 2 uploaded files, got %v", up.files)
+	}
+	for name, data := range up.files {
+		if data != "abcdef" && data != "ghijkl" {
+			t.Errorf("unexpected content in uploaded %q: %q", name, data)
+		}
+	}
+}
+
+// TestArchiverRetry verifies that failed archive attempts are retried.
+func TestArchiverRetry(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	archived := ""
+	a := ArchiverFunc(func(ctx context.Context, path string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if attempts++; attempts < 3 {
+			return errors.New("transient failure")
+		}
+		archived = filepath.Base(path)
+		return nil
+	})
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithMaxFileSize(10), WithArchiver(a), WithArchiveRetry(3, time.Millisecond), WithArchiveWorkers(2))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for _, msg := range []string{"abcdef", "ghijkl"} { // second flush rotates
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	waitArchived(t, w)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if attempts != 3 || archived == "" {
+		t.Errorf("expected success on third attempt, got %d attempts (archived %q)", attempts, archived)
+	}
+}
+
+// TestArchiveQueue verifies that the archive queue limit leaves extra files in
+// place and that ArchiveQueueLen counts queued and in-progress files.
+func TestArchiveQueue(t *testing.T) {
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	a := ArchiverFunc(func(ctx context.Context, path string) error {
+		started <- struct{}{}
+		<-release
+		return os.Remove(path)
+	})
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithMaxFileSize(10), WithArchiver(a), WithArchiveQueue(1))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	rotate := func(msg string) {
+		t.Helper()
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	rotate("abcdef")
+	rotate("ghijkl") // rotates "abcdef", which the worker picks up
+	<-started
+	rotate("mnopqr") // rotates "ghijkl" into the queue
+	rotate("stuvwx") // queue is full, so "mnopqr" stays
+	if n := w.ArchiveQueueLen(); n != 2 {
+		t.Errorf("expected 2 files pending archive, got %d", n)
+	}
+	close(release)
+	waitArchived(t, w)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if n := w.ArchiveQueueLen(); n != 0 {
+		t.Errorf("expected an empty archive queue after Close, got %d", n)
+	}
+	names, err := logFiles(OSFS{}, tempDir)
+	if err != nil {
+		t.Fatalf("failed to list log files: %v", err)
+	}
+	if len(names) != 2 {
+		t.Fatalf("expected one rotated file left in place and latest.log, got %v", names)
+	}
+	if data, _ := os.ReadFile(filepath.Join(tempDir, names[0])); string(data) != "mnopqr" {
+		t.Errorf("expected the skipped file to hold %q, got %q", "mnopqr", data)
+	}
+}
+
+// TestArchiveClose verifies that Close cancels a hung archiver, leaving its
+// file in place, and that retention skips files being archived.
+func TestArchiveClose(t *testing.T) {
+	started := make(chan struct{}, 1)
+	a := ArchiverFunc(func(ctx context.Context, path string) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithMaxFileSize(10), WithArchiver(a), WithArchiveRetry(3, time.Hour), WithMaxRotations(1))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for _, msg := range []string{"abcdef", "ghijkl", "mnopqr", "stuvwx"} { // rotates three times
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	<-started
+	done := make(chan error, 1)
+	go func() { done <- w.Close() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close blocked on a hung archiver")
+	}
+	names, err := logFiles(OSFS{}, tempDir)
+	if err != nil {
+		t.Fatalf("failed to list log files: %v", err)
+	}
+	// The rotated files were all busy, so maxRotations deleted none of them.
+	if len(names) != 4 {
+		t.Fatalf("expected three rotated files left in place and latest.log, got %v", names)
+	}
+	if data, _ := os.ReadFile(filepath.Join(tempDir, names[0])); string(data) != "abcdef" {
+		t.Errorf("expected the file being archived to hold %q, got %q", "abcdef", data)
+	}
+}
+
+// waitArchived waits until w has no files queued for or being archived.
+func waitArchived(t *testing.T, w *Writer) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for w.ArchiveQueueLen() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("archiving did not finish, %d files pending", w.ArchiveQueueLen())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
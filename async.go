@@ -0,0 +1,307 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultAsyncCapacity is the number of pending writes an AsyncWriter holds
+// when no capacity is given.
+const DefaultAsyncCapacity = 4096
+
+// AsyncWriter moves all file I/O off the calling goroutine. Write copies p into
+// a lock-free ring buffer and returns, or WriteOwned hands p over without a
+// copy; a single background goroutine drains the ring into a Writer, flushing
+// it when it fills, when it ages past the maximum buffer age, and on Flush and
+// Close. Writes keep their order per goroutine.
+//
+// If the ring is full, Write yields until the drain goroutine makes room, so
+// latency is only bounded while logging keeps pace with the disk. Errors from
+// the underlying Writer are sticky and reported by subsequent calls.
+//
+// AsyncWriter is safe for concurrent use.
+type AsyncWriter struct {
+	w     *Writer
+	ring  *mpscRing
+	slabs slabAllocator
+
+	err      atomic.Pointer[error]
+	closed   atomic.Bool
+	sleeping atomic.Bool
+
+	wake chan struct{}
+	reqs chan asyncReq
+	done chan struct{}
+}
+
+// asyncReq asks the drain goroutine to flush, or close when close is set.
+type asyncReq struct {
+	close bool
+	reply chan error
+}
+
+// NewAsync creates an AsyncWriter whose ring holds capacity pending writes,
+// rounded up to a power of two, in front of a Writer for dirPath. If capacity
+// is not positive, DefaultAsyncCapacity is used. opts configure the Writer,
+// which only the drain goroutine uses, so it's created with WithNoSync.
+func NewAsync(dirPath string, capacity int, opts ...Option) (*AsyncWriter, error) {
+	if capacity <= 0 {
+		capacity = DefaultAsyncCapacity
+	}
+	w, err := New(dirPath, append(append([]Option{}, opts...), WithNoSync())...)
+	if err != nil {
+		return nil, err
+	}
+	a := &AsyncWriter{
+		w:    w,
+		ring: newMPSCRing(capacity),
+		wake: make(chan struct{}, 1),
+		reqs: make(chan asyncReq),
+		done: make(chan struct{}),
+	}
+	go a.drain()
+	return a, nil
+}
+
+// Write queues a copy of p for writing. It only blocks when the ring is full.
+// Copies of small writes are carved from pooled slabs that are reused once
+// written out, so Write rarely allocates.
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	if err := a.err.Load(); err != nil {
+		return 0, *err
+	}
+	if a.closed.Load() {
+		return 0, ErrClosed
+	}
+	data, s := a.slabs.copy(p)
+	return a.push(context.Background(), asyncEntry{data, s})
+}
+
+// WriteContext is like Write but gives up waiting for room in the ring once
+// ctx is done, returning 0 and the context's error; p is then never written.
+func (a *AsyncWriter) WriteContext(ctx context.Context, p []byte) (int, error) {
+	if err := a.err.Load(); err != nil {
+		return 0, *err
+	}
+	if a.closed.Load() {
+		return 0, ErrClosed
+	}
+	data, s := a.slabs.copy(p)
+	return a.push(ctx, asyncEntry{data, s})
+}
+
+// WriteOwned queues p for writing without copying it, and passes it on to the
+// Writer's WriteOwned. The AsyncWriter takes ownership of p: the caller must
+// not modify or reuse it after the call, even if an error is returned. It only
+// blocks when the ring is full.
+func (a *AsyncWriter) WriteOwned(p []byte) (int, error) {
+	if err := a.err.Load(); err != nil {
+		return 0, *err
+	}
+	if a.closed.Load() {
+		return 0, ErrClosed
+	}
+	return a.push(context.Background(), asyncEntry{p: p})
+}
+
+// asyncEntry is a queued write. Data carved from a slab is copied by the Writer
+// and the slab released; other data is owned and handed over as is.
+type asyncEntry struct {
+	p    []byte
+	slab *slab
+}
+
+// push queues e, waiting for room in the ring until ctx is done.
+func (a *AsyncWriter) push(ctx context.Context, e asyncEntry) (int, error) {
+	for !a.ring.push(e) {
+		if err := ctx.Err(); err != nil {
+			e.slab.release()
+			return 0, fmt.Errorf("%w: %w", ErrDropped, err)
+		}
+		a.signal()
+		runtime.Gosched()
+	}
+	if a.sleeping.Load() {
+		a.signal()
+	}
+	return len(e.p), nil
+}
+
+// WriteString is a convenience method that wraps Write() for string data.
+func (a *AsyncWriter) WriteString(s string) (int, error) {
+	return a.Write([]byte(s))
+}
+
+// Flush waits until everything queued before the call has been written and
+// flushed to disk.
+func (a *AsyncWriter) Flush() error {
+	if a.closed.Load() {
+		return ErrClosed
+	}
+	return a.request(false)
+}
+
+// Close drains the ring, closes the underlying Writer, and stops the drain
+// goroutine. Writes racing with Close may be lost. Calling Close again is a
+// no-op returning nil.
+func (a *AsyncWriter) Close() error {
+	if a.closed.Swap(true) {
+		return nil
+	}
+	return a.request(true)
+}
+
+func (a *AsyncWriter) request(close bool) error {
+	reply := make(chan error, 1)
+	select {
+	case a.reqs <- asyncReq{close: close, reply: reply}:
+		return <-reply
+	case <-a.done:
+		return ErrClosed
+	}
+}
+
+// signal wakes the drain goroutine if it isn't already due to wake.
+func (a *AsyncWriter) signal() {
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+}
+
+// drain is the ring's single consumer and the only user of a.w.
+func (a *AsyncWriter) drain() {
+	defer close(a.done)
+	t := time.NewTicker(a.w.maxBufAge)
+	defer t.Stop()
+	for {
+		a.drainRing()
+		// Announce we're about to sleep, then check once more so a push that
+		// raced with the announcement isn't left waiting for the ticker.
+		a.sleeping.Store(true)
+		if !a.ring.empty() {
+			a.sleeping.Store(false)
+			continue
+		}
+		select {
+		case <-a.wake:
+		case <-t.C:
+			a.fail(a.w.Flush())
+		case req := <-a.reqs:
+			a.drainRing()
+			if req.close {
+				err := a.w.Close()
+				a.fail(err)
+				req.reply <- err
+				return
+			}
+			err := a.w.Flush()
+			a.fail(err)
+			req.reply <- err
+		}
+		a.sleeping.Store(false)
+	}
+}
+
+// drainRing writes every queued entry to the Writer.
+func (a *AsyncWriter) drainRing() {
+	for {
+		e, ok := a.ring.pop()
+		if !ok {
+			return
+		}
+		var err error
+		if e.slab != nil {
+			_, err = a.w.Write(e.p)
+			e.slab.release()
+		} else {
+			_, err = a.w.WriteOwned(e.p)
+		}
+		a.fail(err)
+	}
+}
+
+// fail records the first error seen by the drain goroutine.
+func (a *AsyncWriter) fail(err error) {
+	if err != nil && err != ErrClosed {
+		e := err // only escape on failure, not on every call
+		a.err.CompareAndSwap(nil, &e)
+	}
+}
+
+// mpscRing is a bounded multi-producer single-consumer queue after Dmitry
+// Vyukov's bounded MPMC design. Each slot carries a sequence number that tells
+// producers and the consumer whose turn it is, so pushes only contend on a
+// single compare-and-swap.
+type mpscRing struct {
+	slots []ringSlot
+	mask  uint64
+	_     [56]byte
+	tail  atomic.Uint64 // next position to push, shared by producers
+	_     [56]byte
+	head  uint64 // next position to pop, owned by the consumer
+}
+
+type ringSlot struct {
+	seq   atomic.Uint64
+	entry asyncEntry
+}
+
+func newMPSCRing(capacity int) *mpscRing {
+	size := 1
+	for size < capacity {
+		size <<= 1
+	}
+	r := &mpscRing{slots: make([]ringSlot, size), mask: uint64(size - 1)}
+	for i := range r.slots {
+		r.slots[i].seq.Store(uint64(i))
+	}
+	return r
+}
+
+// push adds e to the ring, reporting false if it's full.
+func (r *mpscRing) push(e asyncEntry) bool {
+	pos := r.tail.Load()
+	for {
+		s := &r.slots[pos&r.mask]
+		switch dif := int64(s.seq.Load() - pos); {
+		case dif == 0:
+			if r.tail.CompareAndSwap(pos, pos+1) {
+				s.entry = e
+				s.seq.Store(pos + 1)
+				return true
+			}
+			pos = r.tail.Load()
+		case dif < 0:
+			return false
+		default:
+			pos = r.tail.Load()
+		}
+	}
+}
+
+// pop removes the oldest entry. It must only be called by the consumer.
+func (r *mpscRing) pop() (asyncEntry, bool) {
+	s := &r.slots[r.head&r.mask]
+	if s.seq.Load() != r.head+1 {
+		return asyncEntry{}, false
+	}
+	e := s.entry
+	s.entry = asyncEntry{}
+	s.seq.Store(r.head + r.mask + 1)
+	r.head++
+	return e, true
+}
+
+// empty reports whether the consumer has nothing to pop. A push that has
+// claimed a slot but not yet published it counts as empty.
+func (r *mpscRing) empty() bool {
+	return r.slots[r.head&r.mask].seq.Load() != r.head+1
+}
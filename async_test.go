@@ -0,0 +1,164 @@
+package rlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestAsyncWriter verifies that concurrent writes are drained in per-goroutine
+// order and that Flush makes them visible on disk.
+func TestAsyncWriter(t *testing.T) {
+	dirPath := t.TempDir()
+	a, err := NewAsync(dirPath, 8)
+	if err != nil {
+		t.Fatalf("failed to create AsyncWriter: %v", err)
+	}
+	const goroutines, writes = 8, 500
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < writes; i++ {
+				if _, err := fmt.Fprintf(a, "%d %d\n", g, i); err != nil {
+					t.Errorf("write failed: %v", err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := a.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dirPath, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	next := make([]int, goroutines)
+	for _, line := range bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n")) {
+		var g, i int
+		if _, err := fmt.Sscanf(string(line), "%d %d", &g, &i); err != nil {
+			t.Fatalf("malformed line %q: %v", line, err)
+		}
+		if i != next[g] {
+			t.Fatalf("goroutine %d: got write %d, want %d", g, i, next[g])
+		}
+		next[g]++
+	}
+	for g, n := range next {
+		if n != writes {
+			t.Errorf("goroutine %d: got %d writes, want %d", g, n, writes)
+		}
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Errorf("second Close returned %v, want nil", err)
+	}
+	if _, err := a.Write([]byte("late\n")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write after Close: got %v, want ErrClosed", err)
+	}
+}
+
+func BenchmarkAsyncWriter(b *testing.B) {
+	a, err := NewAsync(b.TempDir(), 0)
+	if err != nil {
+		b.Fatalf("failed to create AsyncWriter: %v", err)
+	}
+	defer a.Close()
+	line := []byte("benchmark log line with a little bit of payload\n")
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			a.Write(line)
+		}
+	})
+}
+
+// TestAsyncWriteOwned verifies that owned and copied writes keep their order.
+func TestAsyncWriteOwned(t *testing.T) {
+	tempDir := t.TempDir()
+	a, err := NewAsync(tempDir, 4)
+	if err != nil {
+		t.Fatalf("failed to create AsyncWriter: %v", err)
+	}
+	var want strings.Builder
+	for i := 0; i < 100; i++ {
+		line := fmt.Sprintf("line %d\n", i)
+		want.WriteString(line)
+		if i%2 == 0 {
+			_, err = a.WriteOwned([]byte(line))
+		} else {
+			_, err = a.WriteString(line)
+		}
+		if err != nil {
+			t.Fatalf("write %d failed: %v", i, err)
+		}
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tempDir, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read latest.log: %v", err)
+	}
+	if string(data) != want.String() {
+		t.Errorf("content mismatch: got %q, want %q", data, want.String())
+	}
+}
+
+func BenchmarkAsyncWriteOwned(b *testing.B) {
+	a, err := NewAsync(b.TempDir(), 0)
+	if err != nil {
+		b.Fatalf("failed to create AsyncWriter: %v", err)
+	}
+	defer a.Close()
+	line := "benchmark log line with a little bit of payload\n"
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			a.WriteOwned([]byte(line)) // ownership passes on, so each call needs its own slice
+		}
+	})
+}
+
+// TestAsyncWriteContext verifies that WriteContext gives up on a full ring
+// once its context is done.
+func TestAsyncWriteContext(t *testing.T) {
+	release := make(chan struct{})
+	var once sync.Once
+	stall := func(p []byte) []byte { // blocks the drain goroutine on its first write
+		once.Do(func() { <-release })
+		return p
+	}
+	a, err := NewAsync(t.TempDir(), 4, WithFilter(stall))
+	if err != nil {
+		t.Fatalf("failed to create AsyncWriter: %v", err)
+	}
+	defer a.Close()
+	if _, err := a.WriteString("stalls the drain\n"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var err2 error
+	for err2 == nil { // fill the ring until a write has to wait
+		_, err2 = a.WriteContext(ctx, []byte("fills the ring\n"))
+	}
+	if !errors.Is(err2, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err2)
+	}
+	close(release)
+	if n, err := a.WriteContext(context.Background(), []byte("ok\n")); n != 3 || err != nil {
+		t.Errorf("WriteContext: got (%d, %v), want (3, nil)", n, err)
+	}
+}
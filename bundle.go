@@ -0,0 +1,286 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BundleExt is the extension of the per-day bundles created by WithBundling.
+const BundleExt = ".tar.gz"
+
+// WithBundling rolls rotated files last modified more than d ago into one
+// "YYYY-MM-DD.tar.gz" bundle per day of modification, along with their
+// signature sidecars, to save inodes and simplify archival. Files are checked
+// after each rotation and retention; bundles that already exist are extended
+// by appending to them.
+// Days follow local time, or UTC with WithUTC. Bundles are not rotated files,
+// so they're ignored by WithMaxRotations and WithMaxAge. Zero disables bundling.
+func WithBundling(d time.Duration) Option {
+	return func(w *Writer) {
+		w.bundleAfter = d
+	}
+}
+
+// applyBundling moves rotated files older than bundleAfter into day bundles.
+func (w *Writer) applyBundling() error {
+	days, err := w.bundleDays(w.archiving())
+	if err != nil {
+		return err
+	}
+	for day, names := range days {
+		if err := w.bundle(day, names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bundleDays returns the names of the rotated files older than bundleAfter,
+// oldest first, grouped by the day of their bundle. Names in skip are left out.
+func (w *Writer) bundleDays(skip map[string]bool) (map[string][]string, error) {
+	if w.bundleAfter <= 0 {
+		return nil, nil
+	}
+	names, err := w.logFiles()
+	if err != nil {
+		return nil, err
+	}
+	days := make(map[string][]string)
+	for _, name := range names {
+		if isActiveName(name) || skip[name] {
+			continue
+		}
+		fi, err := w.fs.Stat(filepath.Join(w.dirPath, name))
+		if err != nil {
+			return nil, err
+		}
+		if now().Sub(fi.ModTime()) <= w.bundleAfter {
+			continue
+		}
+		t := fi.ModTime()
+		if w.utc {
+			t = t.UTC()
+		}
+		day := t.Format("2006-01-02")
+		days[day] = append(days[day], name)
+	}
+	return days, nil
+}
+
+// bundle adds the rotated files names, and their signatures, to the bundle for
+// day, then removes them. A new bundle is written to a temporary file and
+// renamed into place. An existing one is extended in place with a gzip member
+// holding just the new entries, so each rotation costs as much as the files it
+// adds rather than the whole day's bundle.
+func (w *Writer) bundle(day string, names []string) error {
+	path := filepath.Join(w.dirPath, day+BundleExt)
+	w.sortRotated(names)
+	err := w.appendBundle(path, names)
+	if errors.Is(err, errNoTrailer) {
+		err = w.rewriteBundle(path, names)
+	}
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := removeRotated(w.fs, filepath.Join(w.dirPath, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bundles are a series of gzip members, each holding tar entries without the
+// end-of-archive marker, followed by bundleTrailer, a member holding only the
+// marker. Readers see a single tarball, as gzip readers concatenate members.
+var bundleTrailer = func() []byte {
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	gz.Write(make([]byte, 2*512)) // two zero blocks end a tar archive
+	gz.Close()
+	return b.Bytes()
+}()
+
+// errNoTrailer is returned by appendBundle when there's no bundle at path that
+// ends with bundleTrailer, e.g. because it doesn't exist yet.
+var errNoTrailer = errors.New("bundle doesn't end with the expected trailer")
+
+// appendBundle replaces the trailer of the bundle at path with a member
+// holding names and their signatures, followed by the trailer. If writing
+// fails, the bundle is truncated back to its previous entries.
+func (w *Writer) appendBundle(path string, names []string) error {
+	f, err := w.fs.OpenFile(path, os.O_RDWR|os.O_APPEND, 0)
+	if errors.Is(err, fs.ErrNotExist) {
+		return errNoTrailer
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	end := fi.Size() - int64(len(bundleTrailer))
+	if end < 0 {
+		return errNoTrailer
+	}
+	tail := make([]byte, len(bundleTrailer))
+	if _, err := f.ReadAt(tail, end); err != nil {
+		return err
+	}
+	if !bytes.Equal(tail, bundleTrailer) {
+		return errNoTrailer
+	}
+	if err := f.Truncate(end); err != nil {
+		return err
+	}
+	err = w.writeBundleMember(f, names)
+	if err == nil {
+		_, err = f.Write(bundleTrailer)
+	}
+	if err == nil {
+		return f.Sync()
+	}
+	if terr := f.Truncate(end); terr == nil {
+		f.Write(bundleTrailer) // best effort; the files are kept either way
+	}
+	return err
+}
+
+// rewriteBundle writes the entries of the existing bundle at path, if any,
+// followed by names and their signatures, to a temporary file and renames it
+// into place, so a failure never leaves a damaged bundle behind. It creates
+// new bundles and converts those not ending with bundleTrailer.
+func (w *Writer) rewriteBundle(path string, names []string) error {
+	tmp := path + ".tmp"
+	out, err := w.fs.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	err = w.writeBundle(out, path, names)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = w.fs.Rename(tmp, path)
+	}
+	if err != nil {
+		w.fs.Remove(tmp)
+	}
+	return err
+}
+
+// writeBundle writes the entries of the existing bundle at path, if any,
+// followed by names and their signatures, to out in the bundle format.
+func (w *Writer) writeBundle(out File, path string, names []string) error {
+	if in, err := w.fs.OpenFile(path, os.O_RDONLY, 0); err == nil {
+		gz := gzip.NewWriter(out)
+		tw := tar.NewWriter(gz)
+		err = copyBundle(tw, in)
+		in.Close()
+		if err == nil {
+			err = tw.Flush()
+		}
+		if err == nil {
+			err = gz.Close()
+		}
+		if err != nil {
+			return err
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := w.writeBundleMember(out, names); err != nil {
+		return err
+	}
+	if _, err := out.Write(bundleTrailer); err != nil {
+		return err
+	}
+	return out.Sync()
+}
+
+// writeBundleMember writes names and their signatures to out as a gzip member
+// of tar entries, without the end-of-archive marker.
+func (w *Writer) writeBundleMember(out io.Writer, names []string) error {
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		if err := w.addToBundle(tw, name); err != nil {
+			return err
+		}
+		sig := name + SignatureExt
+		signed, err := fileExists(w.fs, filepath.Join(w.dirPath, sig))
+		if err != nil {
+			return err
+		}
+		if signed {
+			if err := w.addToBundle(tw, sig); err != nil {
+				return err
+			}
+		}
+	}
+	if err := tw.Flush(); err != nil { // not Close, which ends the archive
+		return err
+	}
+	return gz.Close()
+}
+
+// copyBundle copies every entry of the gzipped tarball in to tw.
+func copyBundle(tw *tar.Writer, in io.Reader) error {
+	gr, err := gzip.NewReader(in)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// addToBundle writes the file name from the log directory to tw.
+func (w *Writer) addToBundle(tw *tar.Writer, name string) error {
+	f, err := w.fs.OpenFile(filepath.Join(w.dirPath, name), os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    fi.Size(),
+		ModTime: fi.ModTime(),
+		Format:  tar.FormatPAX,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
@@ -0,0 +1,136 @@
+package rlog
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+	"time"
+)
+
+// TestBundling verifies that old rotated files are rolled into per-day bundles
+// and that existing bundles are extended.
+func TestBundling(t *testing.T) {
+	clock := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	w, m, err := NewMemory(WithMaxFileSize(4), WithBundling(24*time.Hour), WithUTC())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	write := func(s string) {
+		t.Helper()
+		w.WriteString(s)
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		clock = clock.Add(time.Hour)
+	}
+	// Each flush rotates the previous line out of latest.log.
+	write("a\n")
+	write("b\n")
+	write("c\n")
+	clock = clock.Add(48 * time.Hour)
+	write("d\n") // bundles the files rotated on May 1st
+	write("e\n")
+
+	files := m.Files()
+	bundle, ok := files["2024-05-01"+BundleExt]
+	if !ok {
+		t.Fatalf("expected a bundle for 2024-05-01, got files %v", keys(files))
+	}
+	if got := bundleContents(t, bundle); got != "a\nb\nc\n" {
+		t.Errorf("bundle content mismatch: got %q, want %q", got, "a\nb\nc\n")
+	}
+	if len(files) != 3 { // bundle, the rotation of "d", latest.log
+		t.Errorf("expected 3 files, got %v", keys(files))
+	}
+
+	// A straggler from May 1st, e.g. left by another process, joins the existing bundle.
+	mtime := clock
+	clock = time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	if err := writeFile(m, "20240501-230000.000000.log", []byte("late\n"), 0o644); err != nil {
+		t.Fatalf("failed to create straggler: %v", err)
+	}
+	clock = mtime
+	write("f\n")
+	files = m.Files()
+	extended := files["2024-05-01"+BundleExt]
+	if got := bundleContents(t, extended); got != "a\nb\nc\nlate\n" {
+		t.Errorf("extended bundle content mismatch: got %q, want %q", got, "a\nb\nc\nlate\n")
+	}
+	if !bytes.HasPrefix(extended, bundle[:len(bundle)-len(bundleTrailer)]) || !bytes.HasSuffix(extended, bundleTrailer) {
+		t.Errorf("expected the bundle to be extended by appending")
+	}
+}
+
+// TestBundleConversion verifies that a bundle written as a single tarball is
+// rewritten with its entries kept, then extended by appending.
+func TestBundleConversion(t *testing.T) {
+	clock := time.Date(2024, 5, 3, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	m := NewMemoryFS()
+	var old bytes.Buffer
+	gz := gzip.NewWriter(&old)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "old.log", Mode: 0o644, Size: 4})
+	tw.Write([]byte("old\n"))
+	tw.Close()
+	gz.Close()
+	writeFile(m, "2024-05-01"+BundleExt, old.Bytes(), 0o644)
+	clock = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	writeFile(m, "20240501-120000.000000.log", []byte("new\n"), 0o644)
+	clock = time.Date(2024, 5, 3, 10, 0, 0, 0, time.UTC)
+
+	w, err := New(".", WithFS(m), WithMaxFileSize(4), WithBundling(24*time.Hour), WithUTC())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	for _, line := range []string{"x\n", "y\n"} { // the second flush rotates
+		w.WriteString(line)
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	bundle := m.Files()["2024-05-01"+BundleExt]
+	if got := bundleContents(t, bundle); got != "old\nnew\n" {
+		t.Errorf("bundle content mismatch: got %q, want %q", got, "old\nnew\n")
+	}
+	if !bytes.HasSuffix(bundle, bundleTrailer) {
+		t.Errorf("expected the converted bundle to end with the trailer")
+	}
+}
+
+// bundleContents returns the concatenated contents of every entry in a bundle.
+func bundleContents(t *testing.T, data []byte) string {
+	t.Helper()
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to open bundle: %v", err)
+	}
+	tr := tar.NewReader(gr)
+	var buf bytes.Buffer
+	for {
+		if _, err := tr.Next(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("failed to read bundle: %v", err)
+		}
+		io.Copy(&buf, tr)
+	}
+	return buf.String()
+}
+
+func keys(m map[string][]byte) []string {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,246 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrChainBroken is returned by VerifyChain and VerifyChainDir when a line's
+// hash does not match the hash computed from its content and the previous line.
+var ErrChainBroken = errors.New("hash chain broken")
+
+// chainHexLen is the length of the hex encoded hash prefixing each chained line.
+const chainHexLen = sha256.Size * 2
+
+// WithHashChain configures the Writer to prefix every line with a SHA-256 hash
+// of the previous line's hash and the line's content, forming a hash chain:
+//
+//	<64 hex chars> <line>
+//
+// Modifying, inserting, or removing a line breaks the chain from that point on,
+// which VerifyChain and VerifyChainDir detect. The chain continues across
+// rotations and, when reopening an existing "latest.log", resumes from its last
+// line. Partial lines are held until their newline arrives or the Writer is closed.
+//
+// A hash chain provides tamper evidence, not tamper proofing: anyone able to
+// rewrite the files can also recompute the hashes.
+func WithHashChain() Option {
+	return func(w *Writer) {
+		w.hashChain = true
+	}
+}
+
+// appendChained appends p to the buffer as hash chained lines. Bytes after the
+// last newline in p are held in w.chainPartial until the line is completed.
+func (w *Writer) appendChained(p []byte) {
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.chainPartial = append(w.chainPartial, p...)
+			return
+		}
+		line := p[:i]
+		if len(w.chainPartial) > 0 {
+			line = append(w.chainPartial, line...)
+		}
+		w.appendChainLine(line)
+		w.chainPartial = w.chainPartial[:0]
+		p = p[i+1:]
+	}
+}
+
+// appendChainLine appends a single hash chained line, without its trailing
+// newline, to the buffer and advances the chain.
+func (w *Writer) appendChainLine(line []byte) {
+	w.chainPrev = chainHash(w.chainPrev, line)
+	w.buf = hex.AppendEncode(w.buf, w.chainPrev[:])
+	w.buf = append(w.buf, ' ')
+	w.buf = append(w.buf, line...)
+	w.buf = append(w.buf, '\n')
+}
+
+// chainHash returns the hash of line linked to the previous hash prev.
+func chainHash(prev [sha256.Size]byte, line []byte) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write(prev[:])
+	h.Write(line)
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// resumeChain continues the chain from the last line of "latest.log" or, if it
+// holds no chained line, e.g. after a rotation, of the newest rotated file.
+// Without either the chain starts from the zero hash.
+func (w *Writer) resumeChain() error {
+	sum, ok, err := lastChainHash(w.fs, w.activePath())
+	if err != nil || ok {
+		w.chainPrev = sum
+		return err
+	}
+	names, err := w.logFiles()
+	if err != nil {
+		return err
+	}
+	for i := len(names) - 1; i >= 0; i-- {
+		if isActiveName(names[i]) {
+			continue
+		}
+		if sum, ok, err = lastChainHash(w.fs, filepath.Join(w.dirPath, names[i])); ok {
+			w.chainPrev = sum
+		}
+		return err
+	}
+	return nil
+}
+
+// lastChainHash returns the hash prefixing the last complete line of the file
+// at path. ok is false if the file is empty or its last line is not chained.
+func lastChainHash(fsys FS, path string) (sum [sha256.Size]byte, ok bool, err error) {
+	var last []byte
+	if strings.HasSuffix(path, ".gz") {
+		last, err = lastLineGzip(fsys, path)
+	} else {
+		last, err = lastLine(fsys, path)
+	}
+	if err != nil {
+		return sum, false, err
+	}
+	if len(last) <= chainHexLen || last[chainHexLen] != ' ' {
+		return sum, false, nil
+	}
+	if _, err := hex.Decode(sum[:], last[:chainHexLen]); err != nil {
+		return sum, false, nil
+	}
+	return sum, true, nil
+}
+
+// lastLine returns the last complete line of the file at path, without its newline.
+func lastLine(fsys FS, path string) ([]byte, error) {
+	f, err := fsys.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	// Only the tail is needed; lines longer than this fall back to a full read.
+	const tailSize = 64 * 1024
+	off := max(fi.Size()-tailSize, 0)
+	tail := make([]byte, fi.Size()-off)
+	if _, err := f.ReadAt(tail, off); err != nil && err != io.EOF {
+		return nil, err
+	}
+	tail = bytes.TrimSuffix(tail, []byte("\n"))
+	if i := bytes.LastIndexByte(tail, '\n'); i >= 0 {
+		return tail[i+1:], nil
+	} else if off > 0 {
+		if tail, err = readFile(fsys, path); err != nil {
+			return nil, err
+		}
+		tail = bytes.TrimSuffix(tail, []byte("\n"))
+		return tail[bytes.LastIndexByte(tail, '\n')+1:], nil
+	}
+	return tail, nil
+}
+
+// lastLineGzip returns the last complete line of the gzip compressed file at
+// path. A stream truncated by a crash yields the last line before the damage.
+func lastLineGzip(fsys FS, path string) ([]byte, error) {
+	rc, err := openLog(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	var last []byte
+	sc := bufio.NewScanner(rc)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<30)
+	for sc.Scan() {
+		last = append(last[:0], sc.Bytes()...)
+	}
+	if err := sc.Err(); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	return last, nil
+}
+
+// VerifyChain reads hash chained lines from r and verifies each line's hash.
+// If prev is non-nil, the first line must link to it; otherwise the first line
+// is trusted as the anchor of the chain. VerifyChain returns the last hash in r
+// so that consecutive files can be verified by passing it on as prev.
+//
+// A mismatch is reported as an error wrapping ErrChainBroken that includes the
+// offending line number.
+func VerifyChain(r io.Reader, prev []byte) ([]byte, error) {
+	var cur [sha256.Size]byte
+	if prev != nil {
+		if len(prev) != sha256.Size {
+			return nil, fmt.Errorf("previous hash must be %d bytes, got %d", sha256.Size, len(prev))
+		}
+		copy(cur[:], prev)
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<30)
+	lineNum := 0
+	for sc.Scan() {
+		lineNum++
+		line := sc.Bytes()
+		if len(line) < chainHexLen+1 || line[chainHexLen] != ' ' {
+			return nil, fmt.Errorf("line %d: missing hash prefix: %w", lineNum, ErrChainBroken)
+		}
+		var got [sha256.Size]byte
+		if _, err := hex.Decode(got[:], line[:chainHexLen]); err != nil {
+			return nil, fmt.Errorf("line %d: malformed hash prefix: %w", lineNum, ErrChainBroken)
+		}
+		if lineNum > 1 || prev != nil {
+			if want := chainHash(cur, line[chainHexLen+1:]); got != want {
+				return nil, fmt.Errorf("line %d: hash mismatch: %w", lineNum, ErrChainBroken)
+			}
+		}
+		cur = got
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if lineNum == 0 {
+		return prev, nil
+	}
+	return cur[:], nil
+}
+
+// VerifyChainDir verifies the hash chain across all log files in dirPath, from
+// the oldest rotated file through "latest.log". The first line of the oldest
+// file is trusted as the anchor, since its predecessors may have been removed.
+func VerifyChainDir(dirPath string) error {
+	names, err := logFiles(OSFS{}, dirPath)
+	if err != nil {
+		return err
+	}
+	var prev []byte
+	for _, name := range names {
+		rc, err := openLog(OSFS{}, filepath.Join(dirPath, name))
+		if err != nil {
+			return err
+		}
+		prev, err = VerifyChain(rc, prev)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
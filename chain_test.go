@@ -0,0 +1,110 @@
+package rlog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestHashChain verifies that chained logs verify across rotations and restarts,
+// and that tampering is detected.
+func TestHashChain(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithHashChain(), WithMaxFileSize(256))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := w.Write([]byte("first run entry\n")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	if _, err := w.Write([]byte("unterminated")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Reopening resumes the chain from the last line of latest.log.
+	if w, err = New(tempDir, WithHashChain(), WithMaxFileSize(256)); err != nil {
+		t.Fatalf("failed to reopen Writer: %v", err)
+	}
+	if _, err := w.Write([]byte("second run entry\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	names, err := logFiles(OSFS{}, tempDir)
+	if err != nil {
+		t.Fatalf("failed to list log files: %v", err)
+	}
+	if len(names) < 2 {
+		t.Fatalf("expected rotation to produce multiple files, got %v", names)
+	}
+	if err := VerifyChainDir(tempDir); err != nil {
+		t.Fatalf("VerifyChainDir failed on untampered logs: %v", err)
+	}
+
+	// Tamper with the last entry of the oldest file; its first line is the anchor.
+	oldest := filepath.Join(tempDir, names[0])
+	data, err := os.ReadFile(oldest)
+	if err != nil {
+		t.Fatalf("failed to read %q: %v", oldest, err)
+	}
+	i := strings.LastIndex(string(data), "first run entry")
+	if i < 0 || strings.Index(string(data), "first run entry") == i {
+		t.Fatalf("expected several entries in %q, got %q", oldest, string(data))
+	}
+	tampered := string(data[:i]) + "first run ENTRY" + string(data[i+len("first run entry"):])
+	if err := os.WriteFile(oldest, []byte(tampered), 0o644); err != nil {
+		t.Fatalf("failed to write %q: %v", oldest, err)
+	}
+	if err := VerifyChainDir(tempDir); !errors.Is(err, ErrChainBroken) {
+		t.Errorf("expected ErrChainBroken after tampering, got %v", err)
+	}
+}
+
+// TestHashChainAfterRotation verifies that a Writer reopened on an empty
+// "latest.log" resumes the chain from the newest rotated file.
+func TestHashChainAfterRotation(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithHashChain())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	if _, err := w.Write([]byte("first\nsecond\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// The oversized latest.log is rotated on startup, leaving it empty.
+	if w, err = New(tempDir, WithHashChain(), WithMaxFileSize(50)); err != nil {
+		t.Fatalf("failed to reopen Writer: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if w, err = New(tempDir, WithHashChain()); err != nil {
+		t.Fatalf("failed to reopen Writer: %v", err)
+	}
+	if _, err := w.Write([]byte("third\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := VerifyChainDir(tempDir); err != nil {
+		t.Errorf("VerifyChainDir failed after resuming from a rotated file: %v", err)
+	}
+}
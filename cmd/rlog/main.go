@@ -0,0 +1,137 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+// Command rlog provides maintenance utilities for directories written by rlog.
+//
+// Usage:
+//
+//	rlog verify-chain <dir|file>
+//	rlog verify-sig -key <public key file> <dir|file>
+//
+// verify-chain checks the hash chain of logs written with rlog.WithHashChain.
+// Given a directory, every log file is verified in chronological order.
+//
+// verify-sig checks the detached signatures of rotated files written with
+// rlog.WithSigner. The key file holds the hex encoded Ed25519 public key.
+// Given a directory, every rotated file must carry a valid signature.
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Data-Corruption/rlog"
+)
+
+const usage = `usage: rlog <command> [arguments]
+
+commands:
+  verify-chain <dir|file>             verify the hash chain of logs written WithHashChain
+  verify-sig -key <file> <dir|file>   verify signatures of rotated files written WithSigner
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "verify-chain":
+		err = verifyChain(args)
+	case "verify-sig":
+		err = verifySig(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "rlog: unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rlog: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func verifyChain(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("verify-chain takes exactly one path")
+	}
+	fi, err := os.Stat(args[0])
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		err = rlog.VerifyChainDir(args[0])
+	} else {
+		var f *os.File
+		if f, err = os.Open(args[0]); err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = rlog.VerifyChain(f, nil)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println("OK")
+	return nil
+}
+
+func verifySig(args []string) error {
+	fs := flag.NewFlagSet("verify-sig", flag.ContinueOnError)
+	keyPath := fs.String("key", "", "file holding the hex encoded Ed25519 public key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *keyPath == "" || fs.NArg() != 1 {
+		return fmt.Errorf("verify-sig takes -key and exactly one path")
+	}
+	encoded, err := os.ReadFile(*keyPath)
+	if err != nil {
+		return err
+	}
+	raw, err := hex.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil {
+		return fmt.Errorf("malformed public key: %v", err)
+	}
+	pub := ed25519.PublicKey(raw)
+	path := fs.Arg(0)
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		if err := rlog.VerifyFile(path, pub); err != nil {
+			return err
+		}
+		fmt.Println("OK")
+		return nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	verified := 0
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, "latest.") ||
+			!(strings.HasSuffix(name, ".log") || strings.HasSuffix(name, ".log.gz")) {
+			continue
+		}
+		if err := rlog.VerifyFile(filepath.Join(path, name), pub); err != nil {
+			return err
+		}
+		verified++
+	}
+	fmt.Printf("OK (%d files)\n", verified)
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// WithCompressionLevel sets the gzip level used by WithStreamCompression, from
+// gzip.HuffmanOnly through gzip.BestCompression. Lower levels use less CPU on
+// the flush path. The default is gzip.DefaultCompression.
+func WithCompressionLevel(level int) Option {
+	return func(w *Writer) {
+		w.compressLevel = level
+	}
+}
+
+// CompressArchiver returns an Archiver that gzips a rotated file at the given
+// level to path + ".gz", removes the original, and passes the compressed path
+// on to next, if non-nil. A signature sidecar is renamed along with the file
+// and still covers the uncompressed contents, which VerifyFile accounts for.
+//
+// Compression runs on the Writer's archive workers rather than the write path,
+// so WithArchiveWorkers bounds how many cores a burst of rotations can occupy.
+// Files that are already compressed are passed on untouched. The compressed
+// file counts as being archived until next succeeds, so retries after next
+// fails pass it on again rather than losing it to retention.
+//
+//	rlog.WithArchiver(rlog.CompressArchiver(gzip.BestSpeed, rlog.UploadArchiver(u)))
+func CompressArchiver(level int, next Archiver) Archiver {
+	return ArchiverFunc(func(ctx context.Context, path string) error {
+		if !strings.HasSuffix(path, ".gz") {
+			gzPath := path + ".gz"
+			if err := compressRotated(ctx, path, gzPath, level); err != nil {
+				return err
+			}
+			path = gzPath
+		}
+		if next == nil {
+			return nil
+		}
+		return next.Archive(ctx, path)
+	})
+}
+
+// compressRotated compresses the rotated file at path to gzPath, moving its
+// signature sidecar along. If path is gone but gzPath exists, an earlier
+// attempt already compressed it and only next failed, so there's nothing to do.
+func compressRotated(ctx context.Context, path, gzPath string, level int) error {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		if _, err := os.Stat(gzPath); err == nil {
+			return nil
+		}
+	}
+	if err := compressFile(ctx, path, gzPath, level); err != nil {
+		return err
+	}
+	if err := os.Rename(path+SignatureExt, gzPath+SignatureExt); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// compressFile gzips src to dst through a temporary file, so dst only ever
+// appears complete.
+func compressFile(ctx context.Context, src, dst string, level int) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	err = func() error {
+		zw, err := gzip.NewWriterLevel(out, level)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(zw, ctxReader{ctx, in}); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		return out.Sync()
+	}()
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, dst)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+// ctxReader stops reading once its context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,175 @@
+package rlog
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestStreamCompression verifies that compressed output round-trips across flushes,
+// reopens, and rotations.
+func TestStreamCompression(t *testing.T) {
+	tempDir := t.TempDir()
+	var want strings.Builder
+	for run := 0; run < 2; run++ { // the second run appends a new gzip member
+		w, err := New(tempDir, WithStreamCompression(), WithMaxFileSize(200))
+		if err != nil {
+			t.Fatalf("failed to create Writer: %v", err)
+		}
+		for i := 0; i < 20; i++ {
+			line := "compressed log line number something\n"
+			want.WriteString(line)
+			if _, err := w.Write([]byte(line)); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush failed: %v", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+
+	names, err := logFiles(OSFS{}, tempDir)
+	if err != nil {
+		t.Fatalf("failed to list log files: %v", err)
+	}
+	if len(names) < 2 || names[len(names)-1] != "latest.log.gz" {
+		t.Fatalf("expected rotated files and latest.log.gz, got %v", names)
+	}
+	var got strings.Builder
+	for _, name := range names {
+		f, err := os.Open(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("failed to open %q: %v", name, err)
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			if err == io.EOF {
+				f.Close()
+				continue // empty active file
+			}
+			t.Fatalf("failed to read gzip header of %q: %v", name, err)
+		}
+		if _, err := io.Copy(&got, zr); err != nil {
+			t.Fatalf("failed to decompress %q: %v", name, err)
+		}
+		f.Close()
+	}
+	if got.String() != want.String() {
+		t.Errorf("decompressed content mismatch: got %d bytes, want %d", got.Len(), want.Len())
+	}
+}
+
+// TestCompressArchiver verifies that rotated files are compressed in the
+// background and that their signatures still verify.
+func TestCompressArchiver(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithSigner(priv), WithMaxFileSize(10),
+		WithArchiver(CompressArchiver(gzip.BestSpeed, nil)))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for _, msg := range []string{"abcdef\n", "ghijkl\n"} { // second flush rotates
+		w.WriteString(msg)
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	waitArchived(t, w)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(tempDir, "*.log*"))
+	var gzPath string
+	for _, m := range matches {
+		switch {
+		case strings.HasSuffix(m, ".log.gz"):
+			gzPath = m
+		case strings.HasSuffix(m, "latest.log"), strings.HasSuffix(m, ".log.gz"+SignatureExt):
+		default:
+			t.Errorf("unexpected file %s", filepath.Base(m))
+		}
+	}
+	if gzPath == "" {
+		t.Fatalf("expected a compressed rotation, got %v", matches)
+	}
+	rc, err := openLog(OSFS{}, gzPath)
+	if err != nil {
+		t.Fatalf("failed to open compressed rotation: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "abcdef\n" {
+		t.Errorf("content mismatch: got %q, want %q", data, "abcdef\n")
+	}
+	if err := VerifyFile(gzPath, pub); err != nil {
+		t.Errorf("expected signature to verify after compression: %v", err)
+	}
+}
+
+// TestCompressArchiverRetry verifies that a retry after next fails passes the
+// compressed file on again, and that retention leaves it alone meanwhile.
+func TestCompressArchiverRetry(t *testing.T) {
+	started := make(chan string, 1)
+	release := make(chan struct{})
+	archived := make(chan string, 1)
+	attempts := 0
+	next := ArchiverFunc(func(ctx context.Context, path string) error {
+		attempts++
+		if attempts == 1 {
+			started <- path
+			<-release
+			return errors.New("upload failed")
+		}
+		archived <- path
+		return nil
+	})
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithMaxFileSize(10), WithArchiveRetry(2, time.Millisecond),
+		WithArchiver(CompressArchiver(gzip.BestSpeed, next)))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	for _, msg := range []string{"abcdef\n", "ghijkl\n"} { // second flush rotates
+		w.WriteString(msg)
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	gzPath := <-started
+	if n, err := w.Purge(0); err != nil || n != 0 {
+		t.Errorf("Purge(0) returned %d, %v; want 0, nil", n, err)
+	}
+	close(release)
+	select {
+	case path := <-archived:
+		if path != gzPath {
+			t.Errorf("expected the retry to pass on %q, got %q", gzPath, path)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the retry")
+	}
+	rc, err := openLog(OSFS{}, gzPath)
+	if err != nil {
+		t.Fatalf("failed to open compressed rotation: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "abcdef\n" {
+		t.Errorf("content mismatch: got %q, want %q", data, "abcdef\n")
+	}
+}
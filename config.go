@@ -0,0 +1,196 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the Writer settings operators most often tune, so they can be
+// loaded from the environment or a JSON file instead of being compiled in.
+// Zero values leave the corresponding default in place.
+//
+// In JSON, sizes may be numbers of bytes or strings with a KB, MB, or GB
+// suffix (powers of 1024), and durations may be strings such as "15s" or
+// numbers of nanoseconds:
+//
+//	{"dir": "logs", "max_file_size": "64MB", "max_age": "168h", "max_rotations": 10}
+type Config struct {
+	Dir               string        `json:"dir"`
+	MaxFileSize       int64         `json:"max_file_size"`
+	MaxBufSize        int           `json:"max_buf_size"`
+	MaxBufAge         time.Duration `json:"max_buf_age"`
+	FlushOnNewline    bool          `json:"flush_on_newline"`
+	MkdirAll          bool          `json:"mkdir_all"`
+	NoSync            bool          `json:"no_sync"`
+	StreamCompression bool          `json:"stream_compression"`
+	CompressionLevel  int           `json:"compression_level"`
+	MaxRotations      int           `json:"max_rotations"`
+	MaxAge            time.Duration `json:"max_age"`
+}
+
+// configEnv maps environment variables read by FromEnv to Config fields.
+var configEnv = []struct {
+	name string
+	set  func(c *Config, v string) error
+}{
+	{"RLOG_DIR", func(c *Config, v string) error { c.Dir = v; return nil }},
+	{"RLOG_MAX_FILE_SIZE", func(c *Config, v string) (err error) { c.MaxFileSize, err = parseSize(v); return }},
+	{"RLOG_MAX_BUF_SIZE", func(c *Config, v string) error {
+		n, err := parseSize(v)
+		c.MaxBufSize = int(n)
+		return err
+	}},
+	{"RLOG_MAX_BUF_AGE", func(c *Config, v string) (err error) { c.MaxBufAge, err = time.ParseDuration(v); return }},
+	{"RLOG_FLUSH_ON_NEWLINE", func(c *Config, v string) (err error) { c.FlushOnNewline, err = strconv.ParseBool(v); return }},
+	{"RLOG_MKDIR_ALL", func(c *Config, v string) (err error) { c.MkdirAll, err = strconv.ParseBool(v); return }},
+	{"RLOG_NO_SYNC", func(c *Config, v string) (err error) { c.NoSync, err = strconv.ParseBool(v); return }},
+	{"RLOG_STREAM_COMPRESSION", func(c *Config, v string) (err error) { c.StreamCompression, err = strconv.ParseBool(v); return }},
+	{"RLOG_COMPRESSION_LEVEL", func(c *Config, v string) (err error) { c.CompressionLevel, err = strconv.Atoi(v); return }},
+	{"RLOG_MAX_ROTATIONS", func(c *Config, v string) (err error) { c.MaxRotations, err = strconv.Atoi(v); return }},
+	{"RLOG_MAX_AGE", func(c *Config, v string) (err error) { c.MaxAge, err = time.ParseDuration(v); return }},
+}
+
+// FromEnv returns a Config read from the environment variables RLOG_DIR,
+// RLOG_MAX_FILE_SIZE, RLOG_MAX_BUF_SIZE, RLOG_MAX_BUF_AGE,
+// RLOG_FLUSH_ON_NEWLINE, RLOG_MKDIR_ALL, RLOG_NO_SYNC, RLOG_STREAM_COMPRESSION,
+// RLOG_COMPRESSION_LEVEL, RLOG_MAX_ROTATIONS, and RLOG_MAX_AGE. Unset or empty
+// variables are left zero. Values use the same formats as JSON strings.
+func FromEnv() (Config, error) {
+	var c Config
+	for _, e := range configEnv {
+		v := strings.TrimSpace(os.Getenv(e.name))
+		if v == "" {
+			continue
+		}
+		if err := e.set(&c, v); err != nil {
+			return Config{}, fmt.Errorf("invalid %s %q: %w", e.name, v, err)
+		}
+	}
+	return c, nil
+}
+
+// UnmarshalJSON decodes c from JSON, accepting sizes and durations as strings.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type plain Config // without this method
+	var raw struct {
+		*plain
+		MaxFileSize json.RawMessage `json:"max_file_size"`
+		MaxBufSize  json.RawMessage `json:"max_buf_size"`
+		MaxBufAge   json.RawMessage `json:"max_buf_age"`
+		MaxAge      json.RawMessage `json:"max_age"`
+	}
+	raw.plain = (*plain)(c)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	if c.MaxFileSize, err = jsonSize(raw.MaxFileSize, c.MaxFileSize); err != nil {
+		return fmt.Errorf("invalid max_file_size: %w", err)
+	}
+	bufSize, err := jsonSize(raw.MaxBufSize, int64(c.MaxBufSize))
+	if err != nil {
+		return fmt.Errorf("invalid max_buf_size: %w", err)
+	}
+	c.MaxBufSize = int(bufSize)
+	if c.MaxBufAge, err = jsonDuration(raw.MaxBufAge, c.MaxBufAge); err != nil {
+		return fmt.Errorf("invalid max_buf_age: %w", err)
+	}
+	if c.MaxAge, err = jsonDuration(raw.MaxAge, c.MaxAge); err != nil {
+		return fmt.Errorf("invalid max_age: %w", err)
+	}
+	return nil
+}
+
+// New creates a Writer for c.Dir with the settings in c, followed by opts.
+func (c Config) New(opts ...Option) (*Writer, error) {
+	var cfg []Option
+	if c.MaxFileSize != 0 {
+		cfg = append(cfg, WithMaxFileSize(c.MaxFileSize))
+	}
+	if c.MaxBufSize != 0 {
+		cfg = append(cfg, WithMaxBufSize(c.MaxBufSize))
+	}
+	if c.MaxBufAge != 0 {
+		cfg = append(cfg, WithMaxBufAge(c.MaxBufAge))
+	}
+	if c.FlushOnNewline {
+		cfg = append(cfg, WithFlushOnNewline())
+	}
+	if c.MkdirAll {
+		cfg = append(cfg, WithMkdirAll())
+	}
+	if c.NoSync {
+		cfg = append(cfg, WithNoSync())
+	}
+	if c.StreamCompression {
+		cfg = append(cfg, WithStreamCompression())
+	}
+	if c.CompressionLevel != 0 {
+		cfg = append(cfg, WithCompressionLevel(c.CompressionLevel))
+	}
+	if c.MaxRotations != 0 {
+		cfg = append(cfg, WithMaxRotations(c.MaxRotations))
+	}
+	if c.MaxAge != 0 {
+		cfg = append(cfg, WithMaxAge(c.MaxAge))
+	}
+	return New(c.Dir, append(cfg, opts...)...)
+}
+
+// parseSize parses a number of bytes with an optional KB, MB, or GB suffix.
+func parseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	mult := int64(1)
+	for _, u := range []struct {
+		suffix string
+		mult   int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"B", 1}} {
+		if strings.HasSuffix(s, u.suffix) {
+			s, mult = strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * mult, nil
+}
+
+// jsonSize decodes a size given as a number or a string, returning def if raw
+// is empty.
+func jsonSize(raw json.RawMessage, def int64) (int64, error) {
+	if len(raw) == 0 {
+		return def, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return parseSize(s)
+	}
+	var n int64
+	err := json.Unmarshal(raw, &n)
+	return n, err
+}
+
+// jsonDuration decodes a duration given as a string or a number of
+// nanoseconds, returning def if raw is empty.
+func jsonDuration(raw json.RawMessage, def time.Duration) (time.Duration, error) {
+	if len(raw) == 0 {
+		return def, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return time.ParseDuration(s)
+	}
+	var n int64
+	err := json.Unmarshal(raw, &n)
+	return time.Duration(n), err
+}
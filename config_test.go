@@ -0,0 +1,55 @@
+package rlog
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestConfigJSON verifies that sizes and durations decode from strings or
+// numbers.
+func TestConfigJSON(t *testing.T) {
+	var c Config
+	err := json.Unmarshal([]byte(`{"dir": "logs", "max_file_size": "64MB", "max_buf_size": 8192,
+		"max_buf_age": "5s", "max_age": 3600000000000, "no_sync": true, "max_rotations": 3}`), &c)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	want := Config{Dir: "logs", MaxFileSize: 64 << 20, MaxBufSize: 8192, MaxBufAge: 5 * time.Second,
+		MaxAge: time.Hour, NoSync: true, MaxRotations: 3}
+	if c != want {
+		t.Errorf("expected %+v, got %+v", want, c)
+	}
+	if err := json.Unmarshal([]byte(`{"max_buf_age": "soon"}`), &c); err == nil {
+		t.Errorf("expected an error for an invalid duration")
+	}
+}
+
+// TestFromEnv verifies that FromEnv reads settings and that Config.New
+// applies them.
+func TestFromEnv(t *testing.T) {
+	dirPath := filepath.Join(t.TempDir(), "logs")
+	t.Setenv("RLOG_DIR", dirPath)
+	t.Setenv("RLOG_MKDIR_ALL", "true")
+	t.Setenv("RLOG_MAX_FILE_SIZE", "2 KB")
+	t.Setenv("RLOG_MAX_BUF_AGE", "1m")
+	t.Setenv("RLOG_MAX_ROTATIONS", "4")
+	c, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv failed: %v", err)
+	}
+	w, err := c.New()
+	if err != nil {
+		t.Fatalf("Config.New failed: %v", err)
+	}
+	defer w.Close()
+	if w.dirPath != dirPath || w.maxFileSize != 2048 || w.maxBufAge != time.Minute || w.maxRotations != 4 || w.mu == nil {
+		t.Errorf("settings not applied: %+v", c)
+	}
+
+	t.Setenv("RLOG_NO_SYNC", "maybe")
+	if _, err := FromEnv(); err == nil {
+		t.Errorf("expected an error for an invalid RLOG_NO_SYNC")
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSlowFlush is reported to the error handler when writing out a flush takes
+// longer than the deadline set by WithFlushDeadline.
+var ErrSlowFlush = errors.New("flush exceeded deadline")
+
+// WithFlushDeadline sets how long writing out a flush, including rotation and
+// fsync, may take before the disk is considered stalled. A flush can't be
+// interrupted midway, so when the deadline passes the flush keeps going, but
+// it's counted in Stats and reported to the error handler right away, while
+// the stall is still in progress. With WithFallback, once the slow flush
+// finishes, later flushes go to the fallback until a probe of the log file
+// succeeds in time. Zero, the default, disables the deadline.
+func WithFlushDeadline(d time.Duration) Option {
+	return func(w *Writer) {
+		w.flushDeadline = d
+	}
+}
+
+// timedWriteOut calls writeOut, reporting whether it exceeded the flush deadline.
+func (w *Writer) timedWriteOut(buf []byte, earlyRotate bool) (bool, error) {
+	if w.flushDeadline <= 0 {
+		return false, w.writeOut(buf, earlyRotate)
+	}
+	d := w.flushDeadline
+	watchdog := time.AfterFunc(d, func() {
+		w.stats.slowFlushes.Add(1)
+		w.reportError(fmt.Errorf("%w: writing %d bytes to %s took over %v", ErrSlowFlush, len(buf), w.activePath(), d))
+	})
+	err := w.writeOut(buf, earlyRotate)
+	return !watchdog.Stop(), err
+}
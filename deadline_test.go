@@ -0,0 +1,56 @@
+package rlog
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestFlushDeadline verifies that a flush stalled past the deadline is counted
+// and reported, and that later flushes then go to the fallback.
+func TestFlushDeadline(t *testing.T) {
+	defer func(d time.Duration) { fallbackProbe = d }(fallbackProbe)
+	fallbackProbe = time.Hour
+
+	fsys := &slowSyncFS{Memory: NewMemoryFS()}
+	var fallback bytes.Buffer
+	var mu sync.Mutex
+	var reported []error
+	w, err := New(".", WithFS(fsys), WithFallback(&fallback), WithFlushDeadline(time.Millisecond),
+		WithErrorHandler(func(err error) {
+			mu.Lock()
+			reported = append(reported, err)
+			mu.Unlock()
+		}))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	w.WriteString("one\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	w.WriteString("two\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if n := w.Stats().SlowFlushes; n != 1 {
+		t.Errorf("expected 1 slow flush, got %d", n)
+	}
+	mu.Lock()
+	if len(reported) == 0 || !errors.Is(reported[0], ErrSlowFlush) {
+		t.Errorf("expected ErrSlowFlush to be reported, got %v", reported)
+	}
+	mu.Unlock()
+	if err := w.HealthCheck(); !errors.Is(err, ErrFallback) {
+		t.Errorf("expected ErrFallback, got %v", err)
+	}
+	w.Close()
+	if got, _ := fsys.ReadFile("latest.log"); string(got) != "one\n" {
+		t.Errorf("expected log file %q, got %q", "one\n", got)
+	}
+	if got := fallback.String(); got != "two\n" {
+		t.Errorf("expected fallback %q, got %q", "two\n", got)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// WithDedup collapses runs of identical consecutive lines into the first line
+// followed by "last message repeated N times". The summary is written when a
+// different line arrives or when the buffer is flushed, so a tight loop logging
+// the same error costs one line per flush instead of one per iteration.
+//
+// Lines are compared byte for byte, so lines that embed a timestamp are only
+// collapsed if the timestamps match. Bytes after the last newline of a write
+// are held until the line is completed or the Writer is closed.
+func WithDedup() Option {
+	return func(w *Writer) {
+		w.dedup = true
+	}
+}
+
+// appendBuf appends p to the buffer, timestamping and hash chaining it if
+// enabled.
+func (w *Writer) appendBuf(p []byte) {
+	if w.stampLayout != "" {
+		p = w.stamp(p)
+	}
+	if w.hashChain {
+		w.appendChained(p)
+	} else {
+		w.buf = append(w.buf, p...)
+	}
+}
+
+// appendDedup appends the lines in p to the buffer, counting rather than
+// appending lines that repeat the previous one.
+func (w *Writer) appendDedup(p []byte) {
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.dedupPartial = append(w.dedupPartial, p...)
+			return
+		}
+		line := p[:i+1]
+		if len(w.dedupPartial) > 0 {
+			line = append(w.dedupPartial, line...)
+		}
+		if w.dedupLast != nil && bytes.Equal(line, w.dedupLast) {
+			w.dedupCount++
+		} else {
+			w.dedupRepeat()
+			w.appendBuf(line)
+			w.dedupLast = append(w.dedupLast[:0], line...)
+		}
+		w.dedupPartial = w.dedupPartial[:0]
+		p = p[i+1:]
+	}
+}
+
+// dedupRepeat appends a summary of suppressed repeats, if any.
+func (w *Writer) dedupRepeat() {
+	if w.dedupCount == 0 {
+		return
+	}
+	summary := append([]byte("last message repeated "), strconv.Itoa(w.dedupCount)...)
+	w.appendBuf(append(summary, " times\n"...))
+	w.dedupCount = 0
+}
@@ -0,0 +1,33 @@
+package rlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDedup verifies that repeated lines are summarized on change and on close,
+// including lines split across writes.
+func TestDedup(t *testing.T) {
+	dirPath := t.TempDir()
+	w, err := New(dirPath, WithDedup())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for _, s := range []string{"retry\n", "retry\nre", "try\n", "ok\n", "ok\n", "ok\nend"} {
+		if _, err := w.WriteString(s); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dirPath, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	want := "retry\nlast message repeated 2 times\nok\nlast message repeated 2 times\nend"
+	if string(data) != want {
+		t.Errorf("content mismatch:\ngot  %q\nwant %q", data, want)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"io/fs"
+	"os"
+	"unsafe"
+)
+
+// directAlign is the offset, length, and memory alignment used for direct I/O.
+// It covers the logical block size of practically all devices.
+const directAlign = 4096
+
+// WithDirectIO writes the active log file with direct I/O, bypassing the page
+// cache, so that heavy logging doesn't evict pages other processes, such as a
+// database, depend on. On Linux the file is opened with O_DIRECT; on macOS
+// caching is disabled with F_NOCACHE.
+//
+// Direct I/O is best effort: it's skipped with a custom FS, on filesystems
+// that don't support it, and on other platforms. Each flush is a synchronous
+// write to the device, so pair it with a large WithMaxBufSize.
+func WithDirectIO() Option {
+	return func(w *Writer) {
+		w.directIO = true
+	}
+}
+
+// directFile appends to a file opened for direct I/O, which only accepts
+// aligned writes. Every write covers whole blocks from the one holding the end
+// of the file, padded with zeros and then truncated back to the real size, so
+// the partial last block is kept in memory and rewritten by the next write.
+type directFile struct {
+	*os.File
+	size int64  // logical size of the file
+	buf  []byte // aligned scratch; buf[:size%directAlign] is the partial last block
+}
+
+// newDirectFile wraps f, which must be opened for direct I/O without O_APPEND.
+func newDirectFile(f *os.File) (*directFile, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	d := &directFile{File: f}
+	return d, d.reload(fi.Size())
+}
+
+// reload resets the file's logical size and rereads its partial last block.
+func (d *directFile) reload(size int64) error {
+	d.size = size
+	n := int(size % directAlign)
+	d.buf = growAligned(d.buf, 0, directAlign)
+	if n == 0 {
+		return nil
+	}
+	// Reads through the direct handle have the same alignment rules, so the
+	// block is read through a regular one.
+	r, err := os.Open(d.Name())
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = r.ReadAt(d.buf[:n], size-int64(n))
+	return err
+}
+
+func (d *directFile) Write(p []byte) (int, error) {
+	tail := int(d.size % directAlign)
+	end := tail + len(p)
+	padded := (end + directAlign - 1) &^ (directAlign - 1)
+	d.buf = growAligned(d.buf, tail, padded)
+	b := d.buf[:padded]
+	copy(b[tail:], p)
+	clear(b[end:])
+	if _, err := d.File.WriteAt(b, d.size-int64(tail)); err != nil {
+		d.File.Truncate(d.size) // drop any padding or partial write; p is retried whole
+		return 0, err
+	}
+	if end != padded {
+		if err := d.File.Truncate(d.size + int64(len(p))); err != nil {
+			return 0, err
+		}
+	}
+	d.size += int64(len(p))
+	if keep := int(d.size % directAlign); keep > 0 {
+		copy(d.buf, b[padded-directAlign:padded-directAlign+keep])
+	}
+	return len(p), nil
+}
+
+// Stat reconciles the logical size with the file's, so that truncation or
+// appends by others, e.g. copy-truncate rotation, are picked up.
+func (d *directFile) Stat() (fs.FileInfo, error) {
+	fi, err := d.File.Stat()
+	if err == nil && fi.Size() != d.size {
+		err = d.reload(fi.Size())
+	}
+	return fi, err
+}
+
+func (d *directFile) Truncate(size int64) error {
+	if err := d.File.Truncate(size); err != nil {
+		return err
+	}
+	return d.reload(size)
+}
+
+// growAligned returns buf with room for n bytes, keeping its first keep bytes.
+// New buffers are aligned to directAlign in memory.
+func growAligned(buf []byte, keep, n int) []byte {
+	if cap(buf) >= n {
+		return buf[:cap(buf)]
+	}
+	n = max(n, 2*cap(buf))
+	b := make([]byte, n+directAlign)
+	off := -int(uintptr(unsafe.Pointer(&b[0]))) & (directAlign - 1)
+	b = b[off : off+n : off+n]
+	copy(b, buf[:keep])
+	return b
+}
@@ -0,0 +1,19 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build darwin
+
+package rlog
+
+import (
+	"os"
+	"syscall"
+)
+
+// directIO turns off caching for f with F_NOCACHE, which has no alignment
+// requirements, so f is used as is. Errors are ignored as with preallocate.
+func directIO(f *os.File) File {
+	syscall.Syscall(syscall.SYS_FCNTL, f.Fd(), syscall.F_NOCACHE, 1)
+	return f
+}
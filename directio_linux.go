@@ -0,0 +1,28 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package rlog
+
+import (
+	"os"
+	"syscall"
+)
+
+// directIO reopens f with O_DIRECT, closing f on success. On failure, e.g. on
+// filesystems that reject O_DIRECT with EINVAL, f is returned unchanged.
+func directIO(f *os.File) File {
+	df, err := os.OpenFile(f.Name(), os.O_WRONLY|syscall.O_DIRECT, 0)
+	if err != nil {
+		return f
+	}
+	d, err := newDirectFile(df)
+	if err != nil {
+		df.Close()
+		return f
+	}
+	f.Close()
+	return d
+}
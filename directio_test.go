@@ -0,0 +1,102 @@
+package rlog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestDirectIO verifies that direct I/O writes produce the same file contents
+// as buffered ones across unaligned flushes, reopening, and copy-truncate
+// rotation.
+func TestDirectIO(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "latest.log")
+	var want bytes.Buffer
+	write := func(w *Writer, lines int) {
+		t.Helper()
+		for i := 0; i < lines; i++ {
+			line := fmt.Sprintf("%d %s\n", i, bytes.Repeat([]byte("x"), i*397%5000))
+			want.WriteString(line)
+			if _, err := w.Write([]byte(line)); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+		}
+	}
+	check := func() {
+		t.Helper()
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read log file: %v", err)
+		}
+		if !bytes.Equal(got, want.Bytes()) {
+			t.Fatalf("log content mismatch: got %d bytes, want %d", len(got), want.Len())
+		}
+	}
+
+	w, err := New(tempDir, WithDirectIO(), WithFlushOnNewline())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	if _, ok := w.file.(*directFile); !ok && runtime.GOOS == "linux" {
+		t.Log("O_DIRECT unsupported by the temp filesystem; testing buffered fallback")
+	}
+	write(w, 20)
+	check()
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Reopening must pick up the partial last block.
+	w, err = New(tempDir, WithDirectIO(), WithFlushOnNewline())
+	if err != nil {
+		t.Fatalf("failed to reopen Writer: %v", err)
+	}
+	write(w, 20)
+	check()
+
+	// Appends by someone else must be reconciled before the next write.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("failed to open log file: %v", err)
+	}
+	f.WriteString("external\n")
+	f.Close()
+	want.WriteString("external\n")
+	write(w, 5)
+	check()
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Copy-truncate rotation truncates through another handle.
+	w, err = New(tempDir, WithDirectIO(), WithFlushOnNewline(), WithRotationStrategy(CopyTruncateRotation), WithMaxFileSize(20000))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	write(w, 30)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	names, err := logFiles(OSFS{}, tempDir)
+	if err != nil {
+		t.Fatalf("failed to list log files: %v", err)
+	}
+	if len(names) < 3 {
+		t.Errorf("expected several rotations, got files %v", names)
+	}
+	var got []byte
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		got = append(got, data...)
+	}
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("log content mismatch across rotations: got %d bytes, want %d", len(got), want.Len())
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package rlog
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// syncDirEntries fsyncs the directory name. Filesystems that can't sync
+// directories report EINVAL or ENOTSUP, which is ignored as there's nothing
+// more to be done.
+func syncDirEntries(name string) error {
+	d, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if cerr := d.Close(); err == nil {
+		err = cerr
+	}
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTSUP) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,37 @@
+package rlog
+
+import (
+	"reflect"
+	"testing"
+)
+
+// dirSyncFS is an FS that records the directories synced through SyncDir.
+type dirSyncFS struct {
+	*Memory
+	synced []string
+}
+
+func (s *dirSyncFS) SyncDir(name string) error {
+	s.synced = append(s.synced, name)
+	return nil
+}
+
+// TestSyncDir verifies that the log directory is synced after each rotation,
+// and that OSFS can sync a real directory.
+func TestSyncDir(t *testing.T) {
+	fsys := &dirSyncFS{Memory: NewMemoryFS()}
+	w, err := New(".", WithFS(fsys), WithMaxFileSize(4), WithFlushOnNewline())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	w.WriteString("a\n")
+	w.WriteString("b\n") // rotates
+	w.WriteString("c\n") // rotates
+	if want := []string{".", "."}; !reflect.DeepEqual(fsys.synced, want) {
+		t.Errorf("expected directory syncs %v, got %v", want, fsys.synced)
+	}
+	if err := (OSFS{}).SyncDir(t.TempDir()); err != nil {
+		t.Errorf("SyncDir failed: %v", err)
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build windows
+
+package rlog
+
+// syncDirEntries is a no-op on Windows, where directories can't be flushed
+// and NTFS journals metadata changes itself.
+func syncDirEntries(name string) error { return nil }
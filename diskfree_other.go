@@ -0,0 +1,17 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build !linux && !darwin && !windows
+
+package rlog
+
+import "syscall"
+
+// diskFree is unsupported on this platform.
+func diskFree(path string) (uint64, error) {
+	return 0, errDiskFreeUnsupported
+}
+
+// diskFullErrnos are the errors diskFull reports as ErrDiskFull.
+var diskFullErrnos = []syscall.Errno{syscall.ENOSPC}
@@ -0,0 +1,22 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+
+package rlog
+
+import "syscall"
+
+// diskFree returns the bytes available to unprivileged users on the
+// filesystem containing path.
+func diskFree(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
+
+// diskFullErrnos are the errors diskFull reports as ErrDiskFull.
+var diskFullErrnos = []syscall.Errno{syscall.ENOSPC, syscall.EDQUOT}
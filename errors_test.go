@@ -0,0 +1,77 @@
+package rlog
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestTypedErrors verifies that failures wrap the matching sentinel errors
+// along with their causes.
+func TestTypedErrors(t *testing.T) {
+	t.Run("disk full", func(t *testing.T) {
+		fsys := &flakyFS{Memory: NewMemoryFS()}
+		w, err := New(".", WithFS(fsys))
+		if err != nil {
+			t.Fatalf("failed to create Writer: %v", err)
+		}
+		defer w.Close()
+		fsys.full = true
+		w.WriteString("hello\n")
+		err = w.Flush()
+		if !errors.Is(err, ErrDiskFull) || !errors.Is(err, syscall.ENOSPC) {
+			t.Errorf("expected ErrDiskFull wrapping ENOSPC, got %v", err)
+		}
+		if errors.Is(err, ErrRotateFailed) {
+			t.Errorf("expected a write failure, got %v", err)
+		}
+	})
+
+	t.Run("rotate failed", func(t *testing.T) {
+		fsys := &flakyFS{Memory: NewMemoryFS()}
+		w, err := New(".", WithFS(fsys), WithMaxFileSize(8), WithRotationStrategy(CopyTruncateRotation))
+		if err != nil {
+			t.Fatalf("failed to create Writer: %v", err)
+		}
+		defer w.Close()
+		w.WriteString("hello\n")
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		fsys.full = true // the rotated copy can't be written
+		w.WriteString("world\n")
+		err = w.Flush()
+		if !errors.Is(err, ErrRotateFailed) || !errors.Is(err, ErrDiskFull) {
+			t.Errorf("expected ErrRotateFailed and ErrDiskFull, got %v", err)
+		}
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		w, _, err := NewMemory(WithRateLimit(1, 1))
+		if err != nil {
+			t.Fatalf("failed to create Writer: %v", err)
+		}
+		defer w.Close()
+		events := w.Events()
+		w.WriteString("too long for the burst\n")
+		select {
+		case ev := <-events:
+			if ev.Kind != EventDrop || !errors.Is(ev.Err, ErrDropped) {
+				t.Errorf("expected a drop event wrapping ErrDropped, got %+v", ev)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected a drop event")
+		}
+
+		w.mu.Lock() // keeps the next write from being buffered
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = w.WriteContext(ctx, []byte("x"))
+		w.mu.Unlock()
+		if !errors.Is(err, ErrDropped) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected ErrDropped wrapping the context error, got %v", err)
+		}
+	})
+}
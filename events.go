@@ -0,0 +1,99 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"sync"
+	"time"
+)
+
+// EventBuffer is the number of events Events buffers for a slow reader.
+const EventBuffer = 64
+
+// EventKind identifies what an Event reports.
+type EventKind int
+
+const (
+	EventRotation   EventKind = iota + 1 // the active file was rotated to Path
+	EventRetention                       // the rotated file or bundle at Path was deleted by retention or Purge
+	EventFlushError                      // writing out a flush failed with Err
+	EventDrop                            // a write of Bytes bytes was dropped by the rate or pause limit, given by Err
+	EventCorruption                      // the rotated file or bundle at Path failed VerifyRotations with Err
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventRotation:
+		return "rotation"
+	case EventRetention:
+		return "retention"
+	case EventFlushError:
+		return "flush error"
+	case EventDrop:
+		return "drop"
+	case EventCorruption:
+		return "corruption"
+	}
+	return "unknown"
+}
+
+// Event describes something that happened to a Writer. Only the fields
+// relevant to Kind are set.
+type Event struct {
+	Kind  EventKind
+	Time  time.Time
+	Path  string
+	Err   error
+	Bytes int
+}
+
+// events is the channel behind Events, guarded so that emitting can't race
+// with closing it.
+type events struct {
+	mu     sync.Mutex
+	ch     chan Event
+	closed bool
+}
+
+// Events returns a channel of events such as rotations, retention deletes,
+// flush errors, dropped writes, and corrupt files, for tooling that reacts to
+// them. Every call returns the same channel, which is closed by Close. Events
+// are only recorded once Events has been called, and are discarded rather than
+// blocking the Writer when EventBuffer of them are waiting to be read.
+func (w *Writer) Events() <-chan Event {
+	w.events.mu.Lock()
+	defer w.events.mu.Unlock()
+	if w.events.ch == nil {
+		w.events.ch = make(chan Event, EventBuffer)
+		if w.events.closed {
+			close(w.events.ch)
+		}
+	}
+	return w.events.ch
+}
+
+// emit sends e to the events channel, if anyone asked for it and there's room.
+func (w *Writer) emit(e Event) {
+	w.events.mu.Lock()
+	defer w.events.mu.Unlock()
+	if w.events.ch == nil || w.events.closed {
+		return
+	}
+	e.Time = now()
+	select {
+	case w.events.ch <- e:
+	default:
+	}
+}
+
+// closeEvents closes the events channel.
+func (w *Writer) closeEvents() {
+	w.events.mu.Lock()
+	defer w.events.mu.Unlock()
+	if !w.events.closed && w.events.ch != nil {
+		close(w.events.ch)
+	}
+	w.events.closed = true
+}
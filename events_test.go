@@ -0,0 +1,43 @@
+package rlog
+
+import (
+	"testing"
+	"time"
+)
+
+// TestEvents verifies that rotations, retention deletes, and drops are
+// emitted, and that Close closes the channel.
+func TestEvents(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { clock = clock.Add(time.Second); return clock }
+	defer func() { now = time.Now }()
+
+	w, _, err := NewMemory(WithMaxFileSize(4), WithMaxRotations(1), WithRateLimit(1, 8))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	events := w.Events()
+	if w.Events() != events {
+		t.Errorf("expected Events to return the same channel")
+	}
+	for i := 0; i < 3; i++ {
+		w.WriteString("ab\n")
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	w.WriteString("too long for the burst\n")
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	counts := map[EventKind]int{}
+	for e := range events {
+		counts[e.Kind]++
+		if e.Time.IsZero() {
+			t.Errorf("%v event without a time", e.Kind)
+		}
+	}
+	if counts[EventRotation] != 2 || counts[EventRetention] != 1 || counts[EventDrop] != 1 {
+		t.Errorf("unexpected event counts %v", counts)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import "sync/atomic"
+
+// failure boxes an error so that it can be stored atomically.
+type failure struct{ err error }
+
+// recordFailure counts err against n and remembers it as the last error. It
+// may be called with or without mu and ioMu held.
+func (s *stats) recordFailure(n *atomic.Uint64, err error) {
+	n.Add(1)
+	s.lastErr.Store(&failure{err})
+}
+
+// fail records err as a failure counted against n and passes it to the error
+// handler, if any.
+func (w *Writer) fail(n *atomic.Uint64, err error) {
+	w.stats.recordFailure(n, err)
+	if w.onError != nil {
+		w.onError(err)
+	}
+}
+
+// LastError returns the most recent internal failure, or nil if there hasn't
+// been one. Unlike the errors returned by Write and Flush, it also covers
+// failures the Writer carries on past, such as a switch to the fallback, a
+// failed sink write, or a file that couldn't be archived, so supervising code
+// can notice a logger failing behind a wrapper like the standard log package,
+// which discards Write errors. Stats counts these failures by kind.
+//
+// It's safe to call concurrently with other methods, even with WithNoSync.
+func (w *Writer) LastError() error {
+	if f := w.stats.lastErr.Load(); f != nil {
+		return f.err
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package rlog
+
+import (
+	"bytes"
+	"errors"
+	"syscall"
+	"testing"
+)
+
+// failingSink is a Sink whose writes always fail.
+type failingSink struct{}
+
+func (failingSink) Write(p []byte) (int, error) { return 0, errors.New("sink down") }
+func (failingSink) Close() error                { return nil }
+
+// TestLastError verifies that failures the Writer carries on past are counted
+// and kept as the last error.
+func TestLastError(t *testing.T) {
+	fsys := &flakyFS{Memory: NewMemoryFS()}
+	var fb bytes.Buffer
+	w, err := New(".", WithFS(fsys), WithFallback(&fb), WithSink(failingSink{}))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	if err := w.LastError(); err != nil {
+		t.Errorf("expected no error before any failure, got %v", err)
+	}
+
+	w.WriteString("hello\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if s := w.Stats(); s.SinkFailures != 1 || s.FlushFailures != 0 {
+		t.Errorf("expected only a sink failure, got %+v", s)
+	}
+	if err := w.LastError(); err == nil || err.Error() != "sink write failed: sink down" {
+		t.Errorf("expected the sink failure as the last error, got %v", err)
+	}
+
+	fsys.down = true
+	w.WriteString("world\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("expected the fallback to absorb the failure, got %v", err)
+	}
+	if fb.String() != "world\n" {
+		t.Errorf("expected the fallback to get %q, got %q", "world\n", fb.String())
+	}
+	if s := w.Stats(); s.FlushFailures != 1 {
+		t.Errorf("expected 1 flush failure, got %d", s.FlushFailures)
+	}
+	if err := w.LastError(); !errors.Is(err, syscall.EIO) {
+		t.Errorf("expected the write failure as the last error, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrFallback is reported by HealthCheck while the Writer is writing to its
+// fallback because the log file failed.
+var ErrFallback = errors.New("writing to fallback")
+
+// fallbackProbe is how often a Writer on its fallback tries the log file again.
+var fallbackProbe = 5 * time.Second
+
+// WithFallback configures the Writer to send flushed data to out, or to
+// os.Stderr if out is nil, when writing the log file fails, instead of making
+// the error sticky. Failures are retried first under WithRetryPolicy. While on
+// the fallback, the Writer reopens and tries the log file again every few
+// seconds and switches back as soon as a flush succeeds. A flush that failed
+// midway may leave its start in the log file as well as in the fallback.
+//
+// Only an error from out itself is returned to callers; HealthCheck reports
+// ErrFallback while the log file is failing.
+func WithFallback(out io.Writer) Option {
+	return func(w *Writer) {
+		if out == nil {
+			out = os.Stderr
+		}
+		w.fallback = out
+	}
+}
+
+// deliver writes buf out to the log file, or to the fallback if the log file
+// is failing.
+func (w *Writer) deliver(buf []byte, earlyRotate bool) error {
+	if w.dormant {
+		if err := w.wake(); err != nil {
+			return err
+		}
+	} else if w.budget != nil && w.file != nil {
+		w.budget.touch(w)
+	}
+	if w.fallback == nil {
+		_, err := w.timedWriteOut(buf, earlyRotate)
+		return err
+	}
+	if w.fallbackErr != nil {
+		if now().Sub(w.fallbackAt) < fallbackProbe {
+			return w.writeFallback(buf)
+		}
+		w.fallbackAt = now()
+		if w.file != nil {
+			w.closeActive()
+		}
+		if err := w.openActive(); err != nil {
+			w.fallbackErr = fmt.Errorf("failed to reopen log file: %w", err)
+			w.stats.recordFailure(&w.stats.flushFailures, w.fallbackErr)
+			return w.writeFallback(buf)
+		}
+	}
+	slow, err := w.timedWriteOut(buf, earlyRotate)
+	if err != nil {
+		w.switchToFallback(err)
+		return w.writeFallback(buf)
+	}
+	if slow {
+		w.switchToFallback(fmt.Errorf("%w of %v", ErrSlowFlush, w.flushDeadline))
+		return nil // this flush did reach the log file
+	}
+	w.fallbackErr = nil // recovered, if it was failing
+	return nil
+}
+
+// switchToFallback sends later flushes to the fallback because of err.
+func (w *Writer) switchToFallback(err error) {
+	if w.fallbackErr == nil {
+		w.reportError(err)
+	}
+	w.fallbackErr = err
+	w.fallbackAt = now()
+}
+
+// writeFallback writes buf to the fallback.
+func (w *Writer) writeFallback(buf []byte) error {
+	if _, err := w.fallback.Write(buf); err != nil {
+		return fmt.Errorf("failed to write to fallback: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package rlog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestFallback verifies that flushes go to the fallback while the log file is
+// failing and return to the log file once it recovers.
+func TestFallback(t *testing.T) {
+	defer func(d time.Duration) { fallbackProbe = d }(fallbackProbe)
+	fallbackProbe = time.Hour
+
+	fsys := &flakyFS{Memory: NewMemoryFS()}
+	var fallback bytes.Buffer
+	w, err := New(".", WithFS(fsys), WithFallback(&fallback))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	fsys.down = true
+	w.WriteString("one\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if err := w.HealthCheck(); !errors.Is(err, ErrFallback) {
+		t.Errorf("expected ErrFallback, got %v", err)
+	}
+	fsys.down = false
+	w.WriteString("two\n") // before the next probe
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	fallbackProbe = 0
+	w.WriteString("three\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if err := w.HealthCheck(); err != nil {
+		t.Errorf("expected recovery, got %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := fallback.String(); got != "one\ntwo\n" {
+		t.Errorf("expected fallback %q, got %q", "one\ntwo\n", got)
+	}
+	if got, _ := fsys.ReadFile("latest.log"); string(got) != "three\n" {
+		t.Errorf("expected log file %q, got %q", "three\n", got)
+	}
+}
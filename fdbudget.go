@@ -0,0 +1,117 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// WithMaxOpenFiles limits how many active files the streams of a Manager keep
+// open at once, so services with hundreds of streams, e.g. one per tenant,
+// don't run out of file descriptors. When a stream opens its file beyond the
+// limit, the least recently flushed stream's file is closed, to be reopened by
+// its next flush; buffering is unaffected. Streams busy with I/O are skipped,
+// so the limit may briefly be exceeded. Zero, the default, means no limit.
+//
+// WithMaxOpenFiles only takes effect through NewManager.
+func WithMaxOpenFiles(n int) Option {
+	return func(w *Writer) {
+		w.maxOpenFiles = n
+	}
+}
+
+// withBudget shares b among the Writers of a Manager.
+func withBudget(b *fdBudget) Option {
+	return func(w *Writer) {
+		w.budget = b
+	}
+}
+
+// fdBudget tracks the Writers with an open active file, least recently used
+// last. Lock order: a Writer's ioMu, then mu. While holding mu, the budget
+// only tries other Writers' ioMu without waiting, and never takes their mu;
+// their files are closed once mu is released.
+type fdBudget struct {
+	mu    sync.Mutex
+	max   int
+	lru   *list.List // of *Writer
+	elems map[*Writer]*list.Element
+}
+
+func newFDBudget(max int) *fdBudget {
+	return &fdBudget{max: max, lru: list.New(), elems: make(map[*Writer]*list.Element)}
+}
+
+// touch marks w, which holds its ioMu and has its file open, as most recently
+// used, closing the files of idle Writers while over the limit.
+func (b *fdBudget) touch(w *Writer) {
+	for _, v := range b.evict(w) {
+		v.sleep()
+	}
+}
+
+// evict marks w as most recently used and, while over the limit, forgets the
+// least recently used Writers that aren't busy with I/O and returns them with
+// their ioMu held, for touch to put to sleep.
+func (b *fdBudget) evict(w *Writer) []*Writer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if e, ok := b.elems[w]; ok {
+		b.lru.MoveToFront(e)
+		return nil
+	}
+	b.elems[w] = b.lru.PushFront(w)
+	var idle []*Writer
+	for e := b.lru.Back(); e != nil && len(b.elems) > b.max; {
+		prev := e.Prev()
+		if v := e.Value.(*Writer); v != w && v.ioMu.TryLock() {
+			b.lru.Remove(e)
+			delete(b.elems, v)
+			idle = append(idle, v)
+		}
+		e = prev
+	}
+	return idle
+}
+
+// remove forgets w, e.g. once it's closed.
+func (b *fdBudget) remove(w *Writer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if e, ok := b.elems[w]; ok {
+		b.lru.Remove(e)
+		delete(b.elems, w)
+	}
+}
+
+// sleep closes the active file to free its descriptor, leaving the Writer
+// dormant until its next flush, and releases ioMu, which the budget took.
+func (w *Writer) sleep() {
+	defer w.ioMu.Unlock()
+	if w.file == nil {
+		return
+	}
+	if err := w.closeActive(); err != nil {
+		w.mu.Lock()
+		if w.err == nil {
+			w.err = fmt.Errorf("failed to close idle log file: %w", err)
+			w.reportError(w.err)
+		}
+		w.mu.Unlock()
+		return
+	}
+	w.dormant = true
+}
+
+// wake reopens the active file of a dormant Writer. ioMu must be held.
+func (w *Writer) wake() error {
+	if err := w.openActive(); err != nil {
+		return fmt.Errorf("failed to reopen log file: %w", err)
+	}
+	w.dormant = false
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fileExists reports whether anything exists at path in fsys. Errors other
+// than the path not existing are returned.
+func fileExists(fsys FS, path string) (bool, error) {
+	_, err := fsys.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// isLogName reports whether name has a log file extension, compressed or not.
+func isLogName(name string) bool {
+	return strings.HasSuffix(name, ".log") || strings.HasSuffix(name, ".log.gz")
+}
+
+// isActiveName reports whether name is an active log file.
+func isActiveName(name string) bool {
+	return name == "latest.log" || name == "latest.log.gz"
+}
+
+// openLog opens the log file at path for reading, transparently decompressing
+// files with a ".gz" extension.
+func openLog(fsys FS, path string) (io.ReadCloser, error) {
+	f, err := fsys.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return f, nil
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		if err == io.EOF { // empty file
+			return io.NopCloser(strings.NewReader("")), nil
+		}
+		return nil, err
+	}
+	return &gzipReadCloser{zr, f}, nil
+}
+
+// gzipReadCloser closes both a gzip reader and its underlying file.
+type gzipReadCloser struct {
+	*gzip.Reader
+	f io.Closer
+}
+
+func (g *gzipReadCloser) Close() error {
+	g.Reader.Close()
+	return g.f.Close()
+}
+
+// logFiles returns the names of the log files in dirPath in chronological
+// order, with the active file last.
+func logFiles(fsys FS, dirPath string) ([]string, error) {
+	entries, err := fsys.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	var active []string
+	for _, e := range entries {
+		switch {
+		case e.IsDir():
+		case isActiveName(e.Name()):
+			active = append(active, e.Name())
+		case isLogName(e.Name()):
+			names = append(names, e.Name())
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return rotatedLess(names[i], names[j]) })
+	return append(names, active...), nil
+}
+
+// rotatedLess orders rotated file names chronologically. Timestamps sort as
+// plain strings; names sharing a timestamp are ordered by their "_N" suffix,
+// with the unsuffixed name first.
+func rotatedLess(a, b string) bool {
+	baseA, seqA := splitRotatedSeq(a)
+	baseB, seqB := splitRotatedSeq(b)
+	if baseA != baseB {
+		return baseA < baseB
+	}
+	return seqA < seqB
+}
+
+// splitRotatedSeq splits a rotated file name into its base and collision
+// suffix, which is 0 when absent.
+func splitRotatedSeq(name string) (string, int) {
+	base := strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".log")
+	if i := strings.LastIndexByte(base, '_'); i >= 0 {
+		if seq, err := strconv.Atoi(base[i+1:]); err == nil && seq > 0 {
+			return base[:i], seq
+		}
+	}
+	return base, 0
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"reflect"
+)
+
+// FS is the set of filesystem operations a Writer performs on its directory.
+// The operating system is used by default; WithFS substitutes another
+// implementation, such as a Memory or an adapter for an exotic backend, without
+// changing the Writer's buffering, rotation, or retention logic.
+//
+// Methods follow the semantics of their os package counterparts and should
+// return errors satisfying errors.Is(err, fs.ErrNotExist) for missing files.
+// The Writer detects that its active file was replaced by comparing FileInfo
+// from Stat and File.Stat; implementations should return the same comparable
+// value from Sys for both, or nil if they can't identify files.
+//
+// An FS may also implement SyncDir(name string) error, which makes the creation
+// and renaming of files within the directory name durable, like OSFS does with
+// fsync on Unix. The Writer calls it after each rotation.
+type FS interface {
+	OpenFile(name string, flag int, perm fs.FileMode) (File, error)
+	Rename(oldpath, newpath string) error
+	Remove(name string) error
+	Stat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	MkdirAll(path string, perm fs.FileMode) error
+}
+
+// File is an open file within an FS. Files opened for appending are
+// written with os.O_APPEND semantics.
+type File interface {
+	io.Reader
+	io.ReaderAt
+	io.Writer
+	io.Closer
+	Stat() (fs.FileInfo, error)
+	Sync() error
+	Truncate(size int64) error
+}
+
+// OSFS is the FS backed by the operating system. It's the default, and is
+// useful as a base for implementations that only alter some operations.
+type OSFS struct{}
+
+// WithFS makes the Writer perform all file operations through fsys instead of
+// the operating system. Package level helpers that take paths, such as Recover
+// and VerifyChainDir, always use the operating system.
+func WithFS(fsys FS) Option {
+	return func(w *Writer) {
+		w.fs = fsys
+	}
+}
+
+func (OSFS) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	f, err := openFile(name, flag, perm)
+	if err != nil {
+		return nil, err // avoid a non-nil file holding a nil *os.File
+	}
+	return f, nil
+}
+
+func (OSFS) Rename(oldpath, newpath string) error         { return rename(oldpath, newpath) }
+func (OSFS) Remove(name string) error                     { return os.Remove(name) }
+func (OSFS) Stat(name string) (fs.FileInfo, error)        { return os.Stat(name) }
+func (OSFS) ReadDir(name string) ([]fs.DirEntry, error)   { return os.ReadDir(name) }
+func (OSFS) MkdirAll(path string, perm fs.FileMode) error { return os.MkdirAll(path, perm) }
+
+// SyncDir flushes the directory entries of the named directory to disk, so
+// that files created or renamed within it survive a power loss. It does
+// nothing on platforms where directories can't be synced, such as Windows.
+func (OSFS) SyncDir(name string) error { return syncDirEntries(name) }
+
+// syncDir calls SyncDir on fsys if it implements it.
+func syncDir(fsys FS, name string) error {
+	if s, ok := fsys.(interface{ SyncDir(string) error }); ok {
+		return s.SyncDir(name)
+	}
+	return nil
+}
+
+// readFile returns the contents of the named file in fsys.
+func readFile(fsys FS, name string) ([]byte, error) {
+	f, err := fsys.OpenFile(name, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// writeFile writes data to the named file in fsys, replacing any contents.
+func writeFile(fsys FS, name string, data []byte, perm fs.FileMode) error {
+	f, err := fsys.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// sameFile reports whether a and b describe the same file. Besides files from
+// the OS, it recognizes files whose Sys method returns the same comparable,
+// non-nil value. When neither applies it can't tell and assumes they are.
+func sameFile(a, b fs.FileInfo) bool {
+	if os.SameFile(a, b) {
+		return true
+	}
+	sa, sb := a.Sys(), b.Sys()
+	if sa == nil || sb == nil || reflect.TypeOf(sa) != reflect.TypeOf(sb) || !reflect.TypeOf(sa).Comparable() {
+		return true
+	}
+	return sa == sb
+}
@@ -0,0 +1,134 @@
+package rlog
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// renameCountingFS is an FS that counts renames, standing in for a custom backend.
+type renameCountingFS struct {
+	OSFS
+	renames int
+}
+
+func (c *renameCountingFS) Rename(oldpath, newpath string) error {
+	c.renames++
+	return c.OSFS.Rename(oldpath, newpath)
+}
+
+// TestWithFS verifies that rotations go through the configured FS.
+func TestWithFS(t *testing.T) {
+	fsys := &renameCountingFS{}
+	dirPath := t.TempDir()
+	w, err := New(dirPath, WithFS(fsys), WithMaxFileSize(4))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		w.WriteString("line\n")
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if fsys.renames != 3 {
+		t.Errorf("expected 3 renames through the FS, got %d", fsys.renames)
+	}
+	if ok, _ := fileExists(OSFS{}, filepath.Join(dirPath, "latest.log")); !ok {
+		t.Errorf("expected latest.log on disk")
+	}
+}
+
+// flakyFS is an FS whose files fail their first writes and syncs with EIO,
+// like a network filesystem hiccup, or every write while down or full.
+type flakyFS struct {
+	*Memory
+	writeFails, syncFails int
+	down, full            bool
+}
+
+func (s *flakyFS) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	f, err := s.Memory.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return flakyFile{f, s}, nil
+}
+
+type flakyFile struct {
+	File
+	fs *flakyFS
+}
+
+func (f flakyFile) Write(p []byte) (int, error) {
+	if f.fs.down {
+		return 0, &fs.PathError{Op: "write", Path: "latest.log", Err: syscall.EIO}
+	}
+	if f.fs.full {
+		return 0, &fs.PathError{Op: "write", Path: "latest.log", Err: syscall.ENOSPC}
+	}
+	if f.fs.writeFails > 0 {
+		f.fs.writeFails--
+		n, _ := f.File.Write(p[:len(p)/2]) // partial write
+		return n, &fs.PathError{Op: "write", Path: "latest.log", Err: syscall.EIO}
+	}
+	return f.File.Write(p)
+}
+
+func (f flakyFile) Sync() error {
+	if f.fs.syncFails > 0 {
+		f.fs.syncFails--
+		return &fs.PathError{Op: "sync", Path: "latest.log", Err: syscall.EIO}
+	}
+	return f.File.Sync()
+}
+
+// wrappingFS is an FS whose Stat wraps its errors, as allowed by the FS
+// contract, and fails outright while broken.
+type wrappingFS struct {
+	*Memory
+	broken bool
+}
+
+func (s *wrappingFS) Stat(name string) (fs.FileInfo, error) {
+	if s.broken {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: syscall.EIO}
+	}
+	fi, err := s.Memory.Stat(name)
+	if err != nil {
+		return nil, fmt.Errorf("memory backend: %w", err)
+	}
+	return fi, nil
+}
+
+// TestWrappedStatErrors verifies that wrapped not-exist errors are recognized
+// when rotating, and that other Stat errors fail the rotation rather than
+// being taken for an existing file.
+func TestWrappedStatErrors(t *testing.T) {
+	fsys := &wrappingFS{Memory: NewMemoryFS()}
+	w, err := New(".", WithFS(fsys), WithMaxFileSize(4))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	for i := 0; i < 2; i++ {
+		w.WriteString("line\n")
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	if rotations, err := w.ListRotations(); err != nil || len(rotations) != 2 || rotations[1].Signed {
+		t.Errorf("expected two unsigned rotations, got %+v, %v", rotations, err)
+	}
+	fsys.broken = true
+	w.WriteString("line\n")
+	if err := w.Flush(); !errors.Is(err, syscall.EIO) {
+		t.Errorf("expected the Stat error from the rotation, got %v", err)
+	}
+}
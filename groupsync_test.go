@@ -0,0 +1,50 @@
+package rlog
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSyncInterval verifies that flushes leave syncing to the background
+// goroutine, which batches them, and that Close syncs what's left.
+func TestSyncInterval(t *testing.T) {
+	fsys := &slowSyncFS{Memory: NewMemoryFS()}
+	w, err := New(".", WithFS(fsys), WithSyncInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		w.WriteString("line\n")
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	if n := fsys.syncs.Load(); n != 0 {
+		t.Errorf("expected no syncs before the interval, got %d", n)
+	}
+	if got, _ := fsys.ReadFile("latest.log"); len(got) != 50 {
+		t.Errorf("expected flushes to write immediately, got %q", got)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if n := fsys.syncs.Load(); n != 1 {
+		t.Errorf("expected 1 sync on Close, got %d", n)
+	}
+
+	fsys = &slowSyncFS{Memory: NewMemoryFS()}
+	w, err = New(".", WithFS(fsys), WithSyncInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	w.WriteString("line\n")
+	w.Flush()
+	deadline := time.Now().Add(time.Second)
+	for fsys.syncs.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if fsys.syncs.Load() == 0 {
+		t.Errorf("expected the background goroutine to sync")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrLowDiskSpace is returned by HealthCheck when the log directory's
+// filesystem has less free space than set by WithMinFreeSpace.
+var ErrLowDiskSpace = errors.New("low disk space")
+
+// ErrIOStalled is returned by HealthCheck when a flush, rotation, or other
+// file operation is still in progress after healthGrace, and wrapped by a
+// Multi's Flush and Close for targets that don't finish in time.
+var ErrIOStalled = errors.New("log file I/O stalled")
+
+// healthGrace is how long HealthCheck waits for file I/O in progress before
+// reporting ErrIOStalled. It's a variable so tests can shorten it.
+var healthGrace = 100 * time.Millisecond
+
+// errDiskFreeUnsupported is returned by diskFree on platforms where free space
+// can't be queried.
+var errDiskFreeUnsupported = errors.New("disk free space unsupported on this platform")
+
+// WithMinFreeSpace makes HealthCheck fail when the log directory's filesystem
+// has fewer than bytes available. Zero disables the check, as does running on a
+// platform where free space can't be queried.
+func WithMinFreeSpace(bytes uint64) Option {
+	return func(w *Writer) {
+		w.minFreeSpace = bytes
+	}
+}
+
+// HealthCheck reports whether w can currently accept and persist logs. It
+// returns the Writer's sticky error if one occurred, and otherwise verifies
+// that the directory exists and is writable, that the active file is open, and
+// that free space is above the WithMinFreeSpace threshold. It's cheap enough
+// to back a readiness probe, and never waits long for the file: if I/O started
+// by another goroutine hasn't finished within a short grace period, e.g.
+// because the disk hangs, it returns ErrIOStalled rather than blocking.
+func (w *Writer) HealthCheck() error {
+	if w.mu != nil {
+		if !w.tryLockIO(healthGrace) {
+			return fmt.Errorf("%w: still in progress after %v", ErrIOStalled, healthGrace)
+		}
+		defer w.ioMu.Unlock()
+		w.mu.Lock()
+		defer w.mu.Unlock()
+	}
+	if w.err != nil {
+		return w.err
+	}
+	if fi, err := w.fs.Stat(w.dirPath); err != nil {
+		return err
+	} else if !fi.IsDir() {
+		return fmt.Errorf("path %q is not a directory", w.dirPath)
+	}
+	if w.fallbackErr != nil {
+		return fmt.Errorf("%w: %v", ErrFallback, w.fallbackErr)
+	}
+	if w.file == nil && !w.dormant {
+		return fmt.Errorf("log file %q is closed", w.activePath())
+	}
+	if w.file != nil {
+		if _, err := w.file.Stat(); err != nil {
+			return fmt.Errorf("failed to stat log file: %w", err)
+		}
+	}
+	// Rotation needs to create files, so probe the directory itself.
+	probePath := filepath.Join(w.dirPath, ".healthcheck")
+	probe, err := w.fs.OpenFile(probePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("directory %q is not writable: %w", w.dirPath, err)
+	}
+	probe.Close()
+	w.fs.Remove(probePath)
+	if _, ok := w.fs.(OSFS); ok && w.minFreeSpace > 0 {
+		free, err := diskFree(w.dirPath)
+		if err != nil && err != errDiskFreeUnsupported {
+			return fmt.Errorf("failed to query free space: %w", err)
+		}
+		if err == nil && free < w.minFreeSpace {
+			return fmt.Errorf("%w: %d bytes free, want at least %d", ErrLowDiskSpace, free, w.minFreeSpace)
+		}
+	}
+	return nil
+}
+
+// tryLockIO locks ioMu, giving up after d. It reports whether ioMu was locked.
+func (w *Writer) tryLockIO(d time.Duration) bool {
+	deadline := time.Now().Add(d)
+	for !w.ioMu.TryLock() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package rlog
+
+import (
+	"errors"
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestHealthCheck verifies that HealthCheck passes for a working Writer and
+// reports low disk space, a missing directory, and a closed Writer.
+func TestHealthCheck(t *testing.T) {
+	dirPath := t.TempDir()
+	w, err := New(dirPath, WithMinFreeSpace(1))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	if err := w.HealthCheck(); err != nil {
+		t.Errorf("expected healthy Writer, got %v", err)
+	}
+	if _, err := diskFree(dirPath); err == nil {
+		w.minFreeSpace = math.MaxUint64
+		if err := w.HealthCheck(); !errors.Is(err, ErrLowDiskSpace) {
+			t.Errorf("expected ErrLowDiskSpace, got %v", err)
+		}
+		w.minFreeSpace = 0
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := w.HealthCheck(); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed after Close, got %v", err)
+	}
+
+	w, err = New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	if err := os.RemoveAll(w.dirPath); err != nil {
+		t.Fatalf("failed to remove directory: %v", err)
+	}
+	if err := w.HealthCheck(); err == nil {
+		t.Errorf("expected error for missing directory")
+	}
+}
+
+// TestHealthCheckStalled verifies that HealthCheck reports file I/O stuck in
+// another goroutine instead of waiting for it.
+func TestHealthCheckStalled(t *testing.T) {
+	defer func(d time.Duration) { healthGrace = d }(healthGrace)
+	healthGrace = 10 * time.Millisecond
+	w, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	w.ioMu.Lock() // a flush blocked on the disk
+	if err := w.HealthCheck(); !errors.Is(err, ErrIOStalled) {
+		t.Errorf("expected ErrIOStalled, got %v", err)
+	}
+	w.ioMu.Unlock()
+	if err := w.HealthCheck(); err != nil {
+		t.Errorf("expected healthy Writer once I/O finished, got %v", err)
+	}
+}
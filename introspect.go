@@ -0,0 +1,37 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import "time"
+
+// The getters below never block, even while another goroutine is flushing, so
+// they're safe to poll from monitoring code even with WithNoSync.
+
+// BufferedBytes returns the number of bytes buffered but not yet flushed.
+func (w *Writer) BufferedBytes() int {
+	return int(w.pending.Load())
+}
+
+// CurrentFileSize returns the size of the active log file as of the last
+// flush or rotation. With stream compression it's the compressed size.
+func (w *Writer) CurrentFileSize() int64 {
+	return w.fileSize.Load()
+}
+
+// LastFlushTime returns when the buffer was last flushed, or when the Writer
+// was created if it hasn't been flushed yet.
+func (w *Writer) LastFlushTime() time.Time {
+	return time.Unix(0, w.flushedAt.Load())
+}
+
+// ArchiveQueueLen returns the number of rotated files queued for or being
+// processed by the Archiver, or 0 without one. A steadily growing value means
+// archiving can't keep up with rotation.
+func (w *Writer) ArchiveQueueLen() int {
+	if w.archives == nil {
+		return 0
+	}
+	return int(w.archives.pending.Load())
+}
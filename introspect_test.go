@@ -0,0 +1,39 @@
+package rlog
+
+import (
+	"testing"
+	"time"
+)
+
+// TestIntrospection verifies the buffer, file size, and flush time getters.
+func TestIntrospection(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	w, _, err := NewMemory()
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	w.WriteString("hello\n")
+	if got := w.BufferedBytes(); got != 6 {
+		t.Errorf("BufferedBytes: got %d, want 6", got)
+	}
+	if got := w.CurrentFileSize(); got != 0 {
+		t.Errorf("CurrentFileSize before flush: got %d, want 0", got)
+	}
+	clock = clock.Add(time.Minute)
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if got := w.BufferedBytes(); got != 0 {
+		t.Errorf("BufferedBytes after flush: got %d, want 0", got)
+	}
+	if got := w.CurrentFileSize(); got != 6 {
+		t.Errorf("CurrentFileSize after flush: got %d, want 6", got)
+	}
+	if got := w.LastFlushTime(); !got.Equal(clock) {
+		t.Errorf("LastFlushTime: got %v, want %v", got, clock)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"fmt"
+	"time"
+)
+
+// The setters below change a limit on a live Writer, e.g. from an admin
+// endpoint, without recreating it and losing buffered data. They accept the
+// same values as the corresponding options and take effect from the next
+// Write or flush; a buffer or file already past a lowered limit is flushed or
+// rotated then rather than immediately.
+
+// SetMaxFileSize changes the size at which the active file is rotated, as set
+// by WithMaxFileSize. It waits for an in-progress flush to finish.
+func (w *Writer) SetMaxFileSize(size int64) error {
+	if size <= 0 {
+		return fmt.Errorf("%w, got %d", ErrInvalidMaxFileSize, size)
+	}
+	if w.ioMu != nil {
+		w.ioMu.Lock()
+		defer w.ioMu.Unlock()
+	}
+	w.maxFileSize = size
+	return nil
+}
+
+// SetMaxBufSize changes the buffer size that triggers a flush, as set by
+// WithMaxBufSize.
+func (w *Writer) SetMaxBufSize(size int) error {
+	if size <= 0 {
+		return fmt.Errorf("%w, got %d", ErrInvalidMaxBufSize, size)
+	}
+	if w.mu != nil {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+	}
+	w.maxBufSize = size
+	return nil
+}
+
+// SetMaxBufAge changes the buffer age that triggers a flush, as set by
+// WithMaxBufAge.
+func (w *Writer) SetMaxBufAge(d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("%w, got %v", ErrInvalidMaxBufAge, d)
+	}
+	if w.mu != nil {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+	}
+	w.maxBufAge = d
+	return nil
+}
@@ -0,0 +1,45 @@
+package rlog
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestSetLimits verifies that limits changed at runtime take effect without
+// losing buffered data, and that invalid values are rejected.
+func TestSetLimits(t *testing.T) {
+	w, m, err := NewMemory(WithMaxBufAge(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	w.WriteString("abcdef")
+	if err := w.SetMaxBufSize(8); err != nil {
+		t.Fatalf("SetMaxBufSize failed: %v", err)
+	}
+	if err := w.SetMaxFileSize(12); err != nil {
+		t.Fatalf("SetMaxFileSize failed: %v", err)
+	}
+	if got := string(m.Files()["latest.log"]); got != "" {
+		t.Fatalf("expected nothing flushed yet, got %q", got)
+	}
+	w.WriteString("ghij") // exceeds the new buffer size
+	if got := string(m.Files()["latest.log"]); got != "abcdefghij" {
+		t.Errorf("expected the buffer flushed under the new limit, got %q", got)
+	}
+	w.WriteString("klmnopqr") // exceeds the new file size
+	if len(m.Files()) != 2 {
+		t.Errorf("expected a rotation under the new file size, got %v", m.Files())
+	}
+
+	if err := w.SetMaxFileSize(0); !errors.Is(err, ErrInvalidMaxFileSize) {
+		t.Errorf("expected ErrInvalidMaxFileSize, got %v", err)
+	}
+	if err := w.SetMaxBufSize(-1); !errors.Is(err, ErrInvalidMaxBufSize) {
+		t.Errorf("expected ErrInvalidMaxBufSize, got %v", err)
+	}
+	if err := w.SetMaxBufAge(0); !errors.Is(err, ErrInvalidMaxBufAge) {
+		t.Errorf("expected ErrInvalidMaxBufAge, got %v", err)
+	}
+}
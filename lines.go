@@ -0,0 +1,63 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// WithMaxLines rotates the log file once it holds n complete lines, in
+// addition to the size limit, so every rotated file holds exactly n lines.
+// A flush that crosses the limit is split at the nth line, with a rotation in
+// between. Only WithMinRotationInterval can make a file exceed n lines. Zero,
+// the default, disables the limit.
+//
+// When reopening an existing file, its lines are counted, which means reading
+// it through once.
+func WithMaxLines(n int) Option {
+	return func(w *Writer) {
+		w.maxLines = n
+	}
+}
+
+// lineEnd returns the index just past the nth newline in p, or -1 if p holds
+// fewer than n newlines or n isn't positive.
+func lineEnd(p []byte, n int) int {
+	if n <= 0 {
+		return -1
+	}
+	off := 0
+	for ; n > 0; n-- {
+		i := bytes.IndexByte(p[off:], '\n')
+		if i < 0 {
+			return -1
+		}
+		off += i + 1
+	}
+	return off
+}
+
+// countLines returns the number of newlines in the log file at path, reading
+// compressed files up to any damage at their end.
+func countLines(fsys FS, path string) (int, error) {
+	rc, err := openLog(fsys, path)
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+	buf := make([]byte, 32*1024)
+	lines := 0
+	for {
+		n, err := rc.Read(buf)
+		lines += bytes.Count(buf[:n], []byte("\n"))
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			return lines, nil
+		} else if err != nil {
+			return lines, err
+		}
+	}
+}
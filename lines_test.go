@@ -0,0 +1,46 @@
+package rlog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMaxLines verifies that rotated files hold exactly the line limit, even
+// when a flush crosses it or the active file is reopened.
+func TestMaxLines(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { clock = clock.Add(time.Second); return clock }
+	defer func() { now = time.Now }()
+
+	m := NewMemoryFS()
+	w, err := New(".", WithFS(m), WithMaxLines(3))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	w.WriteString("a\nb\n")
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	w, err = New(".", WithFS(m), WithMaxLines(3))
+	if err != nil {
+		t.Fatalf("failed to reopen Writer: %v", err)
+	}
+	w.WriteString("c\nd\ne\nf\ng\nh\ni\npartial")
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	names, err := logFiles(m, ".")
+	if err != nil {
+		t.Fatalf("failed to list log files: %v", err)
+	}
+	var got []string
+	for _, name := range names {
+		data, _ := m.ReadFile(name)
+		got = append(got, string(data))
+	}
+	want := []string{"a\nb\nc\n", "d\ne\nf\n", "g\nh\ni\n", "partial"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("expected files %q, got %q", want, got)
+	}
+}
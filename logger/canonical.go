@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CanonicalLine accumulates attributes over the course of a request and emits
+// them as a single wide "canonical log line" when the request ends. It's safe
+// for concurrent use, and a nil *CanonicalLine ignores all calls so handlers
+// can record attributes without checking whether a line was started.
+//
+// Usage:
+//
+//	ctx, line := logger.WithCanonical(ctx)
+//	defer logger.EmitCanonical(ctx)
+//	logger.Canonical(ctx).Set("route", "/users").Set("db_ms", 42)
+//	line.Set("status", 200)
+type CanonicalLine struct {
+	mu      sync.Mutex
+	keys    []string
+	values  map[string]any
+	emitted bool
+}
+
+type canonicalKey struct{}
+
+// WithCanonical returns a copy of ctx carrying a new CanonicalLine.
+func WithCanonical(ctx context.Context) (context.Context, *CanonicalLine) {
+	line := &CanonicalLine{values: make(map[string]any)}
+	return context.WithValue(ctx, canonicalKey{}, line), line
+}
+
+// Canonical returns the CanonicalLine carried by ctx, or nil if there is none.
+func Canonical(ctx context.Context) *CanonicalLine {
+	line, _ := ctx.Value(canonicalKey{}).(*CanonicalLine)
+	return line
+}
+
+// Set records value under key, replacing any earlier value while keeping the
+// key's original position in the output. It returns c to allow chaining.
+func (c *CanonicalLine) Set(key string, value any) *CanonicalLine {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.values[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+	c.values[key] = value
+	return c
+}
+
+// String renders the recorded attributes in logfmt style.
+func (c *CanonicalLine) String() string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var b strings.Builder
+	for i, k := range c.keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(logfmtValue(k))
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(fmt.Sprint(c.values[k])))
+	}
+	return b.String()
+}
+
+// take marks the line emitted, reporting false if it already was.
+func (c *CanonicalLine) take() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.emitted {
+		return false
+	}
+	c.emitted = true
+	return true
+}
+
+// EmitCanonical writes the CanonicalLine carried by ctx at info level using the
+// logger carried by ctx. A line is emitted at most once; later calls, or calls
+// without a line or logger in ctx, do nothing.
+func EmitCanonical(ctx context.Context) {
+	l, line := FromContext(ctx), Canonical(ctx)
+	if l == nil || line == nil || !line.take() {
+		return
+	}
+	if l.isLevelEnabled(LevelInfo) {
+		l.withContext(ctx).output(LevelInfo, "canonical", "canonical-log-line "+line.String(), nil)
+	}
+}
+
+// FlushOnDone arranges for the CanonicalLine carried by ctx to be emitted, and
+// the logger carried by ctx to be flushed, as soon as ctx is canceled or times
+// out. The line records the cause under "ctx_err". This persists the context of
+// a failed request even if its handler never reaches its logging epilogue.
+//
+// Calling the returned stop function disarms the hook, reporting whether it did
+// so before the hook ran, as with context.AfterFunc. Handlers that finish
+// normally should call stop and emit the line themselves.
+func FlushOnDone(ctx context.Context) (stop func() bool) {
+	l := FromContext(ctx)
+	if l == nil {
+		return func() bool { return false }
+	}
+	return context.AfterFunc(ctx, func() {
+		if line := Canonical(ctx); line != nil {
+			line.Set("ctx_err", ctx.Err())
+		}
+		EmitCanonical(ctx)
+		if err := l.Flush(); err != nil && err != ErrClosed {
+			log.Printf("logger: failed to flush on context done: %v", err)
+		}
+	})
+}
+
+// logfmtValue quotes s if it contains characters that would make it ambiguous
+// in logfmt output.
+func logfmtValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"io"
+	"os"
+)
+
+// WithConsole also writes entries at or above level to w, e.g. warnings and
+// above to os.Stderr for kubectl logs while the files get debug and above.
+// The console level is independent of the file level, including that of named
+// loggers, and can be changed with SetConsoleLevel. Entries are formatted as
+// for the files, but a TextFormatter writes in color if w is a terminal and
+// the NO_COLOR environment variable is unset or empty: level tags are colored
+// and the metadata before the message is dimmed. Errors writing to w are
+// ignored; w isn't closed by Close.
+func WithConsole(w io.Writer, level string) Option {
+	return func(l *Logger) {
+		l.console, l.consoleName = w, level
+		l.consoleColor = isTerminal(w) && os.Getenv("NO_COLOR") == ""
+	}
+}
+
+// isTerminal reports whether w is a character device, such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// setupConsole applies the level given to WithConsole.
+func (l *Logger) setupConsole() error {
+	l.consoleLevel.Store(levelNone)
+	if l.console == nil {
+		return nil
+	}
+	f := *l.formatter.Load()
+	if tf, ok := f.(*TextFormatter); ok && l.consoleColor {
+		c := *tf
+		c.Color = true
+		f = &c
+	}
+	l.consoleFormatter.Store(&f)
+	return l.SetConsoleLevel(l.consoleName)
+}
+
+// SetConsoleLevel sets the minimum level written to the console given to
+// WithConsole. Levels are as in SetLevel. Without a console it has no effect.
+func (l *Logger) SetConsoleLevel(level string) error {
+	if l.IsClosed() {
+		return ErrClosed
+	}
+	newLevel, ok := l.parseLevel(level)
+	if !ok {
+		return l.invalidLevel(level)
+	}
+	l.consoleLevel.Store(int64(newLevel))
+	return nil
+}
+
+// consoleEnabled reports whether entries at level are written to the console.
+func (l *Logger) consoleEnabled(level int) bool {
+	return l.console != nil && l.consoleLevel.Load() <= int64(level)
+}
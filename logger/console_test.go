@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSetStyle verifies that SetStyle decorates tags on the console only,
+// while SetFlags applies to both outputs.
+func TestSetStyle(t *testing.T) {
+	var console bytes.Buffer
+	dirPath := t.TempDir()
+	l, err := New(dirPath, "info", WithConsole(&console, "info"))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	l.SetFlags(0, 0)
+	l.SetStyle(func(level string) (string, string) { return "<", ">" })
+	l.Info("styled")
+	l.SetStyle(nil)
+	l.Warn("plain")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	pid := os.Getpid()
+	want := fmt.Sprintf("[PID:%d]<INFO>: styled\n[PID:%d]WARN: plain\n", pid, pid)
+	if console.String() != want {
+		t.Errorf("console mismatch:\ngot  %q\nwant %q", console.String(), want)
+	}
+	data, err := os.ReadFile(filepath.Join(dirPath, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	want = fmt.Sprintf("[PID:%d]INFO: styled\n[PID:%d]WARN: plain\n", pid, pid)
+	if string(data) != want {
+		t.Errorf("file mismatch:\ngot  %q\nwant %q", data, want)
+	}
+}
+
+// TestConsole verifies that the console has a level of its own, independent
+// of the files and named loggers.
+func TestConsole(t *testing.T) {
+	var console bytes.Buffer
+	l, dirPath := newTestLogger(t, "debug", WithConsole(&console, "warn"))
+	l.Debug("file only")
+	l.Error("both")
+	if err := l.SetConsoleLevel("info"); err != nil {
+		t.Fatalf("SetConsoleLevel failed: %v", err)
+	}
+	if err := l.SetLevelFor("db", "error"); err != nil {
+		t.Fatalf("SetLevelFor failed: %v", err)
+	}
+	l.Named("db").Info("console only")
+	if err := l.SetConsoleLevel("loud"); !errors.Is(err, ErrInvalidLogLevel) {
+		t.Errorf("expected ErrInvalidLogLevel, got %v", err)
+	}
+	checkLines(t, readLog(t, l, dirPath), "DEBUG file only", "ERROR both")
+	if want := "ERROR both\nINFO console only logger=db\n"; console.String() != want {
+		t.Errorf("console mismatch:\ngot  %q\nwant %q", console.String(), want)
+	}
+}
+
+// TestColor verifies that colored text output colors the tag by level and
+// dims the metadata, and that only terminals get color.
+func TestColor(t *testing.T) {
+	e := &Entry{Level: "warn", Tag: "WARN", PID: 42, Msg: "disk low", order: LevelWarn}
+	f := TextFormatter{Color: true}
+	want := ansiDim + "[PID:42]" + ansiReset + "\x1b[33mWARN" + ansiReset + ": " + ansiDim + ansiReset + "disk low"
+	if got := string(f.Format(e)); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+	if isTerminal(&bytes.Buffer{}) {
+		t.Errorf("expected a buffer not to be a terminal")
+	}
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithDedup collapses runs of identical consecutive entries into the first
+// entry followed by "last message repeated N times" at the same level, keeping
+// exact counts without repeating the bytes. Entries are identical if they have
+// the same level, message, and fields; timestamps and callers are ignored. The
+// summary is written when a different entry arrives, when maxHold has passed
+// since the first repeat, or on Close, whichever comes first; a maxHold of 0
+// holds it until one of the others. Unlike WithMessageLimit, no occurrence goes
+// uncounted.
+func WithDedup(maxHold time.Duration) Option {
+	return func(l *Logger) {
+		l.dedup = &dedupState{maxHold: maxHold, core: l.core}
+	}
+}
+
+// dedupState tracks the last entry written and its pending repeats.
+type dedupState struct {
+	maxHold time.Duration
+	core    *core // for writing summaries without fields
+
+	mu     sync.Mutex // held while writing, to keep summaries in order
+	level  int
+	last   string // text of the last entry, see Logger.text
+	count  int    // repeats of last not yet summarized
+	timer  *time.Timer
+	closed bool
+
+	// nameLevel is that of the logger that wrote last, for its summary.
+	nameLevel *atomic.Int64
+}
+
+// writeDedup writes an entry as output does, unless it repeats the previous
+// one.
+func (l *Logger) writeDedup(level int, kind, msg string, kv []any) {
+	d := l.dedup
+	key := l.text(msg, kv)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+	if key == d.last && level == d.level {
+		d.count++
+		if d.timer == nil && d.maxHold > 0 {
+			d.timer = time.AfterFunc(d.maxHold, d.expire)
+		}
+		return
+	}
+	d.summarize(6 + l.skip)
+	d.level, d.last, d.nameLevel = level, key, l.nameLevel
+	l.write(level, kind, msg, kv, 5+l.skip)
+}
+
+// summarize writes the summary of pending repeats, if any, reporting the
+// caller depth frames up the stack from summarize. d.mu must be held.
+func (d *dedupState) summarize(depth int) {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.count == 0 {
+		return
+	}
+	msg := "last message repeated " + strconv.Itoa(d.count) + " times"
+	(&Logger{core: d.core, nameLevel: d.nameLevel}).write(d.level, "repeat", msg, nil, depth)
+	d.count = 0
+}
+
+// expire writes the summary once repeats have been held for maxHold.
+func (d *dedupState) expire() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.closed {
+		d.summarize(2)
+	}
+}
+
+// stop writes any pending summary and stops collapsing entries.
+func (d *dedupState) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.summarize(5)
+	d.closed = true
+}
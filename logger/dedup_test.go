@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDedup verifies that repeats are summarized when a different entry
+// arrives and on Close, and that fields and levels tell entries apart.
+func TestDedup(t *testing.T) {
+	l, dirPath := newTestLogger(t, "info", WithDedup(0))
+	for i := 0; i < 3; i++ {
+		l.Infow("retry", "n", 1)
+	}
+	l.Infow("retry", "n", 2)
+	l.Warnw("retry", "n", 2)
+	l.Warnw("retry", "n", 2)
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	checkLines(t, readLog(t, l, dirPath),
+		"INFO retry n=1",
+		"INFO last message repeated 2 times",
+		"INFO retry n=2",
+		"WARN retry n=2",
+		"WARN last message repeated 1 times")
+}
+
+// TestDedupMaxHold verifies that held repeats are summarized once maxHold has
+// passed, without another entry.
+func TestDedupMaxHold(t *testing.T) {
+	l, dirPath := newTestLogger(t, "info", WithDedup(100*time.Millisecond))
+	for i := 0; i < 4; i++ {
+		l.Info("retry")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		lines := readLog(t, l, dirPath)
+		if len(lines) == 2 {
+			checkLines(t, lines, "INFO retry", "INFO last message repeated 3 times")
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a summary after maxHold, got %q", lines)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	l.Info("retry")
+	l.Info("done")
+	if got := strings.Join(readLog(t, l, dirPath)[2:], "\n"); got != "INFO last message repeated 1 times\nINFO done" {
+		t.Errorf("expected later repeats to be counted anew, got %q", got)
+	}
+}
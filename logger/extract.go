@@ -0,0 +1,60 @@
+package logger
+
+import "context"
+
+// Extractor reports a field to add to entries logged through a context, e.g.
+// a request ID stored in it by middleware. ok is false if ctx doesn't carry
+// the value.
+type Extractor func(ctx context.Context) (key string, value any, ok bool)
+
+// WithExtractor registers an extractor whose field is appended to every entry
+// logged by the context-based functions, such as Info(ctx, ...) and
+// EmitCanonical, after the logger's own fields and those added by WithValues.
+// Extractors run in the order they were registered, on each call. Entries
+// logged directly on a Logger are unaffected.
+//
+//	logger.WithExtractor(func(ctx context.Context) (string, any, bool) {
+//		id, ok := ctx.Value(requestIDKey{}).(string)
+//		return "request_id", id, ok
+//	})
+func WithExtractor(e Extractor) Option {
+	return func(l *Logger) {
+		l.extractors = append(l.extractors, e)
+	}
+}
+
+type valuesKey struct{}
+
+// WithValues returns a copy of ctx carrying key-value pairs that the
+// context-based functions append to every entry, after the logger's own fields.
+// Pairs accumulate across calls, so each middleware layer can add its own
+// without passing a logger around.
+//
+//	ctx = logger.WithValues(ctx, "request_id", id)
+//	logger.Info(ctx, "started") // ... started request_id=42
+func WithValues(ctx context.Context, kv ...any) context.Context {
+	if len(kv) == 0 {
+		return ctx
+	}
+	prev, _ := ctx.Value(valuesKey{}).([]any)
+	values := make([]any, 0, len(prev)+len(kv))
+	values = append(values, prev...)
+	return context.WithValue(ctx, valuesKey{}, append(values, kv...))
+}
+
+// withContext returns l with the pairs added to ctx by WithValues and the
+// fields reported by its extractors added. Callers check the level first, so
+// disabled entries don't pay for the copy and the extractors.
+func (l *Logger) withContext(ctx context.Context) *Logger {
+	kv, _ := ctx.Value(valuesKey{}).([]any)
+	if len(l.extractors) == 0 {
+		return l.With(kv...)
+	}
+	kv = kv[:len(kv):len(kv)] // extractors mustn't append to ctx's pairs
+	for _, e := range l.extractors {
+		if key, value, ok := e(ctx); ok {
+			kv = append(kv, key, value)
+		}
+	}
+	return l.With(kv...)
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+// TestExtractor verifies that extractors add their fields to entries logged
+// through a context, and aren't called for disabled levels.
+func TestExtractor(t *testing.T) {
+	var calls int
+	l, dirPath := newTestLogger(t, "info", WithExtractor(func(ctx context.Context) (string, any, bool) {
+		calls++
+		return "request_id", 42, true
+	}), WithExtractor(func(ctx context.Context) (string, any, bool) {
+		return "tenant", nil, false
+	}))
+	ctx := IntoContext(context.Background(), l.With("app", "api"))
+	Debug(ctx, "dropped")
+	Debugw(ctx, "dropped")
+	Log(ctx, "debug", "dropped")
+	if calls != 0 {
+		t.Errorf("expected no extractor calls for disabled levels, got %d", calls)
+	}
+	Info(ctx, "kept")
+	Warnw(ctx, "kept", "n", 1)
+	l.Info("direct")
+	if calls != 2 {
+		t.Errorf("expected 2 extractor calls, got %d", calls)
+	}
+	checkLines(t, readLog(t, l, dirPath),
+		"INFO kept app=api request_id=42",
+		"WARN kept app=api request_id=42 n=1",
+		"INFO direct")
+}
+
+// TestWithValues verifies that pairs accumulate across WithValues calls
+// without changing the parent context, after the logger's own fields.
+func TestWithValues(t *testing.T) {
+	l, dirPath := newTestLogger(t, "info")
+	ctx := IntoContext(context.Background(), l.With("app", "api"))
+	outer := WithValues(ctx, "a", 1)
+	inner := WithValues(outer, "b", 2)
+	sibling := WithValues(outer, "c", 3)
+	Info(inner, "inner")
+	Infow(sibling, "sibling", "n", 4)
+	Info(outer, "outer")
+	if WithValues(ctx) != ctx {
+		t.Errorf("expected WithValues without pairs to return ctx")
+	}
+	checkLines(t, readLog(t, l, dirPath),
+		"INFO inner app=api a=1 b=2",
+		"INFO sibling app=api a=1 c=3 n=4",
+		"INFO outer app=api a=1")
+}
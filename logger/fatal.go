@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+)
+
+// WithExitFunc sets the function Fatal calls once the entry is flushed,
+// os.Exit by default. Tests can use it to observe a Fatal without exiting.
+func WithExitFunc(exit func(code int)) Option {
+	return func(l *Logger) {
+		l.exit = exit
+	}
+}
+
+// fatalExit flushes the writer, so the fatal entry reaches disk, and exits.
+func (l *Logger) fatalExit() {
+	if err := l.Flush(); err != nil && err != ErrClosed {
+		log.Printf("logger: failed to flush before exit: %v", err)
+	}
+	l.exit(1)
+}
+
+// Fatal logs at fatal level, flushes the writer, and exits with status 1.
+// Unlike log.Fatal after handing the standard logger an rlog writer, the
+// entry is never lost in the buffer.
+func (l *Logger) Fatal(v ...interface{}) {
+	if l.isLevelEnabled(LevelFatal) {
+		l.output(LevelFatal, "fatal", fmt.Sprint(v...), nil)
+	}
+	l.fatalExit()
+}
+
+// Fatal logs with the logger carried by ctx as Logger.Fatal does. Without one,
+// it behaves like log.Fatal.
+func Fatal(ctx context.Context, v ...interface{}) {
+	if l := FromContext(ctx); l != nil {
+		if l.isLevelEnabled(LevelFatal) {
+			l.withContext(ctx).output(LevelFatal, "fatal", fmt.Sprint(v...), nil)
+		}
+		l.fatalExit()
+		return
+	}
+	log.Output(2, fmt.Sprint(v...))
+	os.Exit(1)
+}
+
+func (l *Logger) Fatalf(format string, v ...interface{}) {
+	if l.isLevelEnabled(LevelFatal) {
+		l.output(LevelFatal, "fatalf", fmt.Sprintf(format, v...), nil)
+	}
+	l.fatalExit()
+}
+
+func Fatalf(ctx context.Context, format string, v ...interface{}) {
+	if l := FromContext(ctx); l != nil {
+		if l.isLevelEnabled(LevelFatal) {
+			l.withContext(ctx).output(LevelFatal, "fatalf", fmt.Sprintf(format, v...), nil)
+		}
+		l.fatalExit()
+		return
+	}
+	log.Output(2, fmt.Sprintf(format, v...))
+	os.Exit(1)
+}
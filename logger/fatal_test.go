@@ -0,0 +1,22 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFatal verifies that Fatal writes its entry to disk before exiting with
+// status 1.
+func TestFatal(t *testing.T) {
+	code := -1
+	l, dirPath := newTestLogger(t, "info", WithExitFunc(func(c int) { code = c }))
+	l.Fatalf("cannot %s", "start")
+	if code != 1 {
+		t.Errorf("expected exit status 1, got %d", code)
+	}
+	data, err := os.ReadFile(filepath.Join(dirPath, "latest.log"))
+	if err != nil || string(data) != "FATAL cannot start\n" {
+		t.Errorf("expected the entry to be flushed, got %q, %v", data, err)
+	}
+}
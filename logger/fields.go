@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+)
+
+// badKey is the key recorded for a value given without a string key before it.
+const badKey = "!BADKEY"
+
+// With returns a logger that appends the given key-value pairs to every entry,
+// after those of l. Keys should be strings; a value without a key is recorded
+// under "!BADKEY". The returned logger shares l's writer, level, and style, so
+// closing either closes both.
+//
+//	reqLog := l.With("request_id", id, "user", user)
+//	reqLog.Info("started") // ... started request_id=42 user=ann
+func (l *Logger) With(kv ...any) *Logger {
+	if len(kv) == 0 {
+		return l
+	}
+	fields := make([]any, 0, len(l.fields)+len(kv))
+	fields = append(fields, l.fields...)
+	c := *l
+	c.fields = append(fields, kv...)
+	return &c
+}
+
+// WithPrefix returns a logger that starts every message with prefix and a
+// colon, after any prefixes of l, so a component's identity needn't be added
+// to each message by hand. Like With, it shares l's writer and level.
+//
+//	wl := l.WithPrefix("worker-3")
+//	wl.Info("started") // ... worker-3: started
+func (l *Logger) WithPrefix(prefix string) *Logger {
+	c := *l
+	c.prefix += prefix + ": "
+	return &c
+}
+
+// Child returns a logger for a component, with its own prefix and key-value
+// pairs added to those of l. It's shorthand for l.WithPrefix(prefix).With(kv...).
+func (l *Logger) Child(prefix string, kv ...any) *Logger {
+	return l.WithPrefix(prefix).With(kv...)
+}
+
+// WithCallerSkip returns a logger that reports the caller n frames further up
+// the stack, for wrappers and adapters that log on behalf of their own callers.
+// It otherwise behaves like l.
+func (l *Logger) WithCallerSkip(n int) *Logger {
+	c := *l
+	c.skip += n
+	return &c
+}
+
+func (l *Logger) Debugw(msg string, kv ...any) {
+	if l.isLevelEnabled(LevelDebug) {
+		l.output(LevelDebug, "debugw", msg, kv)
+	}
+}
+
+func Debugw(ctx context.Context, msg string, kv ...any) {
+	if l := FromContext(ctx); l != nil && l.isLevelEnabled(LevelDebug) {
+		l.withContext(ctx).output(LevelDebug, "debugw", msg, kv)
+	}
+}
+
+func (l *Logger) Infow(msg string, kv ...any) {
+	if l.isLevelEnabled(LevelInfo) {
+		l.output(LevelInfo, "infow", msg, kv)
+	}
+}
+
+func Infow(ctx context.Context, msg string, kv ...any) {
+	if l := FromContext(ctx); l != nil && l.isLevelEnabled(LevelInfo) {
+		l.withContext(ctx).output(LevelInfo, "infow", msg, kv)
+	}
+}
+
+func (l *Logger) Warnw(msg string, kv ...any) {
+	if l.isLevelEnabled(LevelWarn) {
+		l.output(LevelWarn, "warnw", msg, kv)
+	}
+}
+
+func Warnw(ctx context.Context, msg string, kv ...any) {
+	if l := FromContext(ctx); l != nil && l.isLevelEnabled(LevelWarn) {
+		l.withContext(ctx).output(LevelWarn, "warnw", msg, kv)
+	}
+}
+
+func (l *Logger) Errorw(msg string, kv ...any) {
+	if l.isLevelEnabled(LevelError) {
+		l.output(LevelError, "errorw", msg, kv)
+	}
+}
+
+func Errorw(ctx context.Context, msg string, kv ...any) {
+	if l := FromContext(ctx); l != nil && l.isLevelEnabled(LevelError) {
+		l.withContext(ctx).output(LevelError, "errorw", msg, kv)
+	}
+}
+
+// appendFields appends the key-value pairs kv to b in logfmt style, each
+// preceded by a space.
+func appendFields(b []byte, kv []any) []byte {
+	for len(kv) > 0 {
+		key, ok := kv[0].(string)
+		if !ok || len(kv) == 1 {
+			key = badKey
+		} else {
+			kv = kv[1:]
+		}
+		b = append(b, ' ')
+		b = append(b, logfmtValue(key)...)
+		b = append(b, '=')
+		b = append(b, logfmtValue(fmt.Sprint(kv[0]))...)
+		kv = kv[1:]
+	}
+	return b
+}
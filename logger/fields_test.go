@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+// TestFields verifies that fields from With and the call are written in
+// logfmt style, with values lacking a key under !BADKEY.
+func TestFields(t *testing.T) {
+	l, dirPath := newTestLogger(t, "info")
+	if l.With() != l {
+		t.Errorf("expected With without pairs to return l")
+	}
+	reqLog := l.With("request_id", 42)
+	reqLog.Infow("user logged in", "user", "ann smith", "odd")
+	reqLog.Debugw("dropped", "user", "ann")
+	l.Errorw("no fields")
+	Warnw(IntoContext(context.Background(), reqLog), "disk low", "pct", 3)
+	checkLines(t, readLog(t, l, dirPath),
+		`INFO user logged in request_id=42 user="ann smith" !BADKEY=odd`,
+		"ERROR no fields",
+		"WARN disk low request_id=42 pct=3")
+}
+
+// TestChild verifies that prefixes accumulate and that Child adds both a
+// prefix and fields.
+func TestChild(t *testing.T) {
+	l, dirPath := newTestLogger(t, "info")
+	l.WithPrefix("worker-3").Info("started")
+	l.Child("db", "shard", 2).WithPrefix("pool").Warnw("slow", "ms", 5)
+	l.Info("plain")
+	checkLines(t, readLog(t, l, dirPath),
+		"INFO worker-3: started",
+		"WARN db: pool: slow shard=2 ms=5",
+		"INFO plain")
+}
@@ -0,0 +1,184 @@
+package logger
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Entry is a log entry, as rendered by a Formatter and seen by hooks.
+type Entry struct {
+	Time   time.Time
+	Level  string // e.g. "info", or a level registered with WithLevel
+	Tag    string // Level as shown in text output, e.g. "INFO"; follows Level
+	PID    int
+	Msg    string // including any prefix from WithPrefix
+	Fields []any  // key-value pairs: the logger's name and fields, then the call's
+	Caller string // file:line of the logging call, or "" if unknown
+
+	order int // ordering value of Level
+}
+
+// Formatter renders entries. Format returns e as one line, without a trailing
+// newline, which the logger adds. It's called concurrently and must not retain
+// or modify e.
+type Formatter interface {
+	Format(e *Entry) []byte
+}
+
+// WithFormatter sets the Formatter that renders entries, a TextFormatter from
+// NewTextFormatter by default. It replaces the format set by WithFormat.
+func WithFormatter(f Formatter) Option {
+	return func(l *Logger) {
+		l.setFormatter(f)
+	}
+}
+
+// setFormatter makes f the logger's formatter.
+func (l *Logger) setFormatter(f Formatter) {
+	l.formatter.Store(&f)
+}
+
+// TextFormatter renders entries as the standard log package would with a
+// "[PID:n]TAG: " prefix, followed by the message and fields in logfmt style:
+//
+//	[PID:4242]INFO: 2024/01/02 15:04:05 user logged in user=ann
+type TextFormatter struct {
+	Flags      int       // log package flags for all levels but debug
+	DebugFlags int       // log package flags for debug entries
+	Style      StyleFunc // decorates level tags, see SetStyle; may be nil
+	Color      bool      // colors level tags and dims metadata, for terminals
+	// TimeLayout, if set, replaces the date and time at every level whose
+	// flags include them, as set by WithTimeFormat.
+	TimeLayout string
+}
+
+// NewTextFormatter returns the default text formatter, with log.LstdFlags
+// and, for debug entries, the full caller.
+func NewTextFormatter() *TextFormatter {
+	return &TextFormatter{Flags: log.LstdFlags, DebugFlags: log.Ldate | log.Ltime | log.Llongfile}
+}
+
+// Format implements Formatter.
+func (f *TextFormatter) Format(e *Entry) []byte {
+	flags := f.Flags
+	if e.Level == "debug" {
+		flags = f.DebugFlags
+	}
+	prefix := f.prefix(e)
+	var b []byte
+	if flags&log.Lmsgprefix == 0 {
+		b = append(b, prefix...)
+	}
+	if f.Color {
+		b = append(b, ansiDim...)
+	}
+	b = appendHeader(b, flags, f.TimeLayout, e.Time, e.Caller)
+	if f.Color {
+		b = append(b, ansiReset...)
+	}
+	if flags&log.Lmsgprefix != 0 {
+		b = append(b, prefix...)
+	}
+	b = append(b, e.Msg...)
+	return appendFields(b, e.Fields)
+}
+
+// prefix returns the PID and level tag for e, styled and colored.
+func (f *TextFormatter) prefix(e *Entry) string {
+	tag := e.Tag
+	if f.Style != nil {
+		pre, suf := f.Style(e.Level)
+		tag = pre + tag + suf
+	}
+	if f.Color {
+		return fmt.Sprintf("%s[PID:%d]%s%s%s%s: ", ansiDim, e.PID, ansiReset, levelColor(e.order), tag, ansiReset)
+	}
+	return fmt.Sprintf("[PID:%d]%s: ", e.PID, tag)
+}
+
+// appendHeader appends the date, time, and caller selected by flags, as the
+// standard log package writes them. A layout, if set, formats the date and
+// time instead.
+func appendHeader(b []byte, flags int, layout string, t time.Time, caller string) []byte {
+	if flags&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		if flags&log.LUTC != 0 {
+			t = t.UTC()
+		}
+		if layout != "" {
+			b = appendTime(b, t, layout)
+			b = append(b, ' ')
+		} else if flags&log.Ldate != 0 {
+			year, month, day := t.Date()
+			b = appendInt(b, year, 4)
+			b = append(b, '/')
+			b = appendInt(b, int(month), 2)
+			b = append(b, '/')
+			b = appendInt(b, day, 2)
+			b = append(b, ' ')
+		}
+		if layout == "" && flags&(log.Ltime|log.Lmicroseconds) != 0 {
+			hour, min, sec := t.Clock()
+			b = appendInt(b, hour, 2)
+			b = append(b, ':')
+			b = appendInt(b, min, 2)
+			b = append(b, ':')
+			b = appendInt(b, sec, 2)
+			if flags&log.Lmicroseconds != 0 {
+				b = append(b, '.')
+				b = appendInt(b, t.Nanosecond()/1e3, 6)
+			}
+			b = append(b, ' ')
+		}
+	}
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		file := caller
+		if file == "" {
+			file = "???:0"
+		}
+		if flags&log.Lshortfile != 0 {
+			file = file[strings.LastIndexByte(file, '/')+1:]
+		}
+		b = append(b, file...)
+		b = append(b, ": "...)
+	}
+	return b
+}
+
+// appendInt appends i to b, zero-padded to width digits.
+func appendInt(b []byte, i, width int) []byte {
+	var buf [20]byte
+	n := len(buf)
+	for i >= 10 || width > 1 {
+		width--
+		n--
+		buf[n] = byte('0' + i%10)
+		i /= 10
+	}
+	n--
+	buf[n] = byte('0' + i)
+	return append(b, buf[n:]...)
+}
+
+// ANSI escape sequences used for color.
+const (
+	ansiReset = "\x1b[0m"
+	ansiDim   = "\x1b[2m"
+)
+
+// levelColor returns the color of a level's tag, by where it's ordered among
+// the built-in levels.
+func levelColor(order int) string {
+	switch {
+	case order < LevelInfo:
+		return "\x1b[36m" // cyan
+	case order < LevelWarn:
+		return "\x1b[32m" // green
+	case order < LevelError:
+		return "\x1b[33m" // yellow
+	case order < LevelPanic:
+		return "\x1b[31m" // red
+	}
+	return "\x1b[1;31m" // bold red
+}
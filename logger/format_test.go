@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// levelMsgFormatter renders entries as "level:msg" and records them.
+type levelMsgFormatter struct {
+	entries chan Entry
+}
+
+func (f levelMsgFormatter) Format(e *Entry) []byte {
+	f.entries <- *e
+	return []byte(e.Level + ":" + e.Msg)
+}
+
+// TestFormatter verifies that a custom Formatter renders every entry, with the
+// logger's name and fields before the call's.
+func TestFormatter(t *testing.T) {
+	f := levelMsgFormatter{entries: make(chan Entry, 1)}
+	l, dirPath := newTestLogger(t, "info", WithFormatter(f))
+	l.Named("db").With("a", 1).Infow("hello", "b", 2)
+	e := <-f.entries
+	if e.Level != "info" || e.Tag != "INFO" || e.PID != os.Getpid() || e.Msg != "hello" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if got := fmt.Sprint(e.Fields); got != "[logger db a 1 b 2]" {
+		t.Errorf("unexpected fields %s", got)
+	}
+	if !strings.Contains(e.Caller, "format_test.go:") {
+		t.Errorf("expected the caller to be this file, got %q", e.Caller)
+	}
+	checkLines(t, readLog(t, l, dirPath), "info:hello")
+}
+
+// TestTextFormatter verifies the text layout, flags, and fields.
+func TestTextFormatter(t *testing.T) {
+	e := &Entry{
+		Time:   time.Date(2024, 1, 2, 15, 4, 5, 123456000, time.UTC),
+		Level:  "info",
+		Tag:    "INFO",
+		PID:    42,
+		Msg:    "hello",
+		Fields: []any{"user", "ann smith", "n", 1, "odd"},
+		Caller: "/src/app/main.go:7",
+		order:  LevelInfo,
+	}
+	for _, tc := range []struct {
+		name string
+		f    TextFormatter
+		want string
+	}{
+		{"standard flags", TextFormatter{Flags: log.LstdFlags}, `[PID:42]INFO: 2024/01/02 15:04:05 hello user="ann smith" n=1 !BADKEY=odd`},
+		{"microseconds and short file", TextFormatter{Flags: log.Ltime | log.Lmicroseconds | log.Lshortfile}, `[PID:42]INFO: 15:04:05.123456 main.go:7: hello user="ann smith" n=1 !BADKEY=odd`},
+		{"message prefix", TextFormatter{Flags: log.Llongfile | log.Lmsgprefix}, `/src/app/main.go:7: [PID:42]INFO: hello user="ann smith" n=1 !BADKEY=odd`},
+		{"time layout", TextFormatter{Flags: log.LstdFlags, TimeLayout: time.RFC3339}, `[PID:42]INFO: 2024-01-02T15:04:05Z hello user="ann smith" n=1 !BADKEY=odd`},
+		{"style", TextFormatter{Style: func(level string) (string, string) { return "<", ">" }}, `[PID:42]<INFO>: hello user="ann smith" n=1 !BADKEY=odd`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := string(tc.f.Format(e)); got != tc.want {
+				t.Errorf("got  %q\nwant %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Hook observes, and may change or veto, entries as they are written. It's the
+// extension point for metrics, error reporting, filtering, and the like.
+//
+// Before is called before an entry is written and may modify it. Changes to Msg
+// and Fields are written; Level may be changed to another known level, and the
+// entry is then written where that level is enabled; Tag follows Level; Time,
+// PID, and Caller are informational. Returning false vetoes the entry, which is
+// then neither written nor passed to later hooks. After is called once the
+// entry is written, with the error from the write, if any.
+//
+// Hooks are called synchronously by the logging goroutine, possibly from many
+// goroutines at once, after redaction. Hooks that buffer entries can implement
+// Flush() error to be flushed by the logger's Flush and Close.
+type Hook interface {
+	Before(e *Entry) bool
+	After(e *Entry, err error)
+}
+
+// HookFunc adapts a function to a Hook that only acts before writing, e.g. to
+// filter or enrich entries.
+type HookFunc func(e *Entry) bool
+
+func (f HookFunc) Before(e *Entry) bool { return f(e) }
+
+func (f HookFunc) After(*Entry, error) {}
+
+// AddHook adds a hook, called after those added before it, to l and all
+// loggers sharing its writer. It's safe to call while logging.
+func (l *Logger) AddHook(h Hook) {
+	l.hookMu.Lock()
+	defer l.hookMu.Unlock()
+	var hooks []Hook
+	if p := l.hooks.Load(); p != nil {
+		hooks = append(hooks, *p...)
+	}
+	hooks = append(hooks, h)
+	l.hooks.Store(&hooks)
+}
+
+// entry returns the Entry for a write of msg and kv at level, reporting the
+// caller depth frames up the stack from entry's caller.
+func (l *Logger) entry(level int, msg string, kv []any, depth int) *Entry {
+	def := l.byOrder[level]
+	e := &Entry{Time: l.now(), Level: def.name, Tag: def.tag, PID: l.pid, Msg: l.prefix + msg, order: level}
+	if l.name != "" {
+		e.Fields = append(e.Fields, "logger", l.name)
+	}
+	e.Fields = append(e.Fields, l.fields...)
+	e.Fields = append(e.Fields, kv...)
+	if _, file, line, ok := runtime.Caller(depth); ok {
+		e.Caller = file + ":" + strconv.Itoa(line)
+	}
+	return e
+}
+
+// flushHooks flushes the hooks that buffer entries.
+func (l *Logger) flushHooks() error {
+	p := l.hooks.Load()
+	if p == nil {
+		return nil
+	}
+	var first error
+	for _, h := range *p {
+		if f, ok := h.(interface{ Flush() error }); ok {
+			if err := f.Flush(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// levelOf returns the ordering value of the named level, or def if unknown.
+func (l *Logger) levelOf(name string, def int) int {
+	if d := l.levels[strings.ToLower(name)]; d != nil {
+		return d.order
+	}
+	return def
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingHook records the entries it sees after writing.
+type recordingHook struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (h *recordingHook) Before(*Entry) bool { return true }
+
+func (h *recordingHook) After(e *Entry, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, *e)
+}
+
+// TestHooks verifies that hooks can veto entries, change their message and
+// level, and see them once written.
+func TestHooks(t *testing.T) {
+	l, dirPath := newTestLogger(t, "info")
+	l.AddHook(HookFunc(func(e *Entry) bool {
+		return !strings.Contains(e.Msg, "secret")
+	}))
+	l.AddHook(HookFunc(func(e *Entry) bool {
+		switch e.Msg {
+		case "db down":
+			e.Level = "ERROR"
+		case "chatty":
+			e.Level = "debug"
+		case "typo":
+			e.Level = "nope"
+		}
+		e.Msg = "[" + e.Msg + "]"
+		return true
+	}))
+	rec := &recordingHook{}
+	l.AddHook(rec)
+	l.Info("a secret")
+	l.Warn("db down")
+	l.Info("chatty")
+	l.Warn("typo")
+	checkLines(t, readLog(t, l, dirPath), "ERROR [db down]", "WARN [typo]")
+	if len(rec.entries) != 3 {
+		t.Fatalf("expected 3 entries after the veto, got %+v", rec.entries)
+	}
+	if e := rec.entries[0]; e.Level != "ERROR" || e.Tag != "ERROR" || e.order != LevelError {
+		t.Errorf("expected the rewritten level to be seen, got %+v", e)
+	}
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Format selects one of the built-in formatters.
+type Format int
+
+const (
+	// Text renders entries with a TextFormatter from NewTextFormatter,
+	// prefixed by the PID and level tag, with fields in logfmt style after
+	// the message.
+	Text Format = iota
+	// JSON renders entries with a JSONFormatter.
+	JSON
+)
+
+// WithFormat sets the output format, Text by default. It replaces the
+// formatter set by WithFormatter.
+func WithFormat(f Format) Option {
+	return func(l *Logger) {
+		if f == JSON {
+			l.setFormatter(JSONFormatter{})
+		} else {
+			l.setFormatter(NewTextFormatter())
+		}
+	}
+}
+
+// JSONFormatter renders entries as JSON objects with the keys "time", "level",
+// "pid", "caller", and "msg", followed by the entry's fields. Fields are
+// encoded with encoding/json; errors are written as their message.
+type JSONFormatter struct {
+	// TimeLayout is the layout of "time", time.RFC3339Nano if empty, as set
+	// by WithTimeFormat. UnixMilli times are numbers.
+	TimeLayout string
+}
+
+// Format implements Formatter.
+func (f JSONFormatter) Format(e *Entry) []byte {
+	b := []byte(`{"time":`)
+	switch f.TimeLayout {
+	case "":
+		b = strconv.AppendQuote(b, e.Time.Format(time.RFC3339Nano))
+	case UnixMilli:
+		b = appendTime(b, e.Time, UnixMilli)
+	default:
+		b = strconv.AppendQuote(b, e.Time.Format(f.TimeLayout))
+	}
+	b = append(b, `,"level":`...)
+	b = strconv.AppendQuote(b, e.Level)
+	b = append(b, `,"pid":`...)
+	b = strconv.AppendInt(b, int64(e.PID), 10)
+	if e.Caller != "" {
+		b = append(b, `,"caller":`...)
+		b = strconv.AppendQuote(b, e.Caller)
+	}
+	b = append(b, `,"msg":`...)
+	b = appendJSONString(b, e.Msg)
+	b = appendJSONFields(b, e.Fields)
+	return append(b, '}')
+}
+
+// appendJSONFields appends the key-value pairs kv to b as JSON object members,
+// each preceded by a comma. Keys are handled as in appendFields.
+func appendJSONFields(b []byte, kv []any) []byte {
+	for len(kv) > 0 {
+		key, ok := kv[0].(string)
+		if !ok || len(kv) == 1 {
+			key = badKey
+		} else {
+			kv = kv[1:]
+		}
+		b = append(b, ',')
+		b = appendJSONString(b, key)
+		b = append(b, ':')
+		b = appendJSONValue(b, kv[0])
+		kv = kv[1:]
+	}
+	return b
+}
+
+// appendJSONValue appends v to b as JSON. Errors are rendered as their message,
+// and values that can't be marshaled as their fmt representation.
+func appendJSONValue(b []byte, v any) []byte {
+	if err, ok := v.(error); ok {
+		return appendJSONString(b, err.Error())
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return appendJSONString(b, fmt.Sprint(v))
+	}
+	return append(b, data...)
+}
+
+// appendJSONString appends s to b as a JSON string.
+func appendJSONString(b []byte, s string) []byte {
+	data, _ := json.Marshal(s) // can't fail for a string
+	return append(b, data...)
+}
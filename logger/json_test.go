@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestJSONFormatter verifies that entries are written as JSON objects with
+// their fields, errors as their message.
+func TestJSONFormatter(t *testing.T) {
+	l, dirPath := newTestLogger(t, "info", WithFormat(JSON))
+	l.Infow("hello", "n", 1, "err", errors.New("boom"), "tags", []string{"a"})
+	lines := readLog(t, l, dirPath)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line, got %q", lines)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("failed to parse %q: %v", lines[0], err)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, fmt.Sprint(got["time"])); err != nil {
+		t.Errorf("expected an RFC 3339 time, got %v", got["time"])
+	}
+	if got["level"] != "info" || got["msg"] != "hello" || got["n"] != 1.0 || got["err"] != "boom" || fmt.Sprint(got["tags"]) != "[a]" {
+		t.Errorf("unexpected object %v", got)
+	}
+	if got["pid"] != float64(os.Getpid()) || !strings.Contains(fmt.Sprint(got["caller"]), "json_test.go:") {
+		t.Errorf("unexpected pid or caller in %v", got)
+	}
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// WithLevel registers an additional named level, e.g. "notice" or "audit", so
+// existing severity taxonomies can be mapped without abusing warn or error.
+// order places it among the built-in levels (see LevelDebug and friends) and
+// must be unique; tag is shown in text output and defaults to the upper-cased
+// name. Entries at a custom level are written with Log, Logf, and Logw.
+func WithLevel(name string, order int, tag string) Option {
+	return func(l *Logger) {
+		l.custom = append(l.custom, &levelDef{name: name, order: order, tag: tag})
+	}
+}
+
+// lookupLevel returns the ordering value of the named level for the logging
+// function kind, reporting unknown or "none" levels.
+func (l *Logger) lookupLevel(kind, level string) (int, bool) {
+	def := l.levels[strings.ToLower(level)]
+	if def == nil {
+		log.Printf("logger: %s called with unknown level %q", kind, level)
+		return 0, false
+	}
+	return def.order, true
+}
+
+// Log logs at the named level, built in or registered with WithLevel. Entries
+// at unknown levels are dropped. Logging at panic or fatal level this way
+// doesn't panic or exit.
+func (l *Logger) Log(level string, v ...interface{}) {
+	if lvl, ok := l.lookupLevel("log", level); ok && l.isLevelEnabled(lvl) {
+		l.output(lvl, "log", fmt.Sprint(v...), nil)
+	}
+}
+
+func Log(ctx context.Context, level string, v ...interface{}) {
+	if l := FromContext(ctx); l != nil {
+		if lvl, ok := l.lookupLevel("log", level); ok && l.isLevelEnabled(lvl) {
+			l.withContext(ctx).output(lvl, "log", fmt.Sprint(v...), nil)
+		}
+	}
+}
+
+func (l *Logger) Logf(level, format string, v ...interface{}) {
+	if lvl, ok := l.lookupLevel("logf", level); ok && l.isLevelEnabled(lvl) {
+		l.output(lvl, "logf", fmt.Sprintf(format, v...), nil)
+	}
+}
+
+func Logf(ctx context.Context, level, format string, v ...interface{}) {
+	if l := FromContext(ctx); l != nil {
+		if lvl, ok := l.lookupLevel("logf", level); ok && l.isLevelEnabled(lvl) {
+			l.withContext(ctx).output(lvl, "logf", fmt.Sprintf(format, v...), nil)
+		}
+	}
+}
+
+// Logw logs msg at the named level with key-value pairs, as Infow does.
+func (l *Logger) Logw(level, msg string, kv ...any) {
+	if lvl, ok := l.lookupLevel("logw", level); ok && l.isLevelEnabled(lvl) {
+		l.output(lvl, "logw", msg, kv)
+	}
+}
+
+func Logw(ctx context.Context, level, msg string, kv ...any) {
+	if l := FromContext(ctx); l != nil {
+		if lvl, ok := l.lookupLevel("logw", level); ok && l.isLevelEnabled(lvl) {
+			l.withContext(ctx).output(lvl, "logw", msg, kv)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestCustomLevels verifies that levels registered with WithLevel are ordered
+// among the built-in ones and written with their tags.
+func TestCustomLevels(t *testing.T) {
+	l, dirPath := newTestLogger(t, "Notice", WithLevel("notice", LevelInfo+5, "NOTE"), WithLevel("audit", LevelFatal+10, ""))
+	l.Info("dropped")
+	l.Log("notice", "kept")
+	l.Logw("AUDIT", "login", "user", "ann")
+	l.Logf("warn", "%d left", 3)
+	l.Log("fatal", "no exit")
+	if !l.Enabled("notice") || l.Enabled("info") || l.Enabled("nope") {
+		t.Errorf("unexpected Enabled results")
+	}
+	checkLines(t, readLog(t, l, dirPath), "NOTE kept", "AUDIT login user=ann", "WARN 3 left", "FATAL no exit")
+	if err := l.SetLevel("verbose"); err == nil || !strings.Contains(err.Error(), "debug, info, notice, warn, error, panic, fatal, audit, none") {
+		t.Errorf("expected the error to list the levels in order, got %v", err)
+	}
+}
+
+// TestInvalidLevels verifies that New rejects levels whose name or order is
+// taken.
+func TestInvalidLevels(t *testing.T) {
+	for _, opt := range []Option{
+		WithLevel("info", 25, ""),
+		WithLevel("trace", LevelDebug, ""),
+		WithLevel("none", 5, ""),
+		WithLevel("", 5, ""),
+	} {
+		if _, err := New(t.TempDir(), "info", opt); !errors.Is(err, ErrInvalidLogLevel) {
+			t.Errorf("expected ErrInvalidLogLevel, got %v", err)
+		}
+	}
+}
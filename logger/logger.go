@@ -1,7 +1,8 @@
-// Package logger provides a leveled, concurrent-safe logging utility
-// built on top of the standard library's log package. Logs are written
-// to disk using rlog and can be filtered by level: debug, info, warn,
-// error, or none.
+// Package logger provides a leveled, concurrent-safe logging utility built on
+// top of rlog. Logs are written to disk using rlog, formatted like the standard
+// library's log package by default, and can be filtered by level: debug, info,
+// warn, error, panic, fatal, or none. Panic and fatal entries are flushed
+// before the logger panics or the process exits.
 //
 // The logger prefixes messages with the process ID and supports
 // dynamic log level changes, log formatting customization, and safe
@@ -25,6 +26,10 @@
 //	l.Info("Application started")
 //	l.Debugf("Configuration value: %s", "some_value")
 //
+//	// Attach key-value fields
+//	reqLog := l.With("request_id", 42)
+//	reqLog.Infow("User logged in", "user", "ann") // ... User logged in request_id=42 user=ann
+//
 //	// Log using context
 //	ctx := context.Background()
 //	ctx = logger.IntoContext(ctx, l) // Place logger into context
@@ -34,40 +39,112 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/Data-Corruption/rlog"
 )
 
+// The built-in levels' ordering values, for placing custom levels among them
+// with WithLevel. Entries at or above the configured level are written.
 const (
-	levelDebug int = iota
-	levelInfo
-	levelWarn
-	levelError
-	levelNone
+	LevelDebug = 10
+	LevelInfo  = 20
+	LevelWarn  = 30
+	LevelError = 40
+	LevelPanic = 50
+	LevelFatal = 60
+
+	levelNone = math.MaxInt
 )
 
 var (
-	ErrInvalidLogLevel = fmt.Errorf("invalid log level")
-	ErrClosed          = fmt.Errorf("logger closed")
+	ErrInvalidLogLevel     = fmt.Errorf("invalid log level")
+	ErrClosed              = fmt.Errorf("logger closed")
+	ErrInvalidSampling     = fmt.Errorf("invalid sampling rate")
+	ErrInvalidMessageLimit = fmt.Errorf("invalid message limit")
+	ErrInvalidTemplate     = fmt.Errorf("invalid format template")
 )
 
+// StyleFunc returns text to place immediately before and after the tag of the
+// given level ("debug", "info", "warn", "error", "panic", or "fatal"), e.g.
+// ANSI color codes or a status glyph.
+type StyleFunc func(level string) (prefix, suffix string)
+
 type Logger struct {
+	*core        // shared with loggers derived by With
+	fields []any // key-value pairs added by With
+	skip   int   // extra stack frames to skip when reporting the caller
+
+	prefix    string        // added to messages by WithPrefix
+	name      string        // set by Named
+	nameLevel *atomic.Int64 // level set by SetLevelFor for name, or levelInherit
+}
+
+// core is the state shared by a logger and those derived from it.
+type core struct {
+	pid     int
 	closeMu sync.Mutex
 	closed  atomic.Uint32
-	level   atomic.Uint32
+	level   atomic.Int64
 	writer  *rlog.Writer
-	// levels use std lib log package for formatting, flags, etc.
-	debug *log.Logger
-	info  *log.Logger
-	warn  *log.Logger
-	error *log.Logger
+	namedMu sync.Mutex
+	named   map[string]*atomic.Int64 // levels of named loggers
+	// levels are fixed once New returns.
+	levels  map[string]*levelDef // by lowercase name
+	byOrder map[int]*levelDef
+	custom  []*levelDef // registered by WithLevel, added to levels by New
+	// sampling rates registered by WithSampling, applied by New.
+	sampling []sampling
+	// extractors add context values to entries; fixed once New returns.
+	extractors []Extractor
+	// msgLimit caps entries per call site, see WithMessageLimit.
+	msgLimit *msgLimiter
+	// dedup collapses repeated entries, see WithDedup.
+	dedup *dedupState
+	// redactors scrub messages and field values, see WithRedactor.
+	redactors []func(string) string
+	// console receives entries at or above consoleLevel, see WithConsole.
+	console          io.Writer
+	consoleMu        sync.Mutex // serializes writes to console
+	consoleLevel     atomic.Int64
+	consoleFormatter atomic.Pointer[Formatter]
+	consoleName      string // level given to WithConsole, parsed by New
+	consoleColor     bool
+	// hooks are replaced, never modified, by AddHook under hookMu.
+	hookMu sync.Mutex
+	hooks  atomic.Pointer[[]Hook]
+	// exit is called by Fatal once the entry is flushed.
+	exit func(code int)
+	// formatter renders entries for the files; replaced, never modified, by
+	// SetFlags under fmtMu, as is consoleFormatter by SetFlags and SetStyle.
+	fmtMu     sync.Mutex
+	formatter atomic.Pointer[Formatter]
+	// templateErr is the error from parsing WithTemplate, returned by New.
+	templateErr error
+	// timeLayout and timeLoc are set by WithTimeFormat and WithTimeLocation.
+	timeLayout string
+	timeLoc    *time.Location
+}
+
+// levelDef describes a level, built in or registered by WithLevel.
+type levelDef struct {
+	name  string
+	order int
+	tag   string // shown in text output, e.g. "INFO"
+
+	sampleN uint64        // write 1 in sampleN entries, see WithSampling
+	seen    atomic.Uint64 // entries considered for sampling
+	dropped atomic.Uint64 // entries dropped by sampling
 }
 
 type ctxKey struct{}
@@ -83,178 +160,298 @@ func FromContext(ctx context.Context) *Logger {
 	return nil
 }
 
+// Option configures a Logger in New.
+type Option func(*Logger)
+
 // New creates a new logger instance with the given directory path and log level.
-// Levels are: debug, info, warn, error, none (case-insensitive).
-func New(dirPath string, level string) (*Logger, error) {
-	if err := os.MkdirAll(dirPath, os.ModePerm); err != nil {
-		return nil, fmt.Errorf("failed to create log directory '%s': %w", dirPath, err)
-	}
+// Levels are: debug, info, warn, error, panic, fatal, none (case-insensitive),
+// plus any registered with WithLevel.
+func New(dirPath string, level string, opts ...Option) (*Logger, error) {
 	var writer *rlog.Writer
 	var err error
-	if writer, err = rlog.New(dirPath, rlog.WithSync()); err != nil {
+	if writer, err = rlog.New(dirPath, rlog.WithMkdirAll(), rlog.WithDirMode(os.ModePerm)); err != nil {
 		return nil, fmt.Errorf("failed to initialize rlog writer in directory '%s': %w", dirPath, err)
 	}
 	pid := os.Getpid()
-	l := &Logger{
-		writer: writer,
-		debug:  log.New(io.Discard, fmt.Sprintf("[PID:%d]DEBUG: ", pid), log.Ldate|log.Ltime|log.Llongfile),
-		info:   log.New(io.Discard, fmt.Sprintf("[PID:%d]INFO: ", pid), log.LstdFlags),
-		warn:   log.New(io.Discard, fmt.Sprintf("[PID:%d]WARN: ", pid), log.LstdFlags),
-		error:  log.New(io.Discard, fmt.Sprintf("[PID:%d]ERROR: ", pid), log.LstdFlags),
+	l := &Logger{core: &core{
+		pid:     pid,
+		writer:  writer,
+		levels:  make(map[string]*levelDef),
+		byOrder: make(map[int]*levelDef),
+		named:   make(map[string]*atomic.Int64),
+		exit:    os.Exit,
+	}}
+	l.setFormatter(NewTextFormatter())
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.templateErr != nil {
+		writer.Close()
+		return nil, l.templateErr
+	}
+	l.applyTimeFormat()
+	for _, def := range []*levelDef{
+		{name: "debug", order: LevelDebug},
+		{name: "info", order: LevelInfo},
+		{name: "warn", order: LevelWarn},
+		{name: "error", order: LevelError},
+		{name: "panic", order: LevelPanic},
+		{name: "fatal", order: LevelFatal},
+	} {
+		l.addLevel(def)
+	}
+	for _, def := range l.custom {
+		if err := l.addLevel(def); err != nil {
+			writer.Close()
+			return nil, err
+		}
+	}
+	if err := l.applySampling(); err != nil {
+		writer.Close()
+		return nil, err
+	}
+	if err := l.setupConsole(); err != nil {
+		writer.Close()
+		return nil, err
+	}
+	if m := l.msgLimit; m != nil && (m.n < 1 || m.per <= 0) {
+		writer.Close()
+		return nil, fmt.Errorf("cannot limit messages to %d per %v. %w", m.n, m.per, ErrInvalidMessageLimit)
 	}
 	l.closed.Store(0)
-	l.level.Store(uint32(levelNone))
+	l.level.Store(levelNone)
 	return l, l.SetLevel(level)
 }
 
+// addLevel adds def to the level table. Names and ordering values must be
+// unique.
+func (l *Logger) addLevel(def *levelDef) error {
+	def.name = strings.ToLower(def.name)
+	if def.name == "" || def.name == "none" || l.levels[def.name] != nil || l.byOrder[def.order] != nil || def.order == levelNone {
+		return fmt.Errorf("cannot register level %q with order %d: name or order taken. %w", def.name, def.order, ErrInvalidLogLevel)
+	}
+	if def.tag == "" {
+		def.tag = strings.ToUpper(def.name)
+	}
+	l.levels[def.name] = def
+	l.byOrder[def.order] = def
+	return nil
+}
+
+// output writes msg, followed by the logger's fields and kv, at level. It must
+// be called directly by the exported logging function so that the reported
+// caller is right.
+func (l *Logger) output(level int, kind, msg string, kv []any) {
+	if !l.sample(level) {
+		return
+	}
+	if l.msgLimit != nil && !l.limitMessage(level, kind) {
+		return
+	}
+	if l.dedup != nil {
+		l.writeDedup(level, kind, msg, kv)
+		return
+	}
+	l.write(level, kind, msg, kv, 4+l.skip)
+}
+
+// write writes an entry as output does, reporting the caller depth frames up
+// the stack from write.
+func (l *Logger) write(level int, kind, msg string, kv []any, depth int) {
+	if len(l.redactors) > 0 {
+		c := *l
+		c.fields = l.redactFields(l.fields)
+		l, msg, kv = &c, l.redact(msg), l.redactFields(kv)
+	}
+	e := l.entry(level, msg, kv, depth)
+	var hooks []Hook
+	if p := l.hooks.Load(); p != nil {
+		hooks = *p
+		for _, h := range hooks {
+			if !h.Before(e) {
+				return
+			}
+		}
+		if level = l.levelOf(e.Level, level); level != e.order {
+			e.order, e.Tag = level, l.byOrder[level].tag
+		}
+	}
+	var err error
+	if l.fileEnabled(level) {
+		_, err = l.writer.Write(line((*l.formatter.Load()).Format(e)))
+		if errors.Is(err, rlog.ErrClosed) {
+			err = nil // raced with Close
+		}
+	}
+	if l.consoleEnabled(level) {
+		b := line((*l.consoleFormatter.Load()).Format(e))
+		l.consoleMu.Lock()
+		l.console.Write(b)
+		l.consoleMu.Unlock()
+	}
+	if err != nil {
+		log.Printf("logger: failed to write %s log entry: %v", kind, err)
+	}
+	for _, h := range hooks {
+		h.After(e, err)
+	}
+}
+
+// line terminates b with a newline unless it already ends with one, as the
+// standard log package does.
+func line(b []byte) []byte {
+	if len(b) == 0 || b[len(b)-1] != '\n' {
+		b = append(b, '\n')
+	}
+	return b
+}
+
+// text returns msg with the logger's prefix, name, fields, and kv, as
+// TextFormatter writes them after the metadata.
+func (l *Logger) text(msg string, kv []any) string {
+	msg = l.prefix + msg
+	if l.name == "" && len(l.fields) == 0 && len(kv) == 0 {
+		return msg
+	}
+	b := []byte(msg)
+	if l.name != "" {
+		b = appendFields(b, []any{"logger", l.name})
+	}
+	b = appendFields(b, l.fields)
+	return string(appendFields(b, kv))
+}
+
+// Enabled reports whether entries at the named level are currently written.
+// Levels are as in SetLevel; unknown levels report false.
+func (l *Logger) Enabled(level string) bool {
+	lvl, ok := l.parseLevel(level)
+	return ok && lvl != levelNone && l.isLevelEnabled(lvl)
+}
+
+// parseLevel returns the ordering value of the level named by s,
+// case-insensitively.
+func (l *Logger) parseLevel(s string) (int, bool) {
+	s = strings.ToLower(s)
+	if s == "none" {
+		return levelNone, true
+	}
+	if def := l.levels[s]; def != nil {
+		return def.order, true
+	}
+	return 0, false
+}
+
 func (l *Logger) isLevelEnabled(level int) bool {
 	if l.IsClosed() {
 		return false
 	}
-	return l.level.Load() <= uint32(level)
+	return l.fileEnabled(level) || l.consoleEnabled(level)
 }
 
-func (l *Logger) Debug(v ...interface{}) {
-	if l.isLevelEnabled(levelDebug) {
-		if err := l.debug.Output(2, fmt.Sprint(v...)); err != nil {
-			log.Printf("logger: failed to write debug log entry: %v", err)
+// fileEnabled reports whether entries at level are written to the log files,
+// per the logger's level or that set for its name.
+func (l *Logger) fileEnabled(level int) bool {
+	if l.nameLevel != nil {
+		if min := l.nameLevel.Load(); min != levelInherit {
+			return min <= int64(level)
 		}
 	}
+	return l.level.Load() <= int64(level)
+}
+
+func (l *Logger) Debug(v ...interface{}) {
+	if l.isLevelEnabled(LevelDebug) {
+		l.output(LevelDebug, "debug", fmt.Sprint(v...), nil)
+	}
 }
 
 func Debug(ctx context.Context, v ...interface{}) {
-	if l := FromContext(ctx); l != nil {
-		if l.isLevelEnabled(levelDebug) {
-			if err := l.debug.Output(2, fmt.Sprint(v...)); err != nil {
-				log.Printf("logger: failed to write debug log entry: %v", err)
-			}
-		}
+	if l := FromContext(ctx); l != nil && l.isLevelEnabled(LevelDebug) {
+		l.withContext(ctx).output(LevelDebug, "debug", fmt.Sprint(v...), nil)
 	}
 }
 
 func (l *Logger) Debugf(format string, v ...interface{}) {
-	if l.isLevelEnabled(levelDebug) {
-		if err := l.debug.Output(2, fmt.Sprintf(format, v...)); err != nil {
-			log.Printf("logger: failed to write debugf log entry: %v", err)
-		}
+	if l.isLevelEnabled(LevelDebug) {
+		l.output(LevelDebug, "debugf", fmt.Sprintf(format, v...), nil)
 	}
 }
 
 func Debugf(ctx context.Context, format string, v ...interface{}) {
-	if l := FromContext(ctx); l != nil {
-		if l.isLevelEnabled(levelDebug) {
-			if err := l.debug.Output(2, fmt.Sprintf(format, v...)); err != nil {
-				log.Printf("logger: failed to write debugf log entry: %v", err)
-			}
-		}
+	if l := FromContext(ctx); l != nil && l.isLevelEnabled(LevelDebug) {
+		l.withContext(ctx).output(LevelDebug, "debugf", fmt.Sprintf(format, v...), nil)
 	}
 }
 
 func (l *Logger) Info(v ...interface{}) {
-	if l.isLevelEnabled(levelInfo) {
-		if err := l.info.Output(2, fmt.Sprint(v...)); err != nil {
-			log.Printf("logger: failed to write info log entry: %v", err)
-		}
+	if l.isLevelEnabled(LevelInfo) {
+		l.output(LevelInfo, "info", fmt.Sprint(v...), nil)
 	}
 }
 
 func Info(ctx context.Context, v ...interface{}) {
-	if l := FromContext(ctx); l != nil {
-		if l.isLevelEnabled(levelInfo) {
-			if err := l.info.Output(2, fmt.Sprint(v...)); err != nil {
-				log.Printf("logger: failed to write info log entry: %v", err)
-			}
-		}
+	if l := FromContext(ctx); l != nil && l.isLevelEnabled(LevelInfo) {
+		l.withContext(ctx).output(LevelInfo, "info", fmt.Sprint(v...), nil)
 	}
 }
 
 func (l *Logger) Infof(format string, v ...interface{}) {
-	if l.isLevelEnabled(levelInfo) {
-		if err := l.info.Output(2, fmt.Sprintf(format, v...)); err != nil {
-			log.Printf("logger: failed to write infof log entry: %v", err)
-		}
+	if l.isLevelEnabled(LevelInfo) {
+		l.output(LevelInfo, "infof", fmt.Sprintf(format, v...), nil)
 	}
 }
 
 func Infof(ctx context.Context, format string, v ...interface{}) {
-	if l := FromContext(ctx); l != nil {
-		if l.isLevelEnabled(levelInfo) {
-			if err := l.info.Output(2, fmt.Sprintf(format, v...)); err != nil {
-				log.Printf("logger: failed to write infof log entry: %v", err)
-			}
-		}
+	if l := FromContext(ctx); l != nil && l.isLevelEnabled(LevelInfo) {
+		l.withContext(ctx).output(LevelInfo, "infof", fmt.Sprintf(format, v...), nil)
 	}
 }
 
 func (l *Logger) Warn(v ...interface{}) {
-	if l.isLevelEnabled(levelWarn) {
-		if err := l.warn.Output(2, fmt.Sprint(v...)); err != nil {
-			log.Printf("logger: failed to write warn log entry: %v", err)
-		}
+	if l.isLevelEnabled(LevelWarn) {
+		l.output(LevelWarn, "warn", fmt.Sprint(v...), nil)
 	}
 }
 
 func Warn(ctx context.Context, v ...interface{}) {
-	if l := FromContext(ctx); l != nil {
-		if l.isLevelEnabled(levelWarn) {
-			if err := l.warn.Output(2, fmt.Sprint(v...)); err != nil {
-				log.Printf("logger: failed to write warn log entry: %v", err)
-			}
-		}
+	if l := FromContext(ctx); l != nil && l.isLevelEnabled(LevelWarn) {
+		l.withContext(ctx).output(LevelWarn, "warn", fmt.Sprint(v...), nil)
 	}
 }
 
 func (l *Logger) Warnf(format string, v ...interface{}) {
-	if l.isLevelEnabled(levelWarn) {
-		if err := l.warn.Output(2, fmt.Sprintf(format, v...)); err != nil {
-			log.Printf("logger: failed to write warnf log entry: %v", err)
-		}
+	if l.isLevelEnabled(LevelWarn) {
+		l.output(LevelWarn, "warnf", fmt.Sprintf(format, v...), nil)
 	}
 }
 
 func Warnf(ctx context.Context, format string, v ...interface{}) {
-	if l := FromContext(ctx); l != nil {
-		if l.isLevelEnabled(levelWarn) {
-			if err := l.warn.Output(2, fmt.Sprintf(format, v...)); err != nil {
-				log.Printf("logger: failed to write warnf log entry: %v", err)
-			}
-		}
+	if l := FromContext(ctx); l != nil && l.isLevelEnabled(LevelWarn) {
+		l.withContext(ctx).output(LevelWarn, "warnf", fmt.Sprintf(format, v...), nil)
 	}
 }
 
 func (l *Logger) Error(v ...interface{}) {
-	if l.isLevelEnabled(levelError) {
-		if err := l.error.Output(2, fmt.Sprint(v...)); err != nil {
-			log.Printf("logger: failed to write error log entry: %v", err)
-		}
+	if l.isLevelEnabled(LevelError) {
+		l.output(LevelError, "error", fmt.Sprint(v...), nil)
 	}
 }
 
 func Error(ctx context.Context, v ...interface{}) {
-	if l := FromContext(ctx); l != nil {
-		if l.isLevelEnabled(levelError) {
-			if err := l.error.Output(2, fmt.Sprint(v...)); err != nil {
-				log.Printf("logger: failed to write error log entry: %v", err)
-			}
-		}
+	if l := FromContext(ctx); l != nil && l.isLevelEnabled(LevelError) {
+		l.withContext(ctx).output(LevelError, "error", fmt.Sprint(v...), nil)
 	}
 }
 
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	if l.isLevelEnabled(levelError) {
-		if err := l.error.Output(2, fmt.Sprintf(format, v...)); err != nil {
-			log.Printf("logger: failed to write errorf log entry: %v", err)
-		}
+	if l.isLevelEnabled(LevelError) {
+		l.output(LevelError, "errorf", fmt.Sprintf(format, v...), nil)
 	}
 }
 
 func Errorf(ctx context.Context, format string, v ...interface{}) {
-	if l := FromContext(ctx); l != nil {
-		if l.isLevelEnabled(levelError) {
-			if err := l.error.Output(2, fmt.Sprintf(format, v...)); err != nil {
-				log.Printf("logger: failed to write errorf log entry: %v", err)
-			}
-		}
+	if l := FromContext(ctx); l != nil && l.isLevelEnabled(LevelError) {
+		l.withContext(ctx).output(LevelError, "errorf", fmt.Sprintf(format, v...), nil)
 	}
 }
 
@@ -262,70 +459,91 @@ func (l *Logger) IsClosed() bool {
 	return l.closed.Load() == 1
 }
 
-// SetFlags sets the flags for all loggers.
-// debugFlag and stdFlag are the flags from std lib log package.
+// SetFlags sets the flags for all loggers. It only affects a TextFormatter.
+// debugFlag and stdFlag are the flags from std lib log package; stdFlag
+// applies to every level but debug.
 func (l *Logger) SetFlags(debugFlag, stdFlag int) {
-	l.debug.SetFlags(debugFlag)
-	l.info.SetFlags(stdFlag)
-	l.warn.SetFlags(stdFlag)
-	l.error.SetFlags(stdFlag)
+	l.updateText(func(f *TextFormatter) {
+		f.DebugFlags, f.Flags = debugFlag, stdFlag
+	}, &l.formatter, &l.consoleFormatter)
+}
+
+// SetStyle sets a hook that decorates each level's tag on the console given to
+// WithConsole, letting products brand their CLI output with symbols or color
+// themes. The files keep plain tags. A nil style restores plain tags. It only
+// affects a TextFormatter.
+func (l *Logger) SetStyle(style StyleFunc) {
+	l.updateText(func(f *TextFormatter) {
+		f.Style = style
+	}, &l.consoleFormatter)
+}
+
+// updateText replaces the formatters in ptrs that are TextFormatters with
+// copies changed by update.
+func (l *Logger) updateText(update func(f *TextFormatter), ptrs ...*atomic.Pointer[Formatter]) {
+	l.fmtMu.Lock()
+	defer l.fmtMu.Unlock()
+	for _, p := range ptrs {
+		if cur := p.Load(); cur != nil {
+			if tf, ok := (*cur).(*TextFormatter); ok {
+				c := *tf
+				update(&c)
+				var f Formatter = &c
+				p.Store(&f)
+			}
+		}
+	}
 }
 
 // SetLevel sets the minimum log level to output.
-// Levels are: debug, info, warn, error, none (case-insensitive)
+// Levels are: debug, info, warn, error, panic, fatal, none (case-insensitive),
+// plus any registered with WithLevel.
 func (l *Logger) SetLevel(level string) error {
 	l.closeMu.Lock()
 	defer l.closeMu.Unlock()
 	if l.IsClosed() {
 		return ErrClosed
 	}
-	var newLevel uint32
-	switch strings.ToLower(level) {
-	case "debug":
-		newLevel = uint32(levelDebug)
-		l.debug.SetOutput(l.writer)
-		l.info.SetOutput(l.writer)
-		l.warn.SetOutput(l.writer)
-		l.error.SetOutput(l.writer)
-	case "info":
-		newLevel = uint32(levelInfo)
-		l.debug.SetOutput(io.Discard)
-		l.info.SetOutput(l.writer)
-		l.warn.SetOutput(l.writer)
-		l.error.SetOutput(l.writer)
-	case "warn":
-		newLevel = uint32(levelWarn)
-		l.debug.SetOutput(io.Discard)
-		l.info.SetOutput(io.Discard)
-		l.warn.SetOutput(l.writer)
-		l.error.SetOutput(l.writer)
-	case "error":
-		newLevel = uint32(levelError)
-		l.debug.SetOutput(io.Discard)
-		l.info.SetOutput(io.Discard)
-		l.warn.SetOutput(io.Discard)
-		l.error.SetOutput(l.writer)
-	case "none":
-		newLevel = uint32(levelNone)
-		l.debug.SetOutput(io.Discard)
-		l.info.SetOutput(io.Discard)
-		l.warn.SetOutput(io.Discard)
-		l.error.SetOutput(io.Discard)
-	default:
-		return fmt.Errorf("invalid log level: '%s'. Valid levels are: debug, info, warn, error, none. %w", level, ErrInvalidLogLevel)
-	}
-	l.level.Store(newLevel)
+	newLevel, ok := l.parseLevel(level)
+	if !ok {
+		return l.invalidLevel(level)
+	}
+	l.level.Store(int64(newLevel))
 	return nil
 }
 
+// invalidLevel returns the error for an unknown level name.
+func (l *Logger) invalidLevel(level string) error {
+	return fmt.Errorf("invalid log level: '%s'. Valid levels are: %s, none. %w", level, strings.Join(l.levelNames(), ", "), ErrInvalidLogLevel)
+}
+
+// levelNames returns the names of the levels in order.
+func (l *Logger) levelNames() []string {
+	orders := make([]int, 0, len(l.byOrder))
+	for order := range l.byOrder {
+		orders = append(orders, order)
+	}
+	sort.Ints(orders)
+	names := make([]string, len(orders))
+	for i, order := range orders {
+		names[i] = l.byOrder[order].name
+	}
+	return names
+}
+
 func (l *Logger) Flush() error {
 	l.closeMu.Lock()
 	defer l.closeMu.Unlock()
 	if l.IsClosed() {
 		return ErrClosed
 	}
-	if err := l.writer.Flush(); err != nil {
-		return fmt.Errorf("failed to flush rlog writer: %w", err)
+	// Flush hooks regardless, so Fatal still reports its entry.
+	werr, herr := l.writer.Flush(), l.flushHooks()
+	if werr != nil {
+		return fmt.Errorf("failed to flush rlog writer: %w", werr)
+	}
+	if herr != nil {
+		return fmt.Errorf("failed to flush hooks: %w", herr)
 	}
 	return nil
 }
@@ -336,17 +554,19 @@ func (l *Logger) Close() error {
 	if l.IsClosed() {
 		return ErrClosed
 	}
+	if l.msgLimit != nil {
+		l.summarizeSuppressed()
+	}
+	if l.dedup != nil {
+		l.dedup.stop()
+	}
 	l.closed.Store(1)
-	l.debug.SetOutput(io.Discard)
-	l.info.SetOutput(io.Discard)
-	l.warn.SetOutput(io.Discard)
-	l.error.SetOutput(io.Discard)
-	if l.writer != nil {
-		err := l.writer.Close()
-		l.writer = nil
-		if err != nil {
-			return fmt.Errorf("failed to close rlog writer: %w", err)
-		}
+	herr := l.flushHooks()
+	if err := l.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close rlog writer: %w", err)
+	}
+	if herr != nil {
+		return fmt.Errorf("failed to flush hooks: %w", herr)
 	}
 	return nil
 }
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestLogger creates a logger in a temporary directory that writes entries
+// as "TAG msg fields", closed when the test ends.
+func newTestLogger(t *testing.T, level string, opts ...Option) (*Logger, string) {
+	t.Helper()
+	dirPath := t.TempDir()
+	opts = append([]Option{WithTemplate("{tag} {msg} {fields}")}, opts...)
+	l, err := New(dirPath, level, opts...)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l, dirPath
+}
+
+// readLog flushes l and returns the lines of the active log file in dirPath.
+func readLog(t *testing.T, l *Logger, dirPath string) []string {
+	t.Helper()
+	if err := l.Flush(); err != nil && !errors.Is(err, ErrClosed) {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dirPath, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// checkLines reports a mismatch between the logged lines and want.
+func checkLines(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lines mismatch:\ngot  %q\nwant %q", got, want)
+	}
+}
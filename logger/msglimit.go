@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WithMessageLimit writes at most n entries per interval from each call site,
+// so a flapping dependency logging the same error in a loop can't flood the
+// log. Once an interval with suppressed entries ends, the next entry from the
+// site is preceded by "suppressed M similar messages" at the same level, with
+// the site under the "site" key. Summaries still pending are written by Close.
+// New fails with ErrInvalidMessageLimit for n < 1 or a non-positive interval.
+func WithMessageLimit(n int, interval time.Duration) Option {
+	return func(l *Logger) {
+		l.msgLimit = &msgLimiter{n: n, per: interval, sites: make(map[uintptr]*siteCount)}
+	}
+}
+
+// msgLimiter counts entries per call site in fixed intervals.
+type msgLimiter struct {
+	n   int
+	per time.Duration
+
+	mu    sync.Mutex
+	sites map[uintptr]*siteCount // by program counter of the call site
+}
+
+// siteCount is a call site's count for the current interval.
+type siteCount struct {
+	start      time.Time
+	level      int
+	count      int
+	suppressed int
+}
+
+// allow reports whether an entry from the call site pc may be written at t,
+// along with the number of entries suppressed in the site's previous interval,
+// which the caller should summarize.
+func (m *msgLimiter) allow(pc uintptr, level int, t time.Time) (ok bool, suppressed int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.sites[pc]
+	if s == nil {
+		s = &siteCount{start: t}
+		m.sites[pc] = s
+	}
+	if t.Sub(s.start) >= m.per {
+		suppressed = s.suppressed
+		*s = siteCount{start: t}
+	}
+	s.level = level
+	if s.count < m.n {
+		s.count++
+		return true, suppressed
+	}
+	s.suppressed++
+	return false, suppressed
+}
+
+// limitMessage applies the message limit to an entry at level from the
+// caller of the exported logging function that called output, writing the
+// summary of earlier suppressed entries if due. It reports whether the entry
+// may be written.
+func (l *Logger) limitMessage(level int, kind string) bool {
+	var pcs [1]uintptr
+	runtime.Callers(4+l.skip, pcs[:]) // skip Callers, limitMessage, output, and the logging function
+	ok, suppressed := l.msgLimit.allow(pcs[0], level, time.Now())
+	if suppressed > 0 {
+		l.write(level, kind, suppressedMsg(suppressed), []any{"site", siteName(pcs[0])}, 5+l.skip)
+	}
+	return ok
+}
+
+// summarizeSuppressed writes the summaries of all entries suppressed so far,
+// in call site order, and resets the counts.
+func (l *Logger) summarizeSuppressed() {
+	m := l.msgLimit
+	m.mu.Lock()
+	type pending struct {
+		site       string
+		level      int
+		suppressed int
+	}
+	var all []pending
+	for pc, s := range m.sites {
+		if s.suppressed > 0 {
+			all = append(all, pending{siteName(pc), s.level, s.suppressed})
+			s.suppressed = 0
+		}
+	}
+	m.mu.Unlock()
+	sort.Slice(all, func(i, j int) bool { return all[i].site < all[j].site })
+	for _, p := range all {
+		l.write(p.level, "summary", suppressedMsg(p.suppressed), []any{"site", p.site}, 4)
+	}
+}
+
+func suppressedMsg(n int) string {
+	return fmt.Sprintf("suppressed %d similar messages", n)
+}
+
+// siteName returns the file:line of the call site pc.
+func siteName(pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return frame.File + ":" + strconv.Itoa(frame.Line)
+}
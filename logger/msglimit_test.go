@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMessageLimit verifies that entries are limited per call site and that
+// suppressed entries are summarized once the interval ends and on Close.
+func TestMessageLimit(t *testing.T) {
+	l, dirPath := newTestLogger(t, "info", WithMessageLimit(2, 100*time.Millisecond))
+	for i := 0; i < 6; i++ {
+		if i == 5 {
+			time.Sleep(100 * time.Millisecond) // end the interval
+		}
+		l.Warnf("down %d", i)
+		if i < 5 {
+			l.Info("other site")
+		}
+	}
+	lines := readLog(t, l, dirPath)
+	if len(lines) != 6 {
+		t.Fatalf("expected 6 lines, got %q", lines)
+	}
+	checkLines(t, lines[:4], "WARN down 0", "INFO other site", "WARN down 1", "INFO other site")
+	site := regexp.MustCompile(`^WARN suppressed 3 similar messages site=\S+/msglimit_test\.go:\d+$`)
+	if !site.MatchString(lines[4]) || lines[5] != "WARN down 5" {
+		t.Errorf("expected a summary before the next entry, got %q", lines[4:])
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	lines = readLog(t, l, dirPath)[6:]
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "INFO suppressed 3 similar messages site=") {
+		t.Errorf("expected Close to summarize the other site, got %q", lines)
+	}
+}
+
+// TestInvalidMessageLimit verifies that New rejects limits that would drop
+// every entry.
+func TestInvalidMessageLimit(t *testing.T) {
+	for _, opt := range []Option{WithMessageLimit(0, time.Second), WithMessageLimit(1, 0)} {
+		if _, err := New(t.TempDir(), "info", opt); !errors.Is(err, ErrInvalidMessageLimit) {
+			t.Errorf("expected ErrInvalidMessageLimit, got %v", err)
+		}
+	}
+}
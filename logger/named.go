@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// levelInherit marks a named logger without a level of its own.
+const levelInherit int64 = math.MinInt64
+
+// Named returns a sub-logger for a subsystem, e.g. "db", whose level can be
+// set on its own with SetLevelFor. Entries carry the name under the "logger"
+// key. Naming a named logger joins the names with a dot, as in "db.pool".
+// Until a level is set for its name, a named logger follows l's level.
+//
+//	dbLog := l.Named("db")
+//	l.SetLevelFor("db", "debug") // debug entries from dbLog only
+func (l *Logger) Named(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	c := *l
+	c.name = name
+	c.nameLevel = l.nameLevelFor(name)
+	return &c
+}
+
+// SetLevelFor sets the minimum level of loggers returned by Named for name,
+// including those created later, regardless of the level set by SetLevel.
+// Sub-loggers of a named logger have names of their own and are unaffected. An
+// empty level reverts name to following the logger's level.
+func (l *Logger) SetLevelFor(name, level string) error {
+	if l.IsClosed() {
+		return ErrClosed
+	}
+	if level == "" {
+		l.nameLevelFor(name).Store(levelInherit)
+		return nil
+	}
+	newLevel, ok := l.parseLevel(level)
+	if !ok {
+		return l.invalidLevel(level)
+	}
+	l.nameLevelFor(name).Store(int64(newLevel))
+	return nil
+}
+
+// nameLevelFor returns the level shared by loggers named name, creating it
+// if needed.
+func (l *Logger) nameLevelFor(name string) *atomic.Int64 {
+	l.namedMu.Lock()
+	defer l.namedMu.Unlock()
+	lvl := l.named[name]
+	if lvl == nil {
+		lvl = new(atomic.Int64)
+		lvl.Store(levelInherit)
+		l.named[name] = lvl
+	}
+	return lvl
+}
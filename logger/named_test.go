@@ -0,0 +1,32 @@
+package logger
+
+import "testing"
+
+// TestNamed verifies that named loggers carry their name and follow the level
+// set for it, or the logger's level until one is set.
+func TestNamed(t *testing.T) {
+	l, dirPath := newTestLogger(t, "info")
+	db := l.Named("db")
+	pool := db.Named("pool")
+	db.Debug("dropped")
+	if err := l.SetLevelFor("db", "debug"); err != nil {
+		t.Fatalf("SetLevelFor failed: %v", err)
+	}
+	db.Debug("kept")
+	pool.Debug("dropped")
+	l.Debug("dropped")
+	if err := l.SetLevelFor("db.pool", "error"); err != nil {
+		t.Fatalf("SetLevelFor failed: %v", err)
+	}
+	pool.Warn("dropped")
+	l.Named("db").Named("pool").Error("kept")
+	if err := l.SetLevelFor("db", ""); err != nil {
+		t.Fatalf("SetLevelFor failed: %v", err)
+	}
+	db.Debug("dropped")
+	db.Info("kept")
+	checkLines(t, readLog(t, l, dirPath),
+		"DEBUG kept logger=db",
+		"ERROR kept logger=db.pool",
+		"INFO kept logger=db")
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// flushBeforePanic flushes the writer so the panic entry reaches disk even if
+// the panic is never recovered.
+func (l *Logger) flushBeforePanic() {
+	if err := l.Flush(); err != nil && err != ErrClosed {
+		log.Printf("logger: failed to flush before panic: %v", err)
+	}
+}
+
+// Panic logs at panic level, flushes the writer, and panics with the message,
+// like log.Panic.
+func (l *Logger) Panic(v ...interface{}) {
+	s := fmt.Sprint(v...)
+	if l.isLevelEnabled(LevelPanic) {
+		l.output(LevelPanic, "panic", s, nil)
+	}
+	l.flushBeforePanic()
+	panic(s)
+}
+
+// Panic logs with the logger carried by ctx as Logger.Panic does. Without one,
+// it behaves like log.Panic.
+func Panic(ctx context.Context, v ...interface{}) {
+	s := fmt.Sprint(v...)
+	if l := FromContext(ctx); l != nil {
+		if l.isLevelEnabled(LevelPanic) {
+			l.withContext(ctx).output(LevelPanic, "panic", s, nil)
+		}
+		l.flushBeforePanic()
+	} else {
+		log.Output(2, s)
+	}
+	panic(s)
+}
+
+func (l *Logger) Panicf(format string, v ...interface{}) {
+	s := fmt.Sprintf(format, v...)
+	if l.isLevelEnabled(LevelPanic) {
+		l.output(LevelPanic, "panicf", s, nil)
+	}
+	l.flushBeforePanic()
+	panic(s)
+}
+
+func Panicf(ctx context.Context, format string, v ...interface{}) {
+	s := fmt.Sprintf(format, v...)
+	if l := FromContext(ctx); l != nil {
+		if l.isLevelEnabled(LevelPanic) {
+			l.withContext(ctx).output(LevelPanic, "panicf", s, nil)
+		}
+		l.flushBeforePanic()
+	} else {
+		log.Output(2, s)
+	}
+	panic(s)
+}
@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestPanic verifies that Panic writes its entry to disk before panicking
+// with the message.
+func TestPanic(t *testing.T) {
+	l, dirPath := newTestLogger(t, "info")
+	func() {
+		defer func() {
+			if r := recover(); r != "out of range 7" {
+				t.Errorf("expected to panic with the message, got %v", r)
+			}
+		}()
+		l.Panicf("out of range %d", 7)
+	}()
+	data, err := os.ReadFile(filepath.Join(dirPath, "latest.log"))
+	if err != nil || string(data) != "PANIC out of range 7\n" {
+		t.Errorf("expected the entry to be flushed, got %q, %v", data, err)
+	}
+}
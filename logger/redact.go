@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Redacted replaces the text removed by the built-in redactors.
+const Redacted = "[REDACTED]"
+
+// WithRedactor registers a function that scrubs sensitive data, such as
+// tokens or personal information, from messages and field values before they
+// are written. Redactors run in the order they were registered. Values other
+// than strings, numbers, and booleans are redacted as text and written as the
+// redacted text if it differs. Keys, prefixes, and names are not redacted.
+//
+//	l, err := logger.New("logs", "info",
+//		logger.WithRedactor(logger.RedactBearerTokens),
+//		logger.WithRedactor(logger.RedactEmails))
+func WithRedactor(f func(string) string) Option {
+	return func(l *Logger) {
+		l.redactors = append(l.redactors, f)
+	}
+}
+
+var (
+	bearerRe = regexp.MustCompile(`(?i)\b(bearer)\s+[A-Za-z0-9\-._~+/]+=*`)
+	cardRe   = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	emailRe  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+// RedactBearerTokens replaces bearer tokens, as in an Authorization header,
+// keeping the scheme: "Bearer abc.def" becomes "Bearer [REDACTED]".
+func RedactBearerTokens(s string) string {
+	return bearerRe.ReplaceAllString(s, "${1} "+Redacted)
+}
+
+// RedactCardNumbers replaces payment card numbers of 13 to 19 digits,
+// optionally grouped by spaces or dashes, that pass the Luhn check.
+func RedactCardNumbers(s string) string {
+	return cardRe.ReplaceAllStringFunc(s, func(m string) string {
+		if luhn(m) {
+			return Redacted
+		}
+		return m
+	})
+}
+
+// RedactEmails replaces email addresses.
+func RedactEmails(s string) string {
+	return emailRe.ReplaceAllString(s, Redacted)
+}
+
+// luhn reports whether the digits in s pass the Luhn checksum.
+func luhn(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+		d := int(s[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// redact applies the logger's redactors to s.
+func (l *Logger) redact(s string) string {
+	for _, f := range l.redactors {
+		s = f(s)
+	}
+	return s
+}
+
+// redactFields returns a copy of kv with its values redacted. Keys are paired
+// with values as in appendFields and kept.
+func (l *Logger) redactFields(kv []any) []any {
+	if len(kv) == 0 {
+		return kv
+	}
+	out := make([]any, len(kv))
+	copy(out, kv)
+	for i := 0; i < len(out); i++ {
+		if _, ok := out[i].(string); ok && i+1 < len(out) {
+			i++ // skip the key
+		}
+		out[i] = l.redactValue(out[i])
+	}
+	return out
+}
+
+// redactValue redacts v as text, keeping v itself if nothing was redacted.
+func (l *Logger) redactValue(v any) any {
+	switch v := v.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr, float32, float64:
+		return v
+	case string:
+		return l.redact(v)
+	}
+	s := fmt.Sprint(v)
+	if r := l.redact(s); r != s {
+		return r
+	}
+	return v
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestRedactors verifies the built-in redactors, including the Luhn check on
+// card numbers.
+func TestRedactors(t *testing.T) {
+	for _, tc := range []struct {
+		f        func(string) string
+		in, want string
+	}{
+		{RedactBearerTokens, "Authorization: Bearer abc.DEF-123==", "Authorization: Bearer [REDACTED]"},
+		{RedactBearerTokens, "bearer\ttok/en+1", "bearer [REDACTED]"},
+		{RedactBearerTokens, "no bearers here", "no bearers here"},
+		{RedactCardNumbers, "card 4111 1111 1111 1111 ok", "card [REDACTED] ok"},
+		{RedactCardNumbers, "card 4111-1111-1111-1111", "card [REDACTED]"},
+		{RedactCardNumbers, "card 4111111111111112", "card 4111111111111112"},
+		{RedactCardNumbers, "order 123456789012", "order 123456789012"},
+		{RedactEmails, "mail ann.smith+x@mail.example.com now", "mail [REDACTED] now"},
+		{RedactEmails, "ann@localhost", "ann@localhost"},
+	} {
+		if got := tc.f(tc.in); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+// TestRedactFields verifies that messages, logger fields, and field values are
+// redacted, and keys and other values are kept.
+func TestRedactFields(t *testing.T) {
+	l, dirPath := newTestLogger(t, "info", WithRedactor(RedactEmails), WithRedactor(RedactCardNumbers))
+	l.With("owner", "bob@example.org").Infow("sent to ann@example.com",
+		"ann@example.com", 1, "err", errors.New("bounced by c@example.net"), "card", "4111111111111111", "ok", true)
+	checkLines(t, readLog(t, l, dirPath),
+		`INFO sent to [REDACTED] owner=[REDACTED] ann@example.com=1 err="bounced by [REDACTED]" card=[REDACTED] ok=true`)
+}
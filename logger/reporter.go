@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Reporter forwards error entries to an error tracking backend, such as
+// Sentry. See ReporterHook.
+type Reporter interface {
+	// Report sends a batch of entries, in the order they were logged.
+	Report(entries []Entry) error
+}
+
+// ReporterHook is a Hook that forwards entries at error level and above,
+// including panic, fatal, and custom levels ordered after error, to a
+// Reporter, so crashes are visible without a second logging stack. Entries are
+// batched and reported in the background once a batch is full or the oldest
+// entry has waited for the batch interval. Flush and Close on the logger
+// report pending entries, as Fatal and Panic do before exiting.
+//
+//	l.AddHook(logger.NewReporterHook(reporter, 20, 5*time.Second))
+type ReporterHook struct {
+	r        Reporter
+	size     int
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending []Entry
+	timer   *time.Timer
+	sending sync.WaitGroup
+}
+
+// NewReporterHook returns a hook reporting to r in batches of up to size
+// entries, at least every interval. size < 1 reports each entry on its own.
+func NewReporterHook(r Reporter, size int, interval time.Duration) *ReporterHook {
+	return &ReporterHook{r: r, size: max(size, 1), interval: interval}
+}
+
+// Before implements Hook.
+func (h *ReporterHook) Before(*Entry) bool { return true }
+
+// After implements Hook, queuing written entries at error level and above.
+func (h *ReporterHook) After(e *Entry, err error) {
+	if e.order < LevelError {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pending = append(h.pending, *e)
+	switch {
+	case len(h.pending) >= h.size:
+		h.sendLocked()
+	case h.timer == nil:
+		h.timer = time.AfterFunc(h.interval, func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.sendLocked()
+		})
+	}
+}
+
+// sendLocked reports the pending entries in the background. h.mu must be held.
+func (h *ReporterHook) sendLocked() {
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+	if len(h.pending) == 0 {
+		return
+	}
+	batch := h.pending
+	h.pending = nil
+	h.sending.Add(1)
+	go func() {
+		defer h.sending.Done()
+		if err := h.r.Report(batch); err != nil {
+			log.Printf("logger: failed to report %d entries: %v", len(batch), err)
+		}
+	}()
+}
+
+// Flush reports the pending entries and waits for all reports to finish.
+func (h *ReporterHook) Flush() error {
+	h.mu.Lock()
+	h.sendLocked()
+	h.mu.Unlock()
+	h.sending.Wait()
+	return nil
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+// chanReporter sends the messages of each batch to a channel.
+type chanReporter chan []string
+
+func (r chanReporter) Report(entries []Entry) error {
+	msgs := make([]string, len(entries))
+	for i, e := range entries {
+		msgs[i] = e.Msg
+	}
+	r <- msgs
+	return nil
+}
+
+// TestReporterHook verifies that entries at error level and above are
+// reported in batches once full, once the interval passes, and on Flush.
+func TestReporterHook(t *testing.T) {
+	r := make(chanReporter, 4)
+	l, _ := newTestLogger(t, "debug", WithLevel("alert", LevelError+5, ""))
+	l.AddHook(NewReporterHook(r, 2, 50*time.Millisecond))
+	receive := func(want ...string) {
+		t.Helper()
+		select {
+		case got := <-r:
+			checkLines(t, got, want...)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for batch %q", want)
+		}
+	}
+
+	l.Warn("ignored")
+	l.Error("a")
+	l.Log("alert", "b")
+	receive("a", "b")
+
+	l.Errorf("c")
+	receive("c")
+
+	l.Error("d")
+	if err := l.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	select {
+	case got := <-r:
+		checkLines(t, got, "d")
+	default:
+		t.Fatalf("expected Flush to report pending entries")
+	}
+}
+
+// TestReporterFatal verifies that Fatal reports pending entries, including its
+// own, before exiting.
+func TestReporterFatal(t *testing.T) {
+	r := make(chanReporter, 1)
+	l, _ := newTestLogger(t, "info", WithExitFunc(func(int) {}))
+	l.AddHook(NewReporterHook(r, 10, time.Hour))
+	l.Error("first")
+	l.Fatal("cannot start")
+	select {
+	case got := <-r:
+		checkLines(t, got, "first", "cannot start")
+	default:
+		t.Errorf("expected the entries to be reported before exiting")
+	}
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sampling is a level's sampling rate, registered by WithSampling.
+type sampling struct {
+	level string
+	n     int
+}
+
+// WithSampling writes only the first of every n entries at level, e.g. to
+// keep debug enabled in production at 1% of its volume with n = 100. The
+// number of entries dropped is reported by Sampled. Entries are counted once
+// they pass the level check, across all loggers derived from the one New
+// returns. New fails with ErrInvalidLogLevel for unknown levels and
+// ErrInvalidSampling for n < 1.
+func WithSampling(level string, n int) Option {
+	return func(l *Logger) {
+		l.sampling = append(l.sampling, sampling{level, n})
+	}
+}
+
+// applySampling sets the sampling rates registered by WithSampling on the
+// level table.
+func (l *Logger) applySampling() error {
+	for _, s := range l.sampling {
+		def := l.levels[strings.ToLower(s.level)]
+		if def == nil {
+			return fmt.Errorf("cannot sample level '%s'. %w", s.level, ErrInvalidLogLevel)
+		}
+		if s.n < 1 {
+			return fmt.Errorf("cannot sample '%s' at 1 in %d. %w", s.level, s.n, ErrInvalidSampling)
+		}
+		def.sampleN = uint64(s.n)
+	}
+	return nil
+}
+
+// sample reports whether an entry at level should be written, counting those
+// dropped.
+func (l *Logger) sample(level int) bool {
+	def := l.byOrder[level]
+	if def.sampleN <= 1 {
+		return true
+	}
+	if (def.seen.Add(1)-1)%def.sampleN == 0 {
+		return true
+	}
+	def.dropped.Add(1)
+	return false
+}
+
+// Sampled returns the number of entries at the named level dropped by
+// sampling, or 0 for levels that aren't sampled.
+func (l *Logger) Sampled(level string) uint64 {
+	if def := l.levels[strings.ToLower(level)]; def != nil {
+		return def.dropped.Load()
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestSampling verifies that a sampled level keeps the first of every n
+// entries and counts the rest, leaving other levels alone.
+func TestSampling(t *testing.T) {
+	l, dirPath := newTestLogger(t, "debug", WithSampling("DEBUG", 3))
+	for i := 0; i < 7; i++ {
+		l.Debugf("tick %d", i)
+		l.Named("sub").Debugf("sub %d", i)
+	}
+	l.Info("kept")
+	lines := readLog(t, l, dirPath)
+	if len(lines) != 6 || lines[0] != "DEBUG tick 0" || lines[5] != "INFO kept" {
+		t.Errorf("expected one in three debug entries, got %q", lines)
+	}
+	if got := l.Sampled("debug"); got != 9 {
+		t.Errorf("Sampled(debug): got %d, want 9", got)
+	}
+	if got := l.Sampled("info"); got != 0 {
+		t.Errorf("Sampled(info): got %d, want 0", got)
+	}
+	if _, err := New(t.TempDir(), "info", WithSampling("nope", 2)); !errors.Is(err, ErrInvalidLogLevel) {
+		t.Errorf("expected ErrInvalidLogLevel, got %v", err)
+	}
+	if _, err := New(t.TempDir(), "info", WithSampling("debug", 0)); !errors.Is(err, ErrInvalidSampling) {
+		t.Errorf("expected ErrInvalidSampling, got %v", err)
+	}
+}
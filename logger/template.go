@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultTimeLayout is the layout of {time} in templates when neither the
+// placeholder nor TimeLayout sets one, the date and time as the text format
+// writes them.
+const DefaultTimeLayout = "2006/01/02 15:04:05"
+
+// TemplateFormatter renders entries following a template, so existing log
+// layouts can be matched exactly when migrating from other frameworks. It's
+// created by NewTemplateFormatter.
+type TemplateFormatter struct {
+	// TimeLayout is the layout of {time} placeholders without one of their
+	// own, DefaultTimeLayout if empty, as set by WithTimeFormat.
+	TimeLayout string
+
+	parts []templatePart
+}
+
+// templatePart is a literal, or a placeholder if verb is set.
+type templatePart struct {
+	text string // literal text, or the layout of a time placeholder if any
+	verb string
+}
+
+// NewTemplateFormatter parses a template into a TemplateFormatter. Placeholders
+// in braces are replaced with parts of the entry:
+//
+//	{time}         the entry's time, as TimeLayout
+//	{time:layout}  the entry's time, formatted with a layout as in WithTimeFormat
+//	{level}        the level's name, e.g. "info"
+//	{tag}          the level's tag, e.g. "INFO"
+//	{pid}          the process ID
+//	{caller}       the caller's file:line, with the full path
+//	{shortcaller}  the caller's file:line, with the file name only
+//	{msg}          the message
+//	{fields}       the fields, in logfmt style
+//
+// "{{" and "}}" stand for literal braces. A placeholder that renders empty,
+// such as {fields} without fields, takes a space before it along when followed
+// by a space or the end of the template. Unknown or unterminated placeholders
+// return an error wrapping ErrInvalidTemplate.
+//
+//	f, err := logger.NewTemplateFormatter("{time:2006-01-02T15:04:05.000Z07:00} [{tag}] {shortcaller} {msg} {fields}")
+func NewTemplateFormatter(tmpl string) (*TemplateFormatter, error) {
+	var parts []templatePart
+	var lit strings.Builder
+	for i := 0; i < len(tmpl); i++ {
+		c := tmpl[i]
+		if (c == '{' || c == '}') && i+1 < len(tmpl) && tmpl[i+1] == c {
+			lit.WriteByte(c)
+			i++
+			continue
+		}
+		if c == '}' {
+			return nil, fmt.Errorf("unmatched '}' at offset %d in template %q. %w", i, tmpl, ErrInvalidTemplate)
+		}
+		if c != '{' {
+			lit.WriteByte(c)
+			continue
+		}
+		end := strings.IndexByte(tmpl[i:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated placeholder at offset %d in template %q. %w", i, tmpl, ErrInvalidTemplate)
+		}
+		verb, layout, hasLayout := strings.Cut(tmpl[i+1:i+end], ":")
+		switch {
+		case verb == "time" && !hasLayout:
+		case verb == "time" && layout == "":
+			return nil, fmt.Errorf("empty time layout in template %q. %w", tmpl, ErrInvalidTemplate)
+		case verb == "time":
+		case hasLayout:
+			return nil, fmt.Errorf("placeholder {%s} in template %q takes no layout. %w", tmpl[i+1:i+end], tmpl, ErrInvalidTemplate)
+		case verb == "level", verb == "tag", verb == "pid", verb == "caller", verb == "shortcaller", verb == "msg", verb == "fields":
+		default:
+			return nil, fmt.Errorf("unknown placeholder {%s} in template %q. %w", verb, tmpl, ErrInvalidTemplate)
+		}
+		if lit.Len() > 0 {
+			parts = append(parts, templatePart{text: lit.String()})
+			lit.Reset()
+		}
+		parts = append(parts, templatePart{text: layout, verb: verb})
+		i += end
+	}
+	if lit.Len() > 0 {
+		parts = append(parts, templatePart{text: lit.String()})
+	}
+	return &TemplateFormatter{parts: parts}, nil
+}
+
+// WithTemplate renders entries with a TemplateFormatter parsed from tmpl, as
+// NewTemplateFormatter does; New returns the error if it's invalid. It replaces
+// the formatter set by WithFormat or WithFormatter.
+//
+//	l, err := logger.New("./app_logs", "info", logger.WithTemplate("{time} {level} {caller} {msg} {fields}"))
+func WithTemplate(tmpl string) Option {
+	return func(l *Logger) {
+		f, err := NewTemplateFormatter(tmpl)
+		if err != nil {
+			l.templateErr = err
+			return
+		}
+		l.setFormatter(f)
+	}
+}
+
+// Format implements Formatter.
+func (f *TemplateFormatter) Format(e *Entry) []byte {
+	var b []byte
+	for i, p := range f.parts {
+		n := len(b)
+		switch p.verb {
+		case "":
+			b = append(b, p.text...)
+			continue
+		case "time":
+			layout := p.text
+			if layout == "" {
+				layout = f.TimeLayout
+			}
+			if layout == "" {
+				layout = DefaultTimeLayout
+			}
+			b = appendTime(b, e.Time, layout)
+		case "level":
+			b = append(b, e.Level...)
+		case "tag":
+			b = append(b, e.Tag...)
+		case "pid":
+			b = strconv.AppendInt(b, int64(e.PID), 10)
+		case "caller":
+			b = append(b, e.Caller...)
+		case "shortcaller":
+			b = append(b, e.Caller[strings.LastIndexByte(e.Caller, '/')+1:]...)
+		case "msg":
+			b = append(b, e.Msg...)
+		case "fields":
+			if len(e.Fields) > 0 {
+				b = appendFields(b, e.Fields)
+				b = append(b[:n], b[n+1:]...) // appendFields leads with a space
+			}
+		}
+		if len(b) == n && n > 0 && b[n-1] == ' ' && (i+1 == len(f.parts) || f.parts[i+1].verb == "" && strings.HasPrefix(f.parts[i+1].text, " ")) {
+			b = b[:n-1]
+		}
+	}
+	return b
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestTemplateFormatter verifies the placeholders, escaped braces, and the
+// elision of spaces before empty placeholders.
+func TestTemplateFormatter(t *testing.T) {
+	e := &Entry{
+		Time:   time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		Level:  "warn",
+		Tag:    "WARN",
+		PID:    42,
+		Msg:    "disk low",
+		Caller: "/src/app/main.go:7",
+	}
+	withFields := *e
+	withFields.Fields = []any{"free", "1 GB", "pct", 3}
+	for _, tc := range []struct {
+		tmpl   string
+		layout string
+		e      *Entry
+		want   string
+	}{
+		{"{time} [{tag}] {msg} {fields}", "", e, "2024/01/02 15:04:05 [WARN] disk low"},
+		{"{time} [{tag}] {msg} {fields}", "", &withFields, `2024/01/02 15:04:05 [WARN] disk low free="1 GB" pct=3`},
+		{"{msg} {fields} end", "", e, "disk low end"},
+		{"{msg} {fields}|", "", e, "disk low |"},
+		{"{{{level}}} {pid} {caller} {shortcaller}", "", e, "{warn} 42 /src/app/main.go:7 main.go:7"},
+		{"{time:2006-01-02} {time}", time.Kitchen, e, "2024-01-02 3:04PM"},
+		{"{time:unixmilli}", "", e, "1704207845000"},
+		{"}}{msg}{{", "", e, "}disk low{"},
+	} {
+		f, err := NewTemplateFormatter(tc.tmpl)
+		if err != nil {
+			t.Fatalf("NewTemplateFormatter(%q) failed: %v", tc.tmpl, err)
+		}
+		f.TimeLayout = tc.layout
+		if got := string(f.Format(tc.e)); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.tmpl, got, tc.want)
+		}
+	}
+}
+
+// TestInvalidTemplate verifies that malformed templates are rejected, by
+// NewTemplateFormatter and by New through WithTemplate.
+func TestInvalidTemplate(t *testing.T) {
+	for _, tmpl := range []string{"{nope}", "{msg", "msg}", "{time:}", "{msg:x}"} {
+		if _, err := NewTemplateFormatter(tmpl); !errors.Is(err, ErrInvalidTemplate) {
+			t.Errorf("%q: expected ErrInvalidTemplate, got %v", tmpl, err)
+		}
+	}
+	if _, err := New(t.TempDir(), "info", WithTemplate("{level} {bad}")); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("expected New to fail with ErrInvalidTemplate, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"strconv"
+	"time"
+)
+
+// Time layouts for WithTimeFormat, besides those of the time package such as
+// time.RFC3339Nano.
+const (
+	// ISO8601Micro is ISO 8601 with microseconds, e.g.
+	// "2024-01-02T15:04:05.123456Z" in UTC.
+	ISO8601Micro = "2006-01-02T15:04:05.000000Z07:00"
+	// UnixMilli writes the time as milliseconds since the Unix epoch. JSON
+	// output encodes it as a number.
+	UnixMilli = "unixmilli"
+)
+
+// WithTimeFormat sets the layout of timestamps, a time package layout or one of
+// ISO8601Micro and UnixMilli, for every level. In the text format it replaces
+// the date and time wherever SetFlags selects them; in JSON it replaces
+// time.RFC3339Nano; in templates it applies to {time} placeholders without a
+// layout. Formatters set with WithFormatter are unaffected.
+//
+//	logger.New("./app_logs", "info", logger.WithTimeFormat(logger.ISO8601Micro), logger.WithTimeLocation(time.UTC))
+func WithTimeFormat(layout string) Option {
+	return func(l *Logger) {
+		l.timeLayout = layout
+	}
+}
+
+// WithTimeLocation sets the time zone of entries, e.g. time.UTC, for every
+// formatter and hook. Entries are in local time by default.
+func WithTimeLocation(loc *time.Location) Option {
+	return func(l *Logger) {
+		l.timeLoc = loc
+	}
+}
+
+// applyTimeFormat sets the layout given to WithTimeFormat on the logger's
+// formatter, if it's a built-in one.
+func (l *Logger) applyTimeFormat() {
+	if l.timeLayout == "" {
+		return
+	}
+	switch f := (*l.formatter.Load()).(type) {
+	case *TextFormatter:
+		c := *f
+		c.TimeLayout = l.timeLayout
+		l.setFormatter(&c)
+	case JSONFormatter:
+		f.TimeLayout = l.timeLayout
+		l.setFormatter(f)
+	case *TemplateFormatter:
+		c := *f
+		c.TimeLayout = l.timeLayout
+		l.setFormatter(&c)
+	}
+}
+
+// appendTime appends t to b, formatted with layout.
+func appendTime(b []byte, t time.Time, layout string) []byte {
+	if layout == UnixMilli {
+		return strconv.AppendInt(b, t.UnixMilli(), 10)
+	}
+	return t.AppendFormat(b, layout)
+}
+
+// now returns the current time in the location given to WithTimeLocation.
+func (l *Logger) now() time.Time {
+	if l.timeLoc != nil {
+		return time.Now().In(l.timeLoc)
+	}
+	return time.Now()
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// TestTimeFormat verifies that WithTimeFormat and WithTimeLocation apply to
+// the text, JSON, and template formatters.
+func TestTimeFormat(t *testing.T) {
+	l, dirPath := newTestLogger(t, "info", WithFormat(Text), WithTimeFormat(ISO8601Micro), WithTimeLocation(time.UTC))
+	l.Info("hello")
+	lines := readLog(t, l, dirPath)
+	if len(lines) != 1 || !regexp.MustCompile(`^\[PID:\d+\]INFO: \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}Z hello$`).MatchString(lines[0]) {
+		t.Errorf("expected an ISO 8601 UTC time, got %q", lines)
+	}
+
+	l, dirPath = newTestLogger(t, "info", WithFormat(JSON), WithTimeFormat(UnixMilli))
+	before := time.Now().UnixMilli()
+	l.Info("hello")
+	lines = readLog(t, l, dirPath)
+	var got struct{ Time int64 }
+	if len(lines) != 1 || json.Unmarshal([]byte(lines[0]), &got) != nil {
+		t.Fatalf("expected a JSON line with a numeric time, got %q", lines)
+	}
+	if got.Time < before || got.Time > time.Now().UnixMilli() {
+		t.Errorf("time %d out of range", got.Time)
+	}
+
+	loc := time.FixedZone("X", -3*3600)
+	l, dirPath = newTestLogger(t, "info", WithTemplate("{time} {time:Z07:00} {msg}"), WithTimeFormat("15:04"), WithTimeLocation(loc))
+	l.Info("hello")
+	lines = readLog(t, l, dirPath)
+	if len(lines) != 1 || !regexp.MustCompile(`^\d\d:\d\d -03:00 hello$`).MatchString(lines[0]) {
+		t.Errorf("expected the template to use the layout and location, got %q", lines)
+	}
+}
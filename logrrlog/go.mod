@@ -0,0 +1,9 @@
+module github.com/Data-Corruption/rlog/logrrlog
+
+go 1.22.4
+
+require github.com/Data-Corruption/rlog v0.0.0
+
+require github.com/go-logr/logr v1.4.2
+
+replace github.com/Data-Corruption/rlog => ../
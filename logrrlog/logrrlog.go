@@ -0,0 +1,91 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+// Package logrrlog implements a logr.LogSink over a *logger.Logger, so that
+// controller-runtime, client-go, and other Kubernetes components can log
+// through rlog without custom glue. It lives in its own module to keep rlog
+// itself free of dependencies.
+//
+// logr's V-levels map onto the logger's levels: V(0) logs at info and every
+// higher verbosity at debug. Error logs at error, with the error under the
+// "error" key. Names added with WithName are joined with "/" and recorded
+// under the "logger" key.
+//
+// Usage:
+//
+//	l, err := logger.New("logs", "info")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer l.Close()
+//	ctrl.SetLogger(logrrlog.New(l))
+package logrrlog
+
+import (
+	"github.com/Data-Corruption/rlog/logger"
+	"github.com/go-logr/logr"
+)
+
+// New returns a logr.Logger that writes through l.
+func New(l *logger.Logger) logr.Logger {
+	return logr.New(NewSink(l))
+}
+
+// NewSink returns a logr.LogSink that writes through l.
+func NewSink(l *logger.Logger) logr.LogSink {
+	return &sink{base: l, l: l}
+}
+
+// sink logs through l, which is base plus the "logger" field for name, so a
+// nested name replaces its parent's rather than repeating the field.
+type sink struct {
+	base *logger.Logger
+	l    *logger.Logger
+	name string
+}
+
+var (
+	_ logr.LogSink          = (*sink)(nil)
+	_ logr.CallDepthLogSink = (*sink)(nil)
+)
+
+// Init skips the frames logr adds, plus the sink's own, when reporting callers.
+func (s *sink) Init(info logr.RuntimeInfo) {
+	s.base = s.base.WithCallerSkip(info.CallDepth + 1)
+	s.l = s.l.WithCallerSkip(info.CallDepth + 1)
+}
+
+func (s *sink) Enabled(level int) bool {
+	if level > 0 {
+		return s.l.Enabled("debug")
+	}
+	return s.l.Enabled("info")
+}
+
+func (s *sink) Info(level int, msg string, kv ...any) {
+	if level > 0 {
+		s.l.Debugw(msg, kv...)
+	} else {
+		s.l.Infow(msg, kv...)
+	}
+}
+
+func (s *sink) Error(err error, msg string, kv ...any) {
+	s.l.Errorw(msg, append(kv, "error", err)...)
+}
+
+func (s *sink) WithValues(kv ...any) logr.LogSink {
+	return &sink{base: s.base.With(kv...), l: s.l.With(kv...), name: s.name}
+}
+
+func (s *sink) WithName(name string) logr.LogSink {
+	if s.name != "" {
+		name = s.name + "/" + name
+	}
+	return &sink{base: s.base, l: s.base.With("logger", name), name: name}
+}
+
+func (s *sink) WithCallDepth(depth int) logr.LogSink {
+	return &sink{base: s.base.WithCallerSkip(depth), l: s.l.WithCallerSkip(depth), name: s.name}
+}
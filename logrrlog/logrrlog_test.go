@@ -0,0 +1,58 @@
+package logrrlog
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Data-Corruption/rlog/logger"
+)
+
+// TestSink verifies that logr calls are written through the logger with
+// their levels, names, values, errors, and callers.
+func TestSink(t *testing.T) {
+	dir := t.TempDir()
+	l, err := logger.New(dir, "info", logger.WithFormat(logger.JSON))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	log := New(l).WithName("ctrl").WithValues("ns", "default").WithName("pod")
+	log.Info("reconciled", "pod", "web-1")
+	log.V(1).Info("hidden")
+	log.Error(errors.New("boom"), "failed")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %q", lines)
+	}
+	var entries []map[string]any
+	for _, line := range lines {
+		var e map[string]any
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("failed to decode %q: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if e := entries[0]; e["level"] != "info" || e["logger"] != "ctrl/pod" || e["ns"] != "default" || e["pod"] != "web-1" {
+		t.Errorf("unexpected info entry %q", lines[0])
+	}
+	if e := entries[1]; e["level"] != "error" || e["error"] != "boom" {
+		t.Errorf("unexpected error entry %q", lines[1])
+	}
+	if caller, _ := entries[0]["caller"].(string); !strings.Contains(caller, "logrrlog_test.go") {
+		t.Errorf("expected the caller to be the test, got %q", caller)
+	}
+	if strings.Count(lines[0], `"logger"`) != 1 {
+		t.Errorf("expected a single logger name, got %q", lines[0])
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/Data-Corruption/rlog/logrusrlog
+
+go 1.22.4
+
+require (
+	github.com/Data-Corruption/rlog v0.0.0
+	github.com/sirupsen/logrus v1.9.3
+)
+
+require golang.org/x/sys v0.0.0-20220715151400-c0bba94af5f8 // indirect
+
+replace github.com/Data-Corruption/rlog => ../
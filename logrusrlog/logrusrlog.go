@@ -0,0 +1,85 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+// Package logrusrlog routes logrus entries into an *rlog.Writer, for codebases
+// migrating from logrus incrementally. It lives in its own module to keep rlog
+// itself free of dependencies.
+//
+// Use makes the Writer a logger's output. NewHook instead adds the Writer as
+// an extra destination, leaving the logger's output untouched. Either way,
+// buffered lines are flushed before a Fatal entry exits the process.
+//
+// Usage:
+//
+//	w, err := rlog.New("logs")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer w.Close()
+//	logrusrlog.Use(logrus.StandardLogger(), w)
+package logrusrlog
+
+import (
+	"os"
+
+	"github.com/Data-Corruption/rlog"
+	"github.com/sirupsen/logrus"
+)
+
+// Use sets w as logger's output and arranges for w to be flushed before
+// logger exits on a Fatal entry. Panic entries are flushed when a deferred
+// w.Close runs as the panic unwinds.
+//
+// w must not be created with rlog.WithNoSync, as logrus may write from
+// several goroutines.
+func Use(logger *logrus.Logger, w *rlog.Writer) {
+	logger.SetOutput(w)
+	exit := logger.ExitFunc
+	if exit == nil {
+		exit = os.Exit
+	}
+	logger.ExitFunc = func(code int) {
+		w.Flush()
+		exit(code)
+	}
+}
+
+// Hook is a logrus.Hook that writes entries to a Writer in addition to the
+// logger's own output. Entries at FlushLevel or more severe are flushed
+// immediately, which covers Fatal and Panic entries as hooks fire before
+// logrus exits or panics.
+type Hook struct {
+	w          *rlog.Writer
+	formatter  logrus.Formatter
+	FlushLevel logrus.Level // logrus.ErrorLevel by default
+}
+
+// NewHook returns a Hook that writes every entry to w, formatted by f, or by
+// a logrus.TextFormatter if f is nil.
+func NewHook(w *rlog.Writer, f logrus.Formatter) *Hook {
+	if f == nil {
+		f = &logrus.TextFormatter{DisableColors: true}
+	}
+	return &Hook{w: w, formatter: f, FlushLevel: logrus.ErrorLevel}
+}
+
+// Levels implements logrus.Hook. The logger's level still applies.
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.
+func (h *Hook) Fire(e *logrus.Entry) error {
+	line, err := h.formatter.Format(e)
+	if err != nil {
+		return err
+	}
+	if _, err := h.w.Write(line); err != nil {
+		return err
+	}
+	if e.Level <= h.FlushLevel {
+		return h.w.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package logrusrlog
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Data-Corruption/rlog"
+	"github.com/sirupsen/logrus"
+)
+
+// TestUse verifies that entries go to the Writer and are flushed before a
+// Fatal entry exits.
+func TestUse(t *testing.T) {
+	w, m, err := rlog.NewMemory()
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	logger := logrus.New()
+	exited := -1
+	logger.ExitFunc = func(code int) { exited = code }
+	Use(logger, w)
+
+	logger.Info("started")
+	if got := m.Files()["latest.log"]; len(got) != 0 {
+		t.Fatalf("expected info entries to stay buffered, got %q", got)
+	}
+	logger.Fatal("giving up")
+	got := string(m.Files()["latest.log"])
+	if exited != 1 || !strings.Contains(got, "started") || !strings.Contains(got, "giving up") {
+		t.Errorf("expected both entries flushed before exit 1, got exit %d and %q", exited, got)
+	}
+}
+
+// TestHook verifies that the hook writes entries alongside the logger's output
+// and flushes severe ones immediately.
+func TestHook(t *testing.T) {
+	w, m, err := rlog.NewMemory()
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(NewHook(w, &logrus.JSONFormatter{}))
+
+	logger.WithField("user", "ann").Info("logged in")
+	if got := m.Files()["latest.log"]; len(got) != 0 {
+		t.Fatalf("expected info entries to stay buffered, got %q", got)
+	}
+	logger.Error("failed")
+	got := string(m.Files()["latest.log"])
+	if strings.Count(got, "\n") != 2 || !strings.Contains(got, `"user":"ann"`) {
+		t.Errorf("expected both entries flushed by the error, got %q", got)
+	}
+}
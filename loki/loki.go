@@ -0,0 +1,290 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+// Package loki implements an rlog.Sink that pushes log lines to a Grafana Loki
+// push endpoint in batches, alongside the Writer's local files.
+//
+// Lines are timestamped when they're flushed by the Writer, queued in memory,
+// and pushed by a background goroutine once a batch is large or old enough.
+// Failed pushes are retried with backoff when the server reports a transient
+// error (5xx or 429); batches rejected outright are dropped and counted.
+//
+// Usage:
+//
+//	s, err := loki.New(loki.Config{
+//		URL:    "http://loki:3100/loki/api/v1/push",
+//		Labels: map[string]string{"app": "api", "env": "prod"},
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	w, err := rlog.New("logs", rlog.WithSink(s))
+package loki
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultBatchSize    = 1024 * 1024 // 1 MB
+	DefaultBatchWait    = time.Second
+	DefaultMaxQueue     = 8 * 1024 * 1024 // 8 MB
+	DefaultAttempts     = 5
+	DefaultBackoff      = 500 * time.Millisecond
+	DefaultCloseTimeout = 5 * time.Second
+)
+
+// ErrClosed is returned by Write after Close.
+var ErrClosed = errors.New("loki: closed")
+
+// Config describes the Loki endpoint and batching behavior. Zero values select
+// the defaults.
+type Config struct {
+	URL          string            // push endpoint, e.g. "http://loki:3100/loki/api/v1/push"
+	Labels       map[string]string // stream labels attached to every line
+	TenantID     string            // sent as X-Scope-OrgID for multi-tenant setups
+	Headers      map[string]string // extra request headers, e.g. Authorization
+	BatchSize    int               // bytes of line content that trigger a push
+	BatchWait    time.Duration     // maximum time a line waits before being pushed
+	MaxQueue     int               // bytes held in memory before the oldest lines are dropped
+	Attempts     int               // push attempts per batch for transient errors
+	Backoff      time.Duration     // delay before the first retry, doubling after each
+	CloseTimeout time.Duration     // bound on the final push during Close
+	Client       *http.Client      // defaults to http.DefaultClient
+}
+
+// entry is a single timestamped line.
+type entry struct {
+	ts   int64
+	line string
+}
+
+// Sink pushes lines to Loki. It implements rlog.Sink.
+type Sink struct {
+	cfg Config
+
+	mu      sync.Mutex
+	partial []byte // incomplete trailing line awaiting its newline
+	queue   []entry
+	queued  int
+	closed  bool
+	dropped atomic.Uint64
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New returns a Sink for cfg.
+func New(cfg Config) (*Sink, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("loki: URL is required")
+	}
+	if len(cfg.Labels) == 0 {
+		return nil, fmt.Errorf("loki: at least one label is required")
+	}
+	setDefault(&cfg.BatchSize, DefaultBatchSize)
+	setDefault(&cfg.BatchWait, DefaultBatchWait)
+	setDefault(&cfg.MaxQueue, DefaultMaxQueue)
+	setDefault(&cfg.Attempts, DefaultAttempts)
+	setDefault(&cfg.Backoff, DefaultBackoff)
+	setDefault(&cfg.CloseTimeout, DefaultCloseTimeout)
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	s := &Sink{
+		cfg:  cfg,
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+func setDefault[T int | time.Duration](v *T, def T) {
+	if *v <= 0 {
+		*v = def
+	}
+}
+
+// Write splits p into lines and queues them for pushing. A trailing partial
+// line is held until its newline arrives or the Sink is closed.
+func (s *Sink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, ErrClosed
+	}
+	ts := time.Now().UnixNano()
+	rest := p
+	for {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			s.partial = append(s.partial, rest...)
+			break
+		}
+		line := string(append(s.partial, rest[:i]...))
+		s.partial = s.partial[:0]
+		rest = rest[i+1:]
+		s.queue = append(s.queue, entry{ts, line})
+		s.queued += len(line)
+	}
+	for s.queued > s.cfg.MaxQueue && len(s.queue) > 0 {
+		s.dropped.Add(1)
+		s.queued -= len(s.queue[0].line)
+		s.queue = s.queue[1:]
+	}
+	if s.queued >= s.cfg.BatchSize {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Dropped returns the number of lines discarded because the queue overflowed
+// or the server rejected them.
+func (s *Sink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close pushes any queued lines, bounded by the close timeout, and stops the
+// background goroutine.
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+	s.closed = true
+	if len(s.partial) > 0 {
+		s.queue = append(s.queue, entry{time.Now().UnixNano(), string(s.partial)})
+		s.queued += len(s.partial)
+		s.partial = nil
+	}
+	s.mu.Unlock()
+	close(s.stop)
+	<-s.done
+	return nil
+}
+
+// take removes up to BatchSize bytes of lines from the queue.
+func (s *Sink) take() []entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, size := 0, 0
+	for n < len(s.queue) && (n == 0 || size+len(s.queue[n].line) <= s.cfg.BatchSize) {
+		size += len(s.queue[n].line)
+		n++
+	}
+	batch := s.queue[:n:n]
+	s.queue = s.queue[n:]
+	s.queued -= size
+	return batch
+}
+
+func (s *Sink) run() {
+	defer close(s.done)
+	t := time.NewTicker(s.cfg.BatchWait)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.wake:
+		case <-t.C:
+		case <-s.stop:
+			ctx, cancel := context.WithTimeout(context.Background(), s.cfg.CloseTimeout)
+			s.pushAll(ctx)
+			cancel()
+			return
+		}
+		s.pushAll(context.Background())
+	}
+}
+
+// pushAll pushes queued lines batch by batch until the queue is empty or ctx ends.
+func (s *Sink) pushAll(ctx context.Context) {
+	for ctx.Err() == nil {
+		batch := s.take()
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.push(ctx, batch); err != nil {
+			s.dropped.Add(uint64(len(batch)))
+		}
+	}
+}
+
+// push sends batch, retrying transient failures with backoff.
+func (s *Sink) push(ctx context.Context, batch []entry) error {
+	body, err := encode(s.cfg.Labels, batch)
+	if err != nil {
+		return err
+	}
+	delay := s.cfg.Backoff
+	for i := 0; ; i++ {
+		retry, err := s.send(ctx, body)
+		if err == nil || !retry || i == s.cfg.Attempts-1 {
+			return err
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// send performs one push request, reporting whether a failure is transient.
+func (s *Sink) send(ctx context.Context, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.cfg.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", s.cfg.TenantID)
+	}
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	err = fmt.Errorf("loki: push failed: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests, err
+}
+
+// encode renders batch as a Loki push request body.
+func encode(labels map[string]string, batch []entry) ([]byte, error) {
+	type stream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+	st := stream{Stream: labels, Values: make([][2]string, len(batch))}
+	for i, e := range batch {
+		st.Values[i] = [2]string{strconv.FormatInt(e.ts, 10), e.line}
+	}
+	return json.Marshal(struct {
+		Streams []stream `json:"streams"`
+	}{[]stream{st}})
+}
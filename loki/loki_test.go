@@ -0,0 +1,67 @@
+package loki
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSink verifies that lines are pushed in Loki's format, retrying transient failures.
+func TestSink(t *testing.T) {
+	var mu sync.Mutex
+	var lines []string
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if requests++; requests == 1 {
+			http.Error(w, "warming up", http.StatusServiceUnavailable)
+			return
+		}
+		var body struct {
+			Streams []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"streams"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Streams) != 1 {
+			http.Error(w, "bad body", http.StatusBadRequest)
+			return
+		}
+		if body.Streams[0].Stream["app"] != "test" || r.Header.Get("X-Scope-OrgID") != "tenant" {
+			http.Error(w, "bad labels", http.StatusBadRequest)
+			return
+		}
+		for _, v := range body.Streams[0].Values {
+			lines = append(lines, v[1])
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s, err := New(Config{URL: srv.URL, Labels: map[string]string{"app": "test"}, TenantID: "tenant",
+		BatchWait: time.Hour, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("failed to create Sink: %v", err)
+	}
+	s.Write([]byte("first\nsec"))
+	s.Write([]byte("ond\nunterminated"))
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	want := []string{"first", "second", "unterminated"}
+	if len(lines) != len(want) {
+		t.Fatalf("pushed lines mismatch: got %q, want %q", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d: got %q, want %q", i, lines[i], want[i])
+		}
+	}
+	if s.Dropped() != 0 {
+		t.Errorf("expected no dropped lines, got %d", s.Dropped())
+	}
+}
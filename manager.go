@@ -0,0 +1,170 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrManagerClosed is returned by Writer and Flush when called after Close.
+var ErrManagerClosed = errors.New("manager closed")
+
+// Manager owns a directory and hands out independent Writers for named
+// streams, e.g. "access", "error", and "audit". Each stream writes to its own
+// subdirectory and is configured with the options shared by the Manager, such
+// as rotation and retention limits. A Manager also flushes every stream on a
+// fixed interval, so idle streams don't hold buffered data indefinitely, and
+// with WithMaxOpenFiles it closes the files of idle streams to stay within a
+// file descriptor budget.
+//
+// Manager is safe for concurrent use, as are the Writers it returns.
+type Manager struct {
+	dirPath string
+	opts    []Option
+
+	mu      sync.Mutex
+	streams map[string]*Writer
+	closed  bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewManager creates a Manager for dirPath, which is created if needed. opts
+// apply to every stream; WithMkdirAll is always added and WithNoSync is
+// ignored. If flushInterval is positive, all streams are flushed at that
+// interval.
+func NewManager(dirPath string, flushInterval time.Duration, opts ...Option) (*Manager, error) {
+	// Validate the shared options once up front rather than on first use.
+	probe := newDefaultWriter(dirPath)
+	for _, opt := range opts {
+		opt(probe)
+	}
+	if err := probe.validate(); err != nil {
+		return nil, err
+	}
+	opts = append(append([]Option{}, opts...), withLocks(), WithMkdirAll())
+	if probe.maxOpenFiles > 0 {
+		opts = append(opts, withBudget(newFDBudget(probe.maxOpenFiles)))
+	}
+	m := &Manager{
+		dirPath: dirPath,
+		opts:    opts,
+		streams: make(map[string]*Writer),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if flushInterval > 0 {
+		go m.flushLoop(flushInterval)
+	} else {
+		close(m.done)
+	}
+	return m, nil
+}
+
+// Writer returns the Writer for the named stream, creating it on first use in
+// the subdirectory of the same name. Names must be a single path element.
+func (m *Manager) Writer(name string) (*Writer, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid stream name %q", name)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrManagerClosed
+	}
+	if w, ok := m.streams[name]; ok {
+		return w, nil
+	}
+	w, err := New(filepath.Join(m.dirPath, name), m.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stream %q: %w", name, err)
+	}
+	m.streams[name] = w
+	return w, nil
+}
+
+// Names returns the names of the streams opened so far, sorted.
+func (m *Manager) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.streams))
+	for name := range m.streams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Flush flushes every stream, returning the first error encountered.
+func (m *Manager) Flush() error {
+	streams, closed := m.snapshot()
+	if closed {
+		return ErrManagerClosed
+	}
+	return eachStream(streams, (*Writer).Flush)
+}
+
+// Close stops the flush schedule and closes every stream, returning the first
+// error encountered. Calling Close again is a no-op returning nil.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	close(m.stop)
+	m.mu.Unlock()
+	<-m.done
+
+	streams, _ := m.snapshot()
+	return eachStream(streams, (*Writer).Close)
+}
+
+// snapshot returns a copy of the streams, so they can be flushed or closed
+// without holding m.mu, and whether the Manager is closed.
+func (m *Manager) snapshot() (map[string]*Writer, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	streams := make(map[string]*Writer, len(m.streams))
+	for name, w := range m.streams {
+		streams[name] = w
+	}
+	return streams, m.closed
+}
+
+// eachStream calls fn on every stream in streams and returns the first error.
+func eachStream(streams map[string]*Writer, fn func(*Writer) error) error {
+	var first error
+	for name, w := range streams {
+		if err := fn(w); err != nil && first == nil {
+			first = fmt.Errorf("stream %q: %w", name, err)
+		}
+	}
+	return first
+}
+
+// flushLoop flushes all streams every interval until Close is called.
+func (m *Manager) flushLoop(interval time.Duration) {
+	defer close(m.done)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			streams, _ := m.snapshot()
+			eachStream(streams, (*Writer).Flush) // errors are sticky and resurface on the next Write
+		case <-m.stop:
+			return
+		}
+	}
+}
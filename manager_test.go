@@ -0,0 +1,172 @@
+package rlog
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestManager verifies that streams are independent and flushed on the manager's schedule.
+func TestManager(t *testing.T) {
+	tempDir := t.TempDir()
+	m, err := NewManager(tempDir, 10*time.Millisecond, WithMaxRotations(1))
+	if err != nil {
+		t.Fatalf("failed to create Manager: %v", err)
+	}
+	access, err := m.Writer("access")
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	audit, err := m.Writer("audit")
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	if again, _ := m.Writer("access"); again != access {
+		t.Errorf("expected the same Writer for repeated stream names")
+	}
+	if _, err := m.Writer("../escape"); err == nil {
+		t.Errorf("expected error for invalid stream name")
+	}
+
+	access.Write([]byte("GET /\n"))
+	audit.Write([]byte("login\n"))
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(filepath.Join(tempDir, "access", "latest.log"))
+		if string(data) == "GET /\n" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("stream was not flushed by the manager, got %q", string(data))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tempDir, "audit", "latest.log"))
+	if err != nil || string(data) != "login\n" {
+		t.Errorf("audit stream content mismatch: got %q (err %v)", string(data), err)
+	}
+	if _, err := m.Writer("error"); err != ErrManagerClosed {
+		t.Errorf("expected ErrManagerClosed after Close, got %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Errorf("second Close returned %v, want nil", err)
+	}
+}
+
+// TestManagerMaxOpenFiles verifies that idle streams' files are closed to stay
+// within the limit and reopened transparently on their next flush.
+func TestManagerMaxOpenFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	m, err := NewManager(tempDir, 0, WithMaxOpenFiles(2), WithFlushOnNewline())
+	if err != nil {
+		t.Fatalf("failed to create Manager: %v", err)
+	}
+	defer m.Close()
+	names := []string{"a", "b", "c", "d", "e"}
+	open := func() int {
+		n := 0
+		for _, name := range names {
+			w, _ := m.Writer(name)
+			w.ioMu.Lock()
+			if w.file != nil {
+				n++
+			}
+			w.ioMu.Unlock()
+		}
+		return n
+	}
+	for round := 0; round < 2; round++ {
+		for _, name := range names {
+			w, err := m.Writer(name)
+			if err != nil {
+				t.Fatalf("failed to open stream: %v", err)
+			}
+			if _, err := fmt.Fprintf(w, "%s %d\n", name, round); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+		}
+		if got := open(); got > 2 {
+			t.Errorf("round %d: expected at most 2 open files, got %d", round, got)
+		}
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(tempDir, name, "latest.log"))
+		if want := fmt.Sprintf("%s 0\n%s 1\n", name, name); err != nil || string(data) != want {
+			t.Errorf("stream %s: got %q (err %v), want %q", name, data, err, want)
+		}
+	}
+}
+
+// closeFailFS is an FS whose next file Close fails once armed.
+type closeFailFS struct {
+	*Memory
+	armed atomic.Bool
+}
+
+func (c *closeFailFS) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	f, err := c.Memory.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return closeFailFile{f, c}, nil
+}
+
+type closeFailFile struct {
+	File
+	fs *closeFailFS
+}
+
+func (f closeFailFile) Close() error {
+	err := f.File.Close()
+	if f.fs.armed.CompareAndSwap(true, false) {
+		return &fs.PathError{Op: "close", Path: "latest.log", Err: syscall.EIO}
+	}
+	return err
+}
+
+// TestManagerMaxOpenFilesErrorHandler verifies that an error handler may log
+// to another stream when closing an idle stream's file fails.
+func TestManagerMaxOpenFilesErrorHandler(t *testing.T) {
+	fsys := &closeFailFS{Memory: NewMemoryFS()}
+	var m *Manager
+	reported := make(chan error, 1)
+	m, err := NewManager(".", 0, WithFS(fsys), WithMaxOpenFiles(1), WithFlushOnNewline(),
+		WithErrorHandler(func(err error) {
+			w, _ := m.Writer("errors")
+			fmt.Fprintf(w, "%v\n", err)
+			reported <- err
+		}))
+	if err != nil {
+		t.Fatalf("failed to create Manager: %v", err)
+	}
+	a, _ := m.Writer("a")
+	b, _ := m.Writer("b")
+	fmt.Fprintln(a, "a")
+	fsys.armed.Store(true)
+	done := make(chan struct{})
+	go func() {
+		fmt.Fprintln(b, "b") // closes a's file, which fails
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("closing an idle file deadlocked")
+	}
+	if err := <-reported; !errors.Is(err, syscall.EIO) {
+		t.Errorf("expected the close failure to be reported, got %v", err)
+	}
+	m.Close() // reports a's close failure again
+}
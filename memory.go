@@ -0,0 +1,315 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory is an in-memory FS. It lets tests exercise a Writer's buffering,
+// rotation, and retention without touching the disk, then inspect the
+// resulting files. Memory is safe for concurrent use.
+type Memory struct {
+	mu    sync.Mutex
+	files map[string]*memNode
+	dirs  map[string]bool
+}
+
+// NewMemory creates a Writer backed by a new, empty Memory and returns both.
+// opts are applied as with New. Features that hand rotated file paths to other
+// code, such as archivers and uploaders, receive paths within the Memory and
+// so can't open them with the os package.
+func NewMemory(opts ...Option) (*Writer, *Memory, error) {
+	m := NewMemoryFS()
+	w, err := New(".", append(append([]Option{}, opts...), WithFS(m))...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return w, m, nil
+}
+
+var _ FS = (*Memory)(nil)
+
+// NewMemoryFS returns an empty Memory containing only the current directory,
+// ".", for use with WithFS.
+func NewMemoryFS() *Memory {
+	return &Memory{files: make(map[string]*memNode), dirs: map[string]bool{".": true}}
+}
+
+// Files returns a copy of the contents of every file in m, keyed by name, e.g.
+// "latest.log" and the names of rotated files.
+func (m *Memory) Files() map[string][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	files := make(map[string][]byte, len(m.files))
+	for name, n := range m.files {
+		files[name] = append([]byte(nil), n.data...)
+	}
+	return files
+}
+
+// ReadFile returns a copy of the contents of the named file.
+func (m *Memory) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), n.data...), nil
+}
+
+// memNode holds a file's contents. Like an inode, it outlives its name, so
+// files removed or renamed while open remain usable.
+type memNode struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// OpenFile implements FS.
+func (m *Memory) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	if !m.dirs[filepath.Dir(name)] || m.dirs[name] {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	n, ok := m.files[name]
+	switch {
+	case ok && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+	case !ok && flag&os.O_CREATE == 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	case !ok:
+		n = &memNode{mode: perm, modTime: now()}
+		m.files[name] = n
+	case flag&os.O_TRUNC != 0:
+		n.data, n.modTime = n.data[:0], now()
+	}
+	return &memFile{m: m, name: name, node: n, flag: flag}, nil
+}
+
+// Rename implements FS.
+func (m *Memory) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	oldpath, newpath = filepath.Clean(oldpath), filepath.Clean(newpath)
+	n, ok := m.files[oldpath]
+	if !ok || !m.dirs[filepath.Dir(newpath)] {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: fs.ErrNotExist}
+	}
+	delete(m.files, oldpath)
+	m.files[newpath] = n
+	return nil
+}
+
+// Remove implements FS.
+func (m *Memory) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	if _, ok := m.files[name]; ok {
+		delete(m.files, name)
+		return nil
+	}
+	if m.dirs[name] {
+		for path := range m.files {
+			if filepath.Dir(path) == name {
+				return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+			}
+		}
+		delete(m.dirs, name)
+		return nil
+	}
+	return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+}
+
+// Stat implements FS.
+func (m *Memory) Stat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stat(filepath.Clean(name))
+}
+
+func (m *Memory) stat(name string) (fs.FileInfo, error) {
+	if n, ok := m.files[name]; ok {
+		return n.info(name), nil
+	}
+	if m.dirs[name] {
+		return &memInfo{name: filepath.Base(name), mode: fs.ModeDir | DefaultDirMode}, nil
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir implements FS.
+func (m *Memory) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	if !m.dirs[name] {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	var entries []fs.DirEntry
+	for path, n := range m.files {
+		if filepath.Dir(path) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(n.info(path)))
+		}
+	}
+	for path := range m.dirs {
+		if path != name && filepath.Dir(path) == name {
+			fi, _ := m.stat(path)
+			entries = append(entries, fs.FileInfoToDirEntry(fi))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// MkdirAll implements FS.
+func (m *Memory) MkdirAll(path string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for path = filepath.Clean(path); !m.dirs[path]; path = filepath.Dir(path) {
+		if _, ok := m.files[path]; ok {
+			return &fs.PathError{Op: "mkdir", Path: path, Err: fs.ErrExist}
+		}
+		m.dirs[path] = true
+	}
+	return nil
+}
+
+func (n *memNode) info(name string) *memInfo {
+	return &memInfo{name: filepath.Base(name), size: int64(len(n.data)), mode: n.mode, modTime: n.modTime, node: n}
+}
+
+// memFile is an open memNode.
+type memFile struct {
+	m      *Memory
+	name   string
+	node   *memNode
+	flag   int
+	off    int64
+	closed bool
+}
+
+func (f *memFile) check(write bool) error {
+	switch {
+	case f.closed:
+		return fs.ErrClosed
+	case write && f.flag&(os.O_WRONLY|os.O_RDWR) == 0, !write && f.flag&os.O_WRONLY != 0:
+		return fs.ErrPermission
+	}
+	return nil
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	n, err := f.ReadAt(p, f.off)
+	f.off += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (f *memFile) ReadAt(p []byte, off int64) (int, error) {
+	f.m.mu.Lock()
+	defer f.m.mu.Unlock()
+	if err := f.check(false); err != nil {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: err}
+	}
+	if off >= int64(len(f.node.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.node.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	f.m.mu.Lock()
+	defer f.m.mu.Unlock()
+	if err := f.check(true); err != nil {
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: err}
+	}
+	if f.flag&os.O_APPEND != 0 {
+		f.off = int64(len(f.node.data))
+	}
+	if end := f.off + int64(len(p)); end > int64(len(f.node.data)) {
+		f.node.data = append(f.node.data, make([]byte, end-int64(len(f.node.data)))...)
+	}
+	copy(f.node.data[f.off:], p)
+	f.off += int64(len(p))
+	f.node.modTime = now()
+	return len(p), nil
+}
+
+func (f *memFile) Truncate(size int64) error {
+	f.m.mu.Lock()
+	defer f.m.mu.Unlock()
+	if err := f.check(true); err != nil {
+		return &fs.PathError{Op: "truncate", Path: f.name, Err: err}
+	}
+	if size < int64(len(f.node.data)) {
+		f.node.data = f.node.data[:size]
+	} else {
+		f.node.data = append(f.node.data, make([]byte, size-int64(len(f.node.data)))...)
+	}
+	f.node.modTime = now()
+	return nil
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) {
+	f.m.mu.Lock()
+	defer f.m.mu.Unlock()
+	if f.closed {
+		return nil, &fs.PathError{Op: "stat", Path: f.name, Err: fs.ErrClosed}
+	}
+	return f.node.info(f.name), nil
+}
+
+func (f *memFile) Sync() error {
+	f.m.mu.Lock()
+	defer f.m.mu.Unlock()
+	if f.closed {
+		return &fs.PathError{Op: "sync", Path: f.name, Err: fs.ErrClosed}
+	}
+	return nil
+}
+
+func (f *memFile) Close() error {
+	f.m.mu.Lock()
+	defer f.m.mu.Unlock()
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	return nil
+}
+
+// memInfo describes a file or directory in a Memory. Sys returns the file's
+// memNode, which identifies it across renames.
+type memInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+	node    *memNode
+}
+
+func (fi *memInfo) Name() string       { return fi.name }
+func (fi *memInfo) Size() int64        { return fi.size }
+func (fi *memInfo) Mode() fs.FileMode  { return fi.mode }
+func (fi *memInfo) ModTime() time.Time { return fi.modTime }
+func (fi *memInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi *memInfo) Sys() any           { return fi.node }
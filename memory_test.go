@@ -0,0 +1,76 @@
+package rlog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMemory verifies that rotation and retention work against a Memory.
+func TestMemory(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	w, m, err := NewMemory(WithMaxFileSize(12), WithMaxRotations(2))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := fmt.Fprintf(w, "line %d\n", i); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		clock = clock.Add(time.Second)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	files := m.Files()
+	if len(files) != 3 {
+		t.Fatalf("expected 2 rotated files and latest.log, got %d files", len(files))
+	}
+	if got := string(files["latest.log"]); got != "line 4\n" {
+		t.Errorf("latest.log mismatch: got %q, want %q", got, "line 4\n")
+	}
+	var all []string
+	for name, data := range files {
+		if name != "latest.log" {
+			all = append(all, string(data))
+		}
+	}
+	if joined := strings.Join(all, ""); !strings.Contains(joined, "line 2") || !strings.Contains(joined, "line 3") {
+		t.Errorf("expected the two newest rotated files to be kept, got %q", all)
+	}
+}
+
+// TestMemoryReopen verifies that a Memory-backed Writer recreates latest.log
+// after it's removed, as with the OS.
+func TestMemoryReopen(t *testing.T) {
+	w, m, err := NewMemory()
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	w.WriteString("before\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if err := m.Remove("latest.log"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	w.WriteString("after\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	data, err := m.ReadFile("latest.log")
+	if err != nil {
+		t.Fatalf("expected latest.log to be recreated: %v", err)
+	}
+	if string(data) != "after\n" {
+		t.Errorf("content mismatch: got %q, want %q", data, "after\n")
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMultiQueue is the number of writes a Multi queues per target.
+const DefaultMultiQueue = 1024
+
+// DefaultMultiTimeout is how long Flush and Close wait for a Multi's targets.
+const DefaultMultiTimeout = 5 * time.Second
+
+// Multi duplicates writes to several targets, like io.MultiWriter, except that
+// every target has its own queue and goroutine. A slow target only falls behind
+// on its own queue and a failing one only fails itself, so neither can block or
+// fail writes to the others, such as a local Writer next to a network sink.
+//
+// Write queues p for every target and returns without waiting. When a target's
+// queue is full, the write is dropped for that target and counted in Dropped.
+// Target errors, including targets that stall Flush or Close, are counted in
+// Failures and kept by LastError. Multi is safe for concurrent use.
+type Multi struct {
+	mu      sync.RWMutex // held for writing by Close to stop writes
+	closed  bool
+	targets []*multiTarget
+	lastErr atomic.Pointer[failure]
+}
+
+type multiTarget struct {
+	w        io.Writer
+	queue    chan multiEntry
+	dropped  atomic.Uint64
+	failures atomic.Uint64
+	lastErr  *atomic.Pointer[failure] // shared by the Multi's targets
+	stop     chan struct{}            // closed by Close once nothing more is queued
+	done     chan struct{}
+}
+
+// multiEntry is a write to perform, or a flush marker to send the result of
+// flushing the target to when reached.
+type multiEntry struct {
+	p       []byte
+	flushed chan error
+}
+
+// MultiWriter returns a Multi writing to targets, each with a queue of
+// DefaultMultiQueue writes.
+func MultiWriter(targets ...io.Writer) *Multi {
+	m := &Multi{}
+	for _, w := range targets {
+		t := &multiTarget{w: w, queue: make(chan multiEntry, DefaultMultiQueue), lastErr: &m.lastErr,
+			stop: make(chan struct{}), done: make(chan struct{})}
+		m.targets = append(m.targets, t)
+		go t.run()
+	}
+	return m
+}
+
+// Write queues a copy of p for every target. It returns ErrClosed after Close
+// and otherwise always succeeds.
+func (m *Multi) Write(p []byte) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return 0, ErrClosed
+	}
+	data := append([]byte(nil), p...) // shared read-only by every target
+	for _, t := range m.targets {
+		select {
+		case t.queue <- multiEntry{p: data}:
+		default:
+			t.dropped.Add(1)
+		}
+	}
+	return len(p), nil
+}
+
+// Flush waits up to DefaultMultiTimeout for every target to be handed the
+// writes queued before the call. Targets that have their own Flush method,
+// such as a Writer, are flushed too. Flush returns the first target's flush
+// error, or one wrapping ErrIOStalled for a target still busy when the time is
+// up, and ErrClosed after Close. Writes and Close don't wait on a Flush.
+func (m *Multi) Flush() error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultMultiTimeout)
+	defer cancel()
+	return m.FlushContext(ctx)
+}
+
+// FlushContext is like Flush but waits until ctx is done rather than
+// DefaultMultiTimeout.
+func (m *Multi) FlushContext(ctx context.Context) error {
+	m.mu.RLock()
+	closed := m.closed
+	m.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+	marks := make([]chan error, len(m.targets))
+	for i, t := range m.targets {
+		marks[i] = make(chan error, 1)
+		go t.enqueue(ctx, multiEntry{flushed: marks[i]})
+	}
+	var first error
+	for i, t := range m.targets {
+		var err error
+		select {
+		case err = <-marks[i]:
+		case <-t.done:
+			select {
+			case err = <-marks[i]:
+			default:
+				err = ErrClosed // closed before reaching the marker
+			}
+		case <-ctx.Done():
+			err = t.stalled("flush", i, ctx.Err())
+		}
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Dropped returns the number of writes dropped for each target, in the order
+// the targets were given, because its queue was full.
+func (m *Multi) Dropped() []uint64 {
+	counts := make([]uint64, len(m.targets))
+	for i, t := range m.targets {
+		counts[i] = t.dropped.Load()
+	}
+	return counts
+}
+
+// Failures returns the number of failed writes and flushes for each target,
+// in the order the targets were given.
+func (m *Multi) Failures() []uint64 {
+	counts := make([]uint64, len(m.targets))
+	for i, t := range m.targets {
+		counts[i] = t.failures.Load()
+	}
+	return counts
+}
+
+// LastError returns the most recent error from any target, or nil if there
+// hasn't been one.
+func (m *Multi) LastError() error {
+	if f := m.lastErr.Load(); f != nil {
+		return f.err
+	}
+	return nil
+}
+
+// Close waits up to DefaultMultiTimeout for every target to finish its queue,
+// then closes the targets that implement io.Closer and returns the first
+// error. A target still busy when the time is up is left running and not
+// closed, and reported with an error wrapping ErrIOStalled. Calling Close
+// again is a no-op returning nil.
+func (m *Multi) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultMultiTimeout)
+	defer cancel()
+	return m.CloseContext(ctx)
+}
+
+// CloseContext is like Close but waits until ctx is done rather than
+// DefaultMultiTimeout.
+func (m *Multi) CloseContext(ctx context.Context) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	for _, t := range m.targets {
+		close(t.stop)
+	}
+	m.mu.Unlock()
+	var first error
+	for i, t := range m.targets {
+		var err error
+		select {
+		case <-t.done:
+			if c, ok := t.w.(io.Closer); ok {
+				err = c.Close()
+			}
+		case <-ctx.Done():
+			err = t.stalled("close", i, ctx.Err())
+		}
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// enqueue queues e for t, waiting for room until ctx is done or t stops.
+func (t *multiTarget) enqueue(ctx context.Context, e multiEntry) {
+	select {
+	case t.queue <- e:
+	case <-t.stop:
+	case <-ctx.Done():
+	}
+}
+
+// run writes queued entries to the target until it's stopped and its queue is
+// empty.
+func (t *multiTarget) run() {
+	defer close(t.done)
+	for {
+		select {
+		case e := <-t.queue:
+			t.handle(e)
+		case <-t.stop:
+			for {
+				select {
+				case e := <-t.queue:
+					t.handle(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// handle performs the write or flush e.
+func (t *multiTarget) handle(e multiEntry) {
+	if e.flushed == nil {
+		if _, err := t.w.Write(e.p); err != nil {
+			t.fail(fmt.Errorf("multi writer target write failed: %w", err))
+		}
+		return
+	}
+	var err error
+	if f, ok := t.w.(interface{ Flush() error }); ok {
+		if err = f.Flush(); err != nil {
+			err = fmt.Errorf("multi writer target flush failed: %w", err)
+			t.fail(err)
+		}
+	}
+	e.flushed <- err
+}
+
+// stalled reports that t, the i'th target, didn't finish op before the wait
+// for it ended with cause.
+func (t *multiTarget) stalled(op string, i int, cause error) error {
+	err := fmt.Errorf("multi writer target %d %s: %w: %w", i, op, ErrIOStalled, cause)
+	t.fail(err)
+	return err
+}
+
+// fail counts err against t and remembers it as the Multi's last error.
+func (t *multiTarget) fail(err error) {
+	t.failures.Add(1)
+	t.lastErr.Store(&failure{err})
+}
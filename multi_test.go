@@ -0,0 +1,122 @@
+package rlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingWriter blocks every write until release is closed.
+type blockingWriter struct {
+	release chan struct{}
+	n       int
+}
+
+func (b *blockingWriter) Write(p []byte) (int, error) {
+	<-b.release
+	b.n++
+	return len(p), nil
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("connection refused") }
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// TestMultiWriter verifies that a stalled or failing target neither blocks nor
+// loses writes for the others.
+func TestMultiWriter(t *testing.T) {
+	var local lockedBuffer
+	slow := &blockingWriter{release: make(chan struct{})}
+	m := MultiWriter(&local, slow, failingWriter{})
+	const writes = DefaultMultiQueue + 100
+	for i := 0; i < writes; i++ {
+		if _, err := m.Write([]byte("line\n")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	close(slow.release)
+	if err := m.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	dropped := m.Dropped()
+	// The stalled target holds a full queue plus the write it's stuck on.
+	if dropped[1] < writes-DefaultMultiQueue-1 {
+		t.Errorf("expected the stalled target to drop at least %d writes, got %d", writes-DefaultMultiQueue-1, dropped[1])
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := local.buf.Len()/len("line\n") + int(dropped[0]); got != writes {
+		t.Errorf("local target: written + dropped = %d, want %d", got, writes)
+	}
+	if got := slow.n + int(dropped[1]); got != writes {
+		t.Errorf("stalled target: written + dropped = %d, want %d", got, writes)
+	}
+	failures := m.Failures()
+	if failures[0] != 0 || failures[2]+dropped[2] != writes {
+		t.Errorf("expected every write to the failing target to count, got failures %v and dropped %v", failures, dropped)
+	}
+	if err := m.LastError(); err == nil || err.Error() != "multi writer target write failed: connection refused" {
+		t.Errorf("expected the failing target's error, got %v", err)
+	}
+	if _, err := m.Write([]byte("late\n")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write after Close: got %v, want ErrClosed", err)
+	}
+}
+
+// flushFailingWriter fails every Flush.
+type flushFailingWriter struct{ lockedBuffer }
+
+func (*flushFailingWriter) Flush() error { return errors.New("disk full") }
+
+// TestMultiWriterStalled verifies that Flush and Close give up on a stalled
+// target without blocking writes, and that Flush returns target flush errors.
+func TestMultiWriterStalled(t *testing.T) {
+	local := &flushFailingWriter{}
+	stuck := &blockingWriter{release: make(chan struct{})}
+	defer close(stuck.release)
+	m := MultiWriter(local, stuck)
+	m.Write([]byte("line\n"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	flushed := make(chan error, 1)
+	go func() { flushed <- m.FlushContext(ctx) }()
+	if _, err := m.Write([]byte("during\n")); err != nil {
+		t.Errorf("Write during Flush failed: %v", err)
+	}
+	err := <-flushed
+	if err == nil || err.Error() != "multi writer target flush failed: disk full" {
+		t.Errorf("expected the local target's flush error, got %v", err)
+	}
+	if err := m.LastError(); !errors.Is(err, ErrIOStalled) {
+		t.Errorf("expected the stalled target to be reported, got %v", err)
+	}
+	if f := m.Failures(); f[0] != 1 || f[1] != 1 {
+		t.Errorf("expected one failure per target, got %v", f)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := m.CloseContext(ctx); !errors.Is(err, ErrIOStalled) {
+		t.Errorf("expected Close to report the stalled target, got %v", err)
+	}
+	if got := local.buf.String(); got != "line\nduring\n" {
+		t.Errorf("expected the local target to get every write, got %q", got)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NamingStrategy decides the names of rotated log files and reads them back,
+// so that retention, bundling, ListRotations, and other readers can order
+// files by rotation time. Names exclude the ".log" or ".log.gz" extension,
+// which the Writer adds and strips. TimeNaming is the default.
+type NamingStrategy interface {
+	// Name returns the name of a file rotated at t. seq is 0 on the first
+	// attempt and counts up while the returned name is already taken. Names
+	// must be plain file names other than "latest".
+	Name(t time.Time, seq int) string
+
+	// Parse returns the rotation time and seq a name was created with. ok is
+	// false for names the strategy doesn't recognize, which are ordered before
+	// recognized ones, by name.
+	Parse(name string) (t time.Time, seq int, ok bool)
+}
+
+// WithNaming sets the strategy used to name rotated files, e.g. to add a
+// tenant prefix or use sequence numbers. It replaces the default TimeNaming,
+// so WithRotationTimeLayout and WithHostAndPID no longer affect names. nil
+// restores the default.
+func WithNaming(n NamingStrategy) Option {
+	return func(w *Writer) {
+		w.naming = n
+	}
+}
+
+// TimeNaming is the default NamingStrategy. It names rotated files after the
+// time of rotation, e.g. "20240102-150405.000000", followed by Tag and, on a
+// collision, an increasing "_N" suffix.
+type TimeNaming struct {
+	Layout string // time.Format layout; DefaultRotationTimeLayout if empty
+	Tag    string // appended to the timestamp, see WithHostAndPID
+	UTC    bool   // use UTC rather than local time, see WithUTC
+}
+
+// Name implements NamingStrategy.
+func (n TimeNaming) Name(t time.Time, seq int) string {
+	if n.UTC {
+		t = t.UTC()
+	}
+	name := t.Format(n.layout()) + n.Tag
+	if seq > 0 {
+		name = fmt.Sprintf("%s_%d", name, seq)
+	}
+	return name
+}
+
+// Parse implements NamingStrategy. It reads the timestamp from the start of
+// name regardless of Tag, so files tagged by earlier processes, e.g. with
+// another PID, are still recognized. Only layouts of a fixed width can be
+// parsed this way.
+func (n TimeNaming) Parse(name string) (time.Time, int, bool) {
+	loc := time.Local
+	if n.UTC {
+		loc = time.UTC
+	}
+	width := len(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Format(n.layout()))
+	base, seq := splitRotatedSeq(name)
+	// A layout containing '_' may be mistaken for a collision suffix.
+	for _, c := range []struct {
+		s   string
+		seq int
+	}{{base, seq}, {name, 0}} {
+		if len(c.s) < width {
+			continue
+		}
+		if t, err := time.ParseInLocation(n.layout(), c.s[:width], loc); err == nil {
+			return t, c.seq, true
+		}
+	}
+	return time.Time{}, 0, false
+}
+
+func (n TimeNaming) layout() string {
+	if n.Layout == "" {
+		return DefaultRotationTimeLayout
+	}
+	return n.Layout
+}
+
+// namer returns the Writer's naming strategy, the configured TimeNaming unless
+// WithNaming was given.
+func (w *Writer) namer() NamingStrategy {
+	if w.naming != nil {
+		return w.naming
+	}
+	return TimeNaming{Layout: w.timeLayout, Tag: w.nameTag, UTC: w.utc}
+}
+
+// validNaming reports whether n produces plain, distinct file names.
+func validNaming(n NamingStrategy) bool {
+	t := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	first, second := n.Name(t, 0), n.Name(t, 1)
+	for _, name := range []string{first, second} {
+		if name == "" || name == "latest" || strings.ContainsAny(name, `/\`) {
+			return false
+		}
+	}
+	return first != second
+}
+
+// trimLogExt removes the log file extension from name.
+func trimLogExt(name string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".log")
+}
+
+// logFiles is like the logFiles function, but orders rotated files by the
+// rotation time and seq the Writer's naming strategy parses from them.
+func (w *Writer) logFiles() ([]string, error) {
+	names, err := logFiles(w.fs, w.dirPath)
+	if err != nil {
+		return nil, err
+	}
+	rotated := len(names)
+	for rotated > 0 && isActiveName(names[rotated-1]) {
+		rotated--
+	}
+	w.sortRotated(names[:rotated])
+	return names, nil
+}
+
+// sortRotated orders rotated file names by parsed rotation time and seq.
+// Names the strategy doesn't recognize come first, in rotatedLess order.
+func (w *Writer) sortRotated(names []string) {
+	type key struct {
+		t   time.Time
+		seq int
+		ok  bool
+	}
+	n := w.namer()
+	keys := make(map[string]key, len(names))
+	for _, name := range names {
+		t, seq, ok := n.Parse(trimLogExt(name))
+		keys[name] = key{t, seq, ok}
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		a, b := keys[names[i]], keys[names[j]]
+		switch {
+		case a.ok != b.ok:
+			return !a.ok
+		case !a.ok:
+			return rotatedLess(names[i], names[j])
+		case !a.t.Equal(b.t):
+			return a.t.Before(b.t)
+		case a.seq != b.seq:
+			return a.seq < b.seq
+		}
+		return rotatedLess(names[i], names[j])
+	})
+}
@@ -0,0 +1,126 @@
+package rlog
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// unixNaming names rotated files after the Unix second, unpadded, so that
+// names don't sort chronologically as strings.
+type unixNaming struct{}
+
+func (unixNaming) Name(t time.Time, seq int) string {
+	return fmt.Sprintf("app-%d-%d", t.Unix(), seq)
+}
+
+func (unixNaming) Parse(name string) (time.Time, int, bool) {
+	parts := strings.Split(strings.TrimPrefix(name, "app-"), "-")
+	if len(parts) != 2 {
+		return time.Time{}, 0, false
+	}
+	sec, err1 := strconv.ParseInt(parts[0], 10, 64)
+	seq, err2 := strconv.Atoi(parts[1])
+	return time.Unix(sec, 0), seq, err1 == nil && err2 == nil
+}
+
+// badNaming produces names with a path separator.
+type badNaming struct{}
+
+func (badNaming) Name(t time.Time, seq int) string    { return fmt.Sprintf("a/%d", seq) }
+func (badNaming) Parse(string) (time.Time, int, bool) { return time.Time{}, 0, false }
+
+// TestNaming verifies that a custom naming strategy names rotated files and
+// that retention and ListRotations order them by the times it parses.
+func TestNaming(t *testing.T) {
+	var clock time.Time
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	w, m, err := NewMemory(WithMaxFileSize(4), WithNaming(unixNaming{}), WithMaxRotations(2))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	for i := 0; i < 4; i++ { // every flush rotates, at 9s through 12s
+		clock = time.Unix(int64(9+i), 0)
+		w.WriteString("line\n")
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	files := m.Files()
+	if _, ok := files["app-9-0.log"]; ok {
+		t.Errorf("expected the oldest rotations to be deleted, got %v", keys(files))
+	}
+	rotations, err := w.ListRotations()
+	if err != nil {
+		t.Fatalf("ListRotations failed: %v", err)
+	}
+	if len(rotations) != 2 || rotations[0].Name != "app-11-0.log" || rotations[1].Name != "app-12-0.log" {
+		t.Fatalf("expected the two newest rotations in order, got %+v", rotations)
+	}
+	if rotations[1].Time.Unix() != 12 {
+		t.Errorf("expected a parsed rotation time of 12s, got %v", rotations[1].Time)
+	}
+}
+
+// TestTimeNaming verifies that TimeNaming parses its own names, including
+// collision suffixes and tags from other processes.
+func TestTimeNaming(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 15, 4, 5, 600000000, time.UTC)
+	tests := []struct {
+		n    TimeNaming
+		name string
+		seq  int
+	}{
+		{TimeNaming{UTC: true}, "20240102-150405.600000", 0},
+		{TimeNaming{UTC: true}, "20240102-150405.600000_3", 3},
+		{TimeNaming{UTC: true, Tag: ".web-1.42"}, "20240102-150405.600000.web-1.4242_1", 1},
+		{TimeNaming{UTC: true, Layout: "2006_01_02_15_04_05.000000"}, "2024_01_02_15_04_05.600000", 0},
+		{TimeNaming{UTC: true, Layout: "2006_01_02_15_04_05.000000"}, "2024_01_02_15_04_05.600000_2", 2},
+	}
+	for _, tt := range tests {
+		got, seq, ok := tt.n.Parse(tt.name)
+		if !ok || !got.Equal(ts) || seq != tt.seq {
+			t.Errorf("Parse(%q) = %v, %d, %v; want %v, %d, true", tt.name, got, seq, ok, ts, tt.seq)
+		}
+	}
+	if name := (TimeNaming{UTC: true}).Name(ts, 2); name != "20240102-150405.600000_2" {
+		t.Errorf("expected %q, got %q", "20240102-150405.600000_2", name)
+	}
+	if _, _, ok := (TimeNaming{}).Parse("backup"); ok {
+		t.Errorf("expected a foreign name not to parse")
+	}
+}
+
+// TestHostAndPID verifies that rotated names carry the hostname and PID and
+// still parse in ListRotations.
+func TestHostAndPID(t *testing.T) {
+	w, m, err := NewMemory(WithMaxFileSize(4), WithHostAndPID())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	for i := 0; i < 2; i++ {
+		w.WriteString("line\n")
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	suffix := fmt.Sprintf(".%d.log", os.Getpid())
+	var found bool
+	for name := range m.Files() {
+		found = found || strings.HasSuffix(name, suffix)
+	}
+	if !found {
+		t.Fatalf("expected a rotated file ending in %q, got %v", suffix, keys(m.Files()))
+	}
+	rotations, err := w.ListRotations()
+	if err != nil || len(rotations) == 0 || rotations[0].Time.IsZero() {
+		t.Errorf("expected rotation times to parse, got %+v, %v", rotations, err)
+	}
+}
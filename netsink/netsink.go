@@ -0,0 +1,447 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+// Package netsink implements an rlog.Sink that forwards flushed log data to a
+// remote collector over TCP or TLS.
+//
+// Data is queued in memory and sent in batches by a background goroutine,
+// which reconnects with exponential backoff whenever the connection fails.
+// While the collector is unreachable and the queue overflows, data is spilled
+// to disk if a spill directory is configured, and replayed once the
+// connection is back. Delivery is at-least-once: data that was partially sent
+// when a connection failed is sent again.
+//
+// Usage:
+//
+//	s, err := netsink.New("tcp", "collector:5140", netsink.WithSpillDir("logs/spill"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	w, err := rlog.New("logs", rlog.WithSink(s))
+package netsink
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultMaxQueue     = 4 * 1024 * 1024 // 4 MB
+	DefaultMinBackoff   = 100 * time.Millisecond
+	DefaultMaxBackoff   = 30 * time.Second
+	DefaultDialTimeout  = 5 * time.Second
+	DefaultCloseTimeout = 5 * time.Second
+)
+
+// Spill file names. The active file is appended to while the collector is
+// down; it's renamed to the sending file while being replayed.
+const (
+	spillName   = "netsink.spill"
+	sendingName = "netsink.spill.sending"
+)
+
+// ErrClosed is returned by Write after Close.
+var ErrClosed = errors.New("netsink: closed")
+
+// Option configures a Sink.
+type Option func(*Sink)
+
+// WithTLS connects using TLS with the given configuration.
+func WithTLS(cfg *tls.Config) Option {
+	return func(s *Sink) {
+		s.tlsConfig = cfg
+	}
+}
+
+// WithSpillDir sets a directory, created if needed, in which data that
+// overflows the in-memory queue is stored until the collector is reachable.
+// Without it, overflowing data is dropped, oldest first.
+func WithSpillDir(dir string) Option {
+	return func(s *Sink) {
+		s.spillDir = dir
+	}
+}
+
+// WithMaxQueue sets the number of bytes held in memory before spilling or dropping.
+func WithMaxQueue(n int) Option {
+	return func(s *Sink) {
+		s.maxQueue = n
+	}
+}
+
+// WithBackoff sets the delay after the first failed connection attempt and
+// the cap it doubles up to for each further failure.
+func WithBackoff(min, max time.Duration) Option {
+	return func(s *Sink) {
+		s.minBackoff, s.maxBackoff = min, max
+	}
+}
+
+// WithDialTimeout bounds each connection attempt.
+func WithDialTimeout(d time.Duration) Option {
+	return func(s *Sink) {
+		s.dialTimeout = d
+	}
+}
+
+// WithCloseTimeout bounds how long Close tries to deliver queued data before
+// spilling (or dropping) what remains.
+func WithCloseTimeout(d time.Duration) Option {
+	return func(s *Sink) {
+		s.closeTimeout = d
+	}
+}
+
+// Sink forwards data to a remote collector. It implements rlog.Sink.
+type Sink struct {
+	network, addr string
+	tlsConfig     *tls.Config
+	spillDir      string
+	maxQueue      int
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+	dialTimeout   time.Duration
+	closeTimeout  time.Duration
+
+	mu      sync.Mutex
+	queue   [][]byte
+	queued  int
+	spilled bool // whether the spill file holds data
+	closed  bool
+	dropped atomic.Uint64
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New returns a Sink sending to addr on the named network, e.g. "tcp".
+// Connecting happens in the background; New doesn't wait for it.
+func New(network, addr string, opts ...Option) (*Sink, error) {
+	s := &Sink{
+		network:      network,
+		addr:         addr,
+		maxQueue:     DefaultMaxQueue,
+		minBackoff:   DefaultMinBackoff,
+		maxBackoff:   DefaultMaxBackoff,
+		dialTimeout:  DefaultDialTimeout,
+		closeTimeout: DefaultCloseTimeout,
+		wake:         make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.maxQueue <= 0 || s.minBackoff <= 0 || s.maxBackoff < s.minBackoff || s.dialTimeout <= 0 || s.closeTimeout <= 0 {
+		return nil, fmt.Errorf("netsink: queue size, backoff, and timeouts must be positive")
+	}
+	if s.spillDir != "" {
+		if err := os.MkdirAll(s.spillDir, 0o755); err != nil {
+			return nil, fmt.Errorf("netsink: failed to create spill directory: %w", err)
+		}
+		// Data spilled by a previous run is replayed first.
+		fi, err := os.Stat(filepath.Join(s.spillDir, spillName))
+		s.spilled = err == nil && fi.Size() > 0
+	}
+	go s.run()
+	return s, nil
+}
+
+// Write queues a copy of p for delivery. It never blocks on the network or
+// the disk: overflowing data is spilled by the background goroutine, so the
+// queue may briefly exceed its maximum size while it's busy connecting or
+// sending.
+func (s *Sink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, ErrClosed
+	}
+	s.queue = append(s.queue, append([]byte(nil), p...))
+	s.queued += len(p)
+	if s.queued > s.maxQueue && s.spillDir == "" {
+		s.dropOldest()
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
+
+// Dropped returns the number of bytes discarded because the queue overflowed
+// and no spill directory was available.
+func (s *Sink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close tries to deliver queued data within the close timeout, spills or drops
+// whatever remains, and closes the connection.
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+	s.closed = true
+	s.mu.Unlock()
+	close(s.stop)
+	<-s.done
+	return nil
+}
+
+// dropOldest drops the oldest data until the queue fits. s.mu must be held.
+func (s *Sink) dropOldest() {
+	for s.queued > s.maxQueue && len(s.queue) > 0 {
+		s.dropped.Add(uint64(len(s.queue[0])))
+		s.queued -= len(s.queue[0])
+		s.queue = s.queue[1:]
+	}
+}
+
+// spillOverflow moves the queue to the spill file if it exceeds the maximum
+// size, dropping the oldest data that can't be spilled. all spills the queue
+// regardless of its size. It's only called by the background goroutine, which
+// owns the spill files.
+func (s *Sink) spillOverflow(all bool) {
+	s.mu.Lock()
+	if s.spillDir == "" || s.queued == 0 || (!all && s.queued <= s.maxQueue) {
+		s.mu.Unlock()
+		return
+	}
+	queue := s.queue
+	s.queue, s.queued = nil, 0
+	s.mu.Unlock()
+
+	rest, wrote := spill(filepath.Join(s.spillDir, spillName), queue)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spilled = s.spilled || wrote
+	for _, chunk := range rest {
+		s.queued += len(chunk)
+	}
+	s.queue = append(rest, s.queue...)
+	s.dropOldest()
+}
+
+// spill appends queue to the file at path. It returns the data that couldn't
+// be written, starting with the unwritten part of the chunk that failed, and
+// whether any was.
+func spill(path string, queue [][]byte) (rest [][]byte, wrote bool) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return queue, false
+	}
+	defer f.Close()
+	for i, chunk := range queue {
+		if n, err := f.Write(chunk); err != nil {
+			rest = queue[i:]
+			rest[0] = chunk[n:]
+			return rest, i > 0 || n > 0
+		}
+	}
+	return nil, len(queue) > 0
+}
+
+// take removes and returns everything queued, concatenated.
+func (s *Sink) take() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var batch []byte
+	for _, chunk := range s.queue {
+		batch = append(batch, chunk...)
+	}
+	s.queue, s.queued = nil, 0
+	return batch
+}
+
+// requeue puts an undelivered batch back at the front of the queue.
+func (s *Sink) requeue(batch []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append([][]byte{batch}, s.queue...)
+	s.queued += len(batch)
+	if s.queued > s.maxQueue && s.spillDir == "" {
+		s.dropOldest()
+	}
+}
+
+// hasWork reports whether anything awaits delivery.
+func (s *Sink) hasWork() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queued > 0 || s.spilled || (s.spillDir != "" && fileExists(filepath.Join(s.spillDir, sendingName)))
+}
+
+func (s *Sink) dial() (net.Conn, error) {
+	d := &net.Dialer{Timeout: s.dialTimeout}
+	if s.tlsConfig != nil {
+		return tls.DialWithDialer(d, s.network, s.addr, s.tlsConfig)
+	}
+	return d.Dial(s.network, s.addr)
+}
+
+// send delivers spilled data, oldest first, followed by the queue.
+func (s *Sink) send(conn net.Conn) error {
+	if err := s.sendSpill(conn); err != nil {
+		return err
+	}
+	batch := s.take()
+	if len(batch) == 0 {
+		return nil
+	}
+	if _, err := conn.Write(batch); err != nil {
+		s.requeue(batch)
+		return err
+	}
+	return nil
+}
+
+// sendSpill replays the spill file. The file is renamed before sending so
+// that concurrent spills start a new one, and removed once fully sent.
+func (s *Sink) sendSpill(conn net.Conn) error {
+	if s.spillDir == "" {
+		return nil
+	}
+	sending := filepath.Join(s.spillDir, sendingName)
+	if !fileExists(sending) {
+		s.mu.Lock()
+		if s.spilled {
+			if err := os.Rename(filepath.Join(s.spillDir, spillName), sending); err != nil {
+				s.mu.Unlock()
+				return err
+			}
+			s.spilled = false
+		}
+		s.mu.Unlock()
+	}
+	f, err := os.Open(sending)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	_, err = io.Copy(conn, f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	return os.Remove(sending)
+}
+
+func (s *Sink) run() {
+	defer close(s.done)
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+	backoff := s.minBackoff
+	for {
+		s.spillOverflow(false)
+		if !s.hasWork() {
+			select {
+			case <-s.wake:
+				continue
+			case <-s.stop:
+				s.drain(conn)
+				return
+			}
+		}
+		if conn == nil {
+			var err error
+			if conn, err = s.dial(); err != nil {
+				conn = nil
+				if !s.wait(backoff) {
+					s.drain(nil)
+					return
+				}
+				backoff = min(backoff*2, s.maxBackoff)
+				continue
+			}
+		}
+		if err := s.send(conn); err != nil {
+			conn.Close()
+			conn = nil
+			if !s.wait(backoff) {
+				s.drain(nil)
+				return
+			}
+			backoff = min(backoff*2, s.maxBackoff)
+			continue
+		}
+		backoff = s.minBackoff
+		select {
+		case <-s.stop:
+			s.drain(conn)
+			return
+		default:
+		}
+	}
+}
+
+// wait sleeps for d, spilling overflowing data written meanwhile. It reports
+// false if Close was called.
+func (s *Sink) wait(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	for {
+		s.spillOverflow(false)
+		select {
+		case <-t.C:
+			return true
+		case <-s.wake:
+		case <-s.stop:
+			return false
+		}
+	}
+}
+
+// drain makes a final delivery attempt bounded by the close timeout, then
+// spills or drops whatever is left.
+func (s *Sink) drain(conn net.Conn) {
+	deadline := time.Now().Add(s.closeTimeout)
+	if s.hasWork() {
+		if conn == nil {
+			d := &net.Dialer{Deadline: deadline}
+			var err error
+			if s.tlsConfig != nil {
+				conn, err = tls.DialWithDialer(d, s.network, s.addr, s.tlsConfig)
+			} else {
+				conn, err = d.Dial(s.network, s.addr)
+			}
+			if err != nil {
+				conn = nil
+			} else {
+				defer conn.Close()
+			}
+		}
+		if conn != nil {
+			conn.SetWriteDeadline(deadline)
+			s.send(conn)
+		}
+	}
+	s.spillOverflow(true)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropped.Add(uint64(s.queued))
+	s.queue, s.queued = nil, 0
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
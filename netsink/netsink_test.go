@@ -0,0 +1,130 @@
+package netsink
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector accepts connections on ln and accumulates everything received.
+type collector struct {
+	mu   sync.Mutex
+	data strings.Builder
+}
+
+func (c *collector) serve(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			buf := make([]byte, 4096)
+			for {
+				n, err := conn.Read(buf)
+				c.mu.Lock()
+				c.data.Write(buf[:n])
+				c.mu.Unlock()
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+func (c *collector) waitFor(t *testing.T, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		c.mu.Lock()
+		got := c.data.String()
+		c.mu.Unlock()
+		if got == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("collector content mismatch: got %q, want %q", got, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestSinkDelivers verifies that written data reaches the collector.
+func TestSinkDelivers(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	c := &collector{}
+	go c.serve(ln)
+
+	s, err := New("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to create Sink: %v", err)
+	}
+	s.Write([]byte("one\n"))
+	s.Write([]byte("two\n"))
+	c.waitFor(t, "one\ntwo\n")
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := s.Write([]byte("late\n")); err != ErrClosed {
+		t.Errorf("expected ErrClosed after Close, got %v", err)
+	}
+}
+
+// TestSinkSpill verifies that data spilled while the collector is down is replayed in order.
+func TestSinkSpill(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close() // collector starts out down
+
+	spillDir := filepath.Join(t.TempDir(), "spill")
+	s, err := New("tcp", addr, WithSpillDir(spillDir), WithMaxQueue(8),
+		WithBackoff(5*time.Millisecond, 20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create Sink: %v", err)
+	}
+	defer s.Close()
+	var want strings.Builder
+	for _, msg := range []string{"first\n", "second\n", "third\n"} {
+		want.WriteString(msg)
+		s.Write([]byte(msg))
+	}
+	// Spilling happens in the background.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(filepath.Join(spillDir, spillName))
+		if err == nil && len(data) > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected data to be spilled to disk, got %q (err %v)", string(data), err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if ln, err = net.Listen("tcp", addr); err != nil {
+		t.Skipf("failed to reuse collector address: %v", err)
+	}
+	defer ln.Close()
+	c := &collector{}
+	go c.serve(ln)
+	c.waitFor(t, want.String())
+	if s.Dropped() != 0 {
+		t.Errorf("expected no dropped bytes, got %d", s.Dropped())
+	}
+	if _, err := os.Stat(filepath.Join(spillDir, sendingName)); !os.IsNotExist(err) {
+		t.Errorf("expected replayed spill file to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package rlog
+
+import "os"
+
+// openFile is os.OpenFile. Open files can already be renamed and removed.
+var openFile = os.OpenFile
@@ -0,0 +1,75 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build windows
+
+package rlog
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// Access rights granted by GENERIC_WRITE, besides those in syscall.
+const (
+	fileWriteEA         = 0x00000010
+	standardRightsWrite = 0x00020000
+)
+
+// openFile is os.OpenFile, except that the file is also opened with
+// FILE_SHARE_DELETE. os.OpenFile only shares reading and writing, so while the
+// Writer holds "latest.log" open, other processes can't rename or delete it,
+// and tail tools that open it the same way block rotation until they close it.
+// With FILE_SHARE_DELETE, an open file can be renamed and removed as on Unix.
+func openFile(name string, flag int, perm fs.FileMode) (*os.File, error) {
+	namep, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	var access uint32
+	switch flag & (os.O_RDONLY | os.O_WRONLY | os.O_RDWR) {
+	case os.O_RDONLY:
+		access = syscall.GENERIC_READ
+	case os.O_WRONLY:
+		access = syscall.GENERIC_WRITE
+	case os.O_RDWR:
+		access = syscall.GENERIC_READ | syscall.GENERIC_WRITE
+	}
+	if flag&os.O_CREATE != 0 {
+		access |= syscall.GENERIC_WRITE
+	}
+	if flag&os.O_APPEND != 0 && flag&os.O_TRUNC == 0 {
+		// Everything GENERIC_WRITE grants except FILE_WRITE_DATA, which would
+		// allow writing anywhere rather than only at the end, as in os.OpenFile.
+		access &^= syscall.GENERIC_WRITE
+		access |= syscall.FILE_APPEND_DATA | syscall.FILE_WRITE_ATTRIBUTES | fileWriteEA | standardRightsWrite | syscall.SYNCHRONIZE
+	}
+	var createmode uint32
+	switch {
+	case flag&(os.O_CREATE|os.O_EXCL) == os.O_CREATE|os.O_EXCL:
+		createmode = syscall.CREATE_NEW
+	case flag&os.O_CREATE != 0:
+		createmode = syscall.OPEN_ALWAYS
+	default:
+		createmode = syscall.OPEN_EXISTING
+	}
+	attrs := uint32(syscall.FILE_ATTRIBUTE_NORMAL)
+	if access&syscall.GENERIC_WRITE == 0 && flag&os.O_APPEND == 0 {
+		attrs |= syscall.FILE_FLAG_BACKUP_SEMANTICS // needed to open directories
+	}
+	share := uint32(syscall.FILE_SHARE_READ | syscall.FILE_SHARE_WRITE | syscall.FILE_SHARE_DELETE)
+	h, err := syscall.CreateFile(namep, access, share, nil, createmode, attrs, 0)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	f := os.NewFile(uintptr(h), name)
+	if flag&os.O_TRUNC != 0 {
+		if err := f.Truncate(0); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+package rlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestShareDelete verifies that the active file can be renamed while the
+// Writer holds it open, e.g. by an external rotation tool.
+func TestShareDelete(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithFlushOnNewline())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	w.WriteString("before\n")
+	active := filepath.Join(tempDir, "latest.log")
+	moved := filepath.Join(tempDir, "moved.log")
+	if err := os.Rename(active, moved); err != nil {
+		t.Fatalf("failed to rename the open active file: %v", err)
+	}
+	w.WriteString("after\n") // recreates the active file
+	if got, _ := os.ReadFile(moved); string(got) != "before\n" {
+		t.Errorf("moved file: got %q, want %q", got, "before\n")
+	}
+	if got, _ := os.ReadFile(active); string(got) != "after\n" {
+		t.Errorf("active file: got %q, want %q", got, "after\n")
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"compress/gzip"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+)
+
+// Errors returned by New when an option is given a nonsensical value.
+var (
+	ErrInvalidMaxFileSize   = errors.New("max file size must be positive")
+	ErrInvalidMaxBufSize    = errors.New("max buffer size must be positive")
+	ErrInvalidMaxBufAge     = errors.New("max buffer age must be positive")
+	ErrInvalidSigner        = errors.New("invalid signing key")
+	ErrInvalidRetention     = errors.New("retention limits must not be negative")
+	ErrInvalidArchive       = errors.New("archive workers and attempts must be positive")
+	ErrInvalidArchiveQueue  = errors.New("archive queue limit must not be negative")
+	ErrInvalidRateLimit     = errors.New("rate limit and burst must be positive")
+	ErrInvalidSampling      = errors.New("sampling fraction must be in (0, 1]")
+	ErrInvalidTimeLayout    = errors.New("rotation time layout must produce a plain file name")
+	ErrInvalidNaming        = errors.New("naming strategy must produce distinct plain file names")
+	ErrInvalidMinRotation   = errors.New("min rotation interval must not be negative")
+	ErrInvalidFS            = errors.New("filesystem must not be nil")
+	ErrInvalidCompression   = errors.New("invalid compression level")
+	ErrInvalidRetry         = errors.New("retry attempts must be positive and backoff must not be negative")
+	ErrInvalidSyncInterval  = errors.New("sync interval must not be negative")
+	ErrInvalidFlushDeadline = errors.New("flush deadline must not be negative")
+	ErrInvalidRotation      = errors.New("unknown rotation strategy")
+	ErrInvalidSnapshot      = errors.New("snapshot limits must not be negative")
+	ErrInvalidPauseLimit    = errors.New("pause limit must not be negative")
+	ErrInvalidMaxLines      = errors.New("max lines must not be negative")
+	ErrInvalidRevalidate    = errors.New("revalidation interval must not be negative")
+	ErrInvalidMaxOpenFiles  = errors.New("max open files must not be negative")
+)
+
+// Option defines a function that configures a Writer.
+type Option func(*Writer)
+
+// WithMaxFileSize sets the maximum size of the log file before it's rotated.
+func WithMaxFileSize(size int64) Option {
+	return func(w *Writer) {
+		w.maxFileSize = size
+	}
+}
+
+// WithMaxBufSize sets the maximum size of the internal buffer before flushing.
+func WithMaxBufSize(size int) Option {
+	return func(w *Writer) {
+		w.maxBufSize = size
+		if cap(w.buf) < size {
+			newBuf := make([]byte, len(w.buf), size)
+			copy(newBuf, w.buf) // in case there's data in the buffer
+			w.buf = newBuf
+		}
+	}
+}
+
+// WithMaxBufAge sets the age of the buffer. On Write(), if the buffer is older
+// than maxBufAge, it will be flushed.
+func WithMaxBufAge(d time.Duration) Option {
+	return func(w *Writer) {
+		w.maxBufAge = d
+	}
+}
+
+// WithMkdirAll configures New to create the log directory, along with any
+// missing parents, using the mode set by WithDirMode.
+func WithMkdirAll() Option {
+	return func(w *Writer) {
+		w.mkdirAll = true
+	}
+}
+
+// WithDirMode sets the permission bits used for directories created by the
+// Writer (before umask). The default is DefaultDirMode.
+func WithDirMode(mode os.FileMode) Option {
+	return func(w *Writer) {
+		w.dirMode = mode
+	}
+}
+
+// WithFlushOnNewline configures the Writer to flush immediately whenever a
+// Write ends in a newline, regardless of the buffer's size or age. This keeps
+// the last complete line on disk without disabling buffering for partial lines.
+func WithFlushOnNewline() Option {
+	return func(w *Writer) {
+		w.flushOnNewline = true
+	}
+}
+
+// WithChaos randomizes the Writer's flush and rotation decisions using a
+// pseudo-random source seeded with seed. Writes may be flushed early or held
+// longer than usual, and files may be rotated before reaching maxFileSize.
+// No data is lost or reordered; only the timing changes.
+//
+// WithChaos is intended for tests only. It helps surface code that depends on
+// rlog's otherwise deterministic flush behavior, e.g. tests that read the log
+// file without calling Flush first.
+func WithChaos(seed int64) Option {
+	return func(w *Writer) {
+		w.chaos = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithStreamCompression configures the Writer to gzip the active file as it's
+// written, rather than writing plain text. The active file is "latest.log.gz"
+// and rotated files keep the ".log.gz" extension. Every flush ends with a gzip
+// flush point, so the file can be decompressed up to the last flush at any
+// time, and reopening the file appends a new gzip member, which standard gzip
+// readers handle transparently.
+//
+// With stream compression, maxFileSize bounds the compressed size on disk.
+func WithStreamCompression() Option {
+	return func(w *Writer) {
+		w.compress = true
+	}
+}
+
+// WithFilter adds a function that transforms each Write's data before it's
+// buffered, e.g. to redact secrets or normalize line endings. Filters run in
+// the order they're added, each receiving the previous one's output; returning
+// an empty slice discards the write. Write still reports len(p) on success.
+//
+// A filter must not modify or retain its argument, which may be the caller's
+// buffer; return a new slice for any change. Filters see exactly what each
+// Write receives, so with log.Logger that's one complete line per call.
+func WithFilter(filter func([]byte) []byte) Option {
+	return func(w *Writer) {
+		w.filters = append(w.filters, filter)
+	}
+}
+
+// WithPreallocate reserves disk space for the active log file up to the
+// maximum file size whenever it's opened. This reduces fragmentation and
+// surfaces a full disk at rotation rather than midway through a flush. The
+// file's reported size is unchanged, so rotation works as usual.
+//
+// Preallocation is best effort: it's only implemented on Linux and is skipped
+// on filesystems that don't support it.
+func WithPreallocate() Option {
+	return func(w *Writer) {
+		w.preallocate = true
+	}
+}
+
+// WithMinRotationInterval sets the minimum time between rotations. While the
+// interval hasn't elapsed, the active file is allowed to grow past the maximum
+// file size instead of being rotated, so a tiny size limit or a flood of logs
+// can't create files fast enough to exhaust inodes. Zero disables the guard.
+func WithMinRotationInterval(d time.Duration) Option {
+	return func(w *Writer) {
+		w.minRotation = d
+	}
+}
+
+// WithRotationTimeLayout sets the time.Format layout used to name rotated
+// files, DefaultRotationTimeLayout by default. The Writer orders rotated files
+// by the times it parses back, which needs a layout of fixed width; tools like
+// VerifyChainDir sort names as strings, so the layout should also sort
+// chronologically, e.g. "2006-01-02T15-04-05.000000". See also WithNaming.
+func WithRotationTimeLayout(layout string) Option {
+	return func(w *Writer) {
+		w.timeLayout = layout
+	}
+}
+
+// WithHostAndPID appends the hostname and process ID to rotated file names,
+// e.g. "20240102-150405.000000.web-1.4242.log", so rotated files from several
+// processes, such as pods archiving to one shared volume, never collide on
+// timestamps alone. Names still sort chronologically. Only rotated names
+// change; each process still needs its own directory for its active file.
+func WithHostAndPID() Option {
+	return func(w *Writer) {
+		host, err := os.Hostname()
+		if err != nil || host == "" {
+			host = "unknown"
+		}
+		host = strings.NewReplacer("/", "-", `\`, "-", "_", "-").Replace(host)
+		w.nameTag = fmt.Sprintf(".%s.%d", host, os.Getpid())
+	}
+}
+
+// WithUTC names rotated files using UTC rather than local time, so that names
+// from hosts in different time zones interleave correctly.
+func WithUTC() Option {
+	return func(w *Writer) {
+		w.utc = true
+	}
+}
+
+// WithErrorHandler sets a function called with errors that the Writer hits
+// while writing out flushes, including those absorbed by WithFallback and
+// ErrSlowFlush from WithFlushDeadline, as well as log files reopened after
+// being removed or replaced, failed sink writes, files that couldn't be
+// archived, and corrupt files found by WithStartupVerification, so they can
+// be alerted on. It may be called from any goroutine, possibly with the
+// Writer's locks held, so it must return quickly and not call the Writer's
+// methods.
+func WithErrorHandler(fn func(error)) Option {
+	return func(w *Writer) {
+		w.onError = fn
+	}
+}
+
+// WithSync configures the Writer to be safe for concurrent use.
+//
+// Deprecated: Writers are safe for concurrent use by default, so WithSync does
+// nothing. See WithNoSync to opt out.
+func WithSync() Option {
+	return func(w *Writer) {}
+}
+
+// WithNoSync turns off the Writer's internal locking, saving its small cost
+// when every call is already serialized by the caller, e.g. by log.Logger.
+// Such a Writer must not be used from several goroutines at once, so it can't
+// be used with helpers that call it from their own goroutines, such as
+// InstallSignalHandler. WithSyncInterval overrides it, keeping the locks its
+// goroutine needs.
+func WithNoSync() Option {
+	return func(w *Writer) {
+		w.noSync = true
+	}
+}
+
+// withLocks overrides WithNoSync for Writers shared between goroutines by
+// the package, e.g. a Manager's streams.
+func withLocks() Option {
+	return func(w *Writer) {
+		w.noSync = false
+	}
+}
+
+// validate reports the first invalid option value applied to w.
+func (w *Writer) validate() error {
+	if w.fs == nil {
+		return ErrInvalidFS
+	}
+	if w.maxFileSize <= 0 {
+		return fmt.Errorf("%w, got %d", ErrInvalidMaxFileSize, w.maxFileSize)
+	}
+	if w.maxLines < 0 {
+		return fmt.Errorf("%w, got %d", ErrInvalidMaxLines, w.maxLines)
+	}
+	if w.maxBufSize <= 0 {
+		return fmt.Errorf("%w, got %d", ErrInvalidMaxBufSize, w.maxBufSize)
+	}
+	if w.maxBufAge <= 0 {
+		return fmt.Errorf("%w, got %v", ErrInvalidMaxBufAge, w.maxBufAge)
+	}
+	if w.signer != nil && len(w.signer) != ed25519.PrivateKeySize {
+		return fmt.Errorf("%w: Ed25519 private key must be %d bytes, got %d", ErrInvalidSigner, ed25519.PrivateKeySize, len(w.signer))
+	}
+	if w.maxRotations < 0 || w.maxAge < 0 || w.bundleAfter < 0 {
+		return fmt.Errorf("%w, got %d rotations, %v max age, and %v bundling", ErrInvalidRetention, w.maxRotations, w.maxAge, w.bundleAfter)
+	}
+	if name := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Format(w.timeLayout); name == "" || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%w, got %q", ErrInvalidTimeLayout, w.timeLayout)
+	}
+	if w.naming != nil && !validNaming(w.naming) {
+		return fmt.Errorf("%w, got %q", ErrInvalidNaming, w.naming.Name(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), 0))
+	}
+	if w.compressLevel < gzip.HuffmanOnly || w.compressLevel > gzip.BestCompression {
+		return fmt.Errorf("%w, got %d", ErrInvalidCompression, w.compressLevel)
+	}
+	if w.retry.Attempts <= 0 || w.retry.Backoff < 0 {
+		return fmt.Errorf("%w, got %d attempts and %v backoff", ErrInvalidRetry, w.retry.Attempts, w.retry.Backoff)
+	}
+	if w.flushDeadline < 0 {
+		return fmt.Errorf("%w, got %v", ErrInvalidFlushDeadline, w.flushDeadline)
+	}
+	if w.maxOpenFiles < 0 {
+		return fmt.Errorf("%w, got %d", ErrInvalidMaxOpenFiles, w.maxOpenFiles)
+	}
+	if w.revalidate < 0 {
+		return fmt.Errorf("%w, got %v", ErrInvalidRevalidate, w.revalidate)
+	}
+	if w.syncInterval < 0 {
+		return fmt.Errorf("%w, got %v", ErrInvalidSyncInterval, w.syncInterval)
+	}
+	if w.rotation < RenameRotation || w.rotation > CopyTruncateRotation {
+		return fmt.Errorf("%w, got %d", ErrInvalidRotation, w.rotation)
+	}
+	if w.snapshotFiles < 0 || w.snapshotBytes < 0 {
+		return fmt.Errorf("%w, got %d files and %d bytes", ErrInvalidSnapshot, w.snapshotFiles, w.snapshotBytes)
+	}
+	if w.pauseLimit < 0 {
+		return fmt.Errorf("%w, got %d", ErrInvalidPauseLimit, w.pauseLimit)
+	}
+	if w.minRotation < 0 {
+		return fmt.Errorf("%w, got %v", ErrInvalidMinRotation, w.minRotation)
+	}
+	if w.sampleRate <= 0 || w.sampleRate > 1 {
+		return fmt.Errorf("%w, got %v", ErrInvalidSampling, w.sampleRate)
+	}
+	if w.limiter != nil && (w.limiter.rate <= 0 || w.limiter.burst <= 0) {
+		return fmt.Errorf("%w, got %v bytes/s and %v burst", ErrInvalidRateLimit, w.limiter.rate, w.limiter.burst)
+	}
+	if w.archiveQueue < 0 {
+		return fmt.Errorf("%w, got %d", ErrInvalidArchiveQueue, w.archiveQueue)
+	}
+	if w.archiveWorkers <= 0 || w.archiveAttempts <= 0 || w.archiveBackoff < 0 {
+		return fmt.Errorf("%w, got %d workers, %d attempts, and %v backoff", ErrInvalidArchive, w.archiveWorkers, w.archiveAttempts, w.archiveBackoff)
+	}
+	return nil
+}
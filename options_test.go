@@ -0,0 +1,54 @@
+package rlog
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestOptionValidation verifies that New rejects nonsensical option values.
+func TestOptionValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+		want error
+	}{
+		{"zero max file size", WithMaxFileSize(0), ErrInvalidMaxFileSize},
+		{"negative max file size", WithMaxFileSize(-1), ErrInvalidMaxFileSize},
+		{"zero max buf size", WithMaxBufSize(0), ErrInvalidMaxBufSize},
+		{"negative max buf size", WithMaxBufSize(-1), ErrInvalidMaxBufSize},
+		{"zero max buf age", WithMaxBufAge(0), ErrInvalidMaxBufAge},
+		{"short signing key", WithSigner(make([]byte, 10)), ErrInvalidSigner},
+		{"zero rate limit", WithRateLimit(0, 10), ErrInvalidRateLimit},
+		{"zero burst", WithRateLimit(10, 0), ErrInvalidRateLimit},
+		{"zero sampling", WithSampling(0), ErrInvalidSampling},
+		{"sampling above one", WithSampling(1.5), ErrInvalidSampling},
+		{"negative min rotation interval", WithMinRotationInterval(-time.Second), ErrInvalidMinRotation},
+		{"compression level too high", WithCompressionLevel(10), ErrInvalidCompression},
+		{"zero retry attempts", WithRetryPolicy(RetryPolicy{}), ErrInvalidRetry},
+		{"negative sync interval", WithSyncInterval(-time.Second), ErrInvalidSyncInterval},
+		{"negative flush deadline", WithFlushDeadline(-time.Second), ErrInvalidFlushDeadline},
+		{"unknown rotation strategy", WithRotationStrategy(-1), ErrInvalidRotation},
+		{"negative snapshot limits", WithSnapshotLimits(-1, 0), ErrInvalidSnapshot},
+		{"negative pause limit", WithPauseLimit(-1), ErrInvalidPauseLimit},
+		{"negative max lines", WithMaxLines(-1), ErrInvalidMaxLines},
+		{"negative max open files", WithMaxOpenFiles(-1), ErrInvalidMaxOpenFiles},
+		{"negative revalidate interval", WithRevalidateInterval(-time.Second), ErrInvalidRevalidate},
+		{"negative archive queue", WithArchiveQueue(-1), ErrInvalidArchiveQueue},
+		{"nil filesystem", WithFS(nil), ErrInvalidFS},
+		{"empty time layout", WithRotationTimeLayout(""), ErrInvalidTimeLayout},
+		{"time layout with separator", WithRotationTimeLayout("2006/01/02"), ErrInvalidTimeLayout},
+		{"naming with separator", WithNaming(badNaming{}), ErrInvalidNaming},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := New(t.TempDir(), tt.opt)
+			if !errors.Is(err, tt.want) {
+				if w != nil {
+					w.Close()
+				}
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+module github.com/Data-Corruption/rlog/otelrlog
+
+go 1.22.4
+
+require github.com/Data-Corruption/rlog v0.0.0
+
+require go.opentelemetry.io/otel/trace v1.28.0
+
+require go.opentelemetry.io/otel v1.28.0 // indirect
+
+replace github.com/Data-Corruption/rlog => ../
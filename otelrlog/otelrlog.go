@@ -0,0 +1,59 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+// Package otelrlog correlates logger entries with OpenTelemetry traces. When a
+// context-based logging function, such as logger.Info(ctx, ...), is passed a
+// context carrying a span, the entry includes the span's trace and span IDs,
+// in both text and JSON formats. It lives in its own module to keep rlog
+// itself free of dependencies.
+//
+// Usage:
+//
+//	l, err := logger.New("logs", "info", otelrlog.WithTrace())
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer l.Close()
+//	ctx = logger.IntoContext(ctx, l)
+//	logger.Info(ctx, "charged card") // ... charged card trace_id=4bf9... span_id=00f0...
+package otelrlog
+
+import (
+	"context"
+
+	"github.com/Data-Corruption/rlog/logger"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTrace returns a logger option that adds the active span's IDs to
+// entries under the "trace_id" and "span_id" keys.
+func WithTrace() logger.Option {
+	return func(l *logger.Logger) {
+		logger.WithExtractor(TraceID("trace_id"))(l)
+		logger.WithExtractor(SpanID("span_id"))(l)
+	}
+}
+
+// TraceID returns an extractor of the active span's trace ID, under key, for
+// use with logger.WithExtractor where other key names are needed.
+func TraceID(key string) logger.Extractor {
+	return func(ctx context.Context) (string, any, bool) {
+		sc := trace.SpanContextFromContext(ctx)
+		if !sc.HasTraceID() {
+			return "", nil, false
+		}
+		return key, sc.TraceID().String(), true
+	}
+}
+
+// SpanID returns an extractor of the active span's ID, under key.
+func SpanID(key string) logger.Extractor {
+	return func(ctx context.Context) (string, any, bool) {
+		sc := trace.SpanContextFromContext(ctx)
+		if !sc.HasSpanID() {
+			return "", nil, false
+		}
+		return key, sc.SpanID().String(), true
+	}
+}
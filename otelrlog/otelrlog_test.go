@@ -0,0 +1,57 @@
+package otelrlog
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Data-Corruption/rlog/logger"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestWithTrace verifies that entries logged through a context carrying a
+// span include its IDs, and that others don't.
+func TestWithTrace(t *testing.T) {
+	dir := t.TempDir()
+	l, err := logger.New(dir, "info", logger.WithFormat(logger.JSON), WithTrace())
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+	ctx := logger.IntoContext(context.Background(), l)
+	logger.Info(ctx, "untraced")
+	logger.Info(trace.ContextWithSpanContext(ctx, sc), "traced")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %d:\n%s", len(lines), data)
+	}
+	want := []map[string]any{
+		{"msg": "untraced"},
+		{"msg": "traced", "trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7"},
+	}
+	for i, line := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("entry %d is not valid JSON: %v", i, err)
+		}
+		for _, key := range []string{"msg", "trace_id", "span_id"} {
+			if entry[key] != want[i][key] {
+				t.Errorf("entry %d: expected %s %v, got %v", i, key, want[i][key], entry[key])
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+// WithOwner sets the owner of the log directory, when created by WithMkdirAll,
+// and of every active log file the Writer creates, for daemons that start as
+// root and drop privileges: the unprivileged user can then keep writing and
+// rotating. Rotated files keep the owner of the active file they came from.
+// Either ID may be -1 to leave it unchanged.
+//
+// Ownership is only set for files on the operating system's filesystem. On
+// Windows, WithOwner has no effect.
+func WithOwner(uid, gid int) Option {
+	return func(w *Writer) {
+		w.owner = &[2]int{uid, gid}
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package rlog
+
+import "os"
+
+// chownFile sets the owner of f.
+func chownFile(f *os.File, uid, gid int) error {
+	return f.Chown(uid, gid)
+}
+
+// chownPath sets the owner of the file at path.
+func chownPath(path string, uid, gid int) error {
+	return os.Chown(path, uid, gid)
+}
@@ -0,0 +1,42 @@
+//go:build unix
+
+package rlog
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// TestOwner verifies that WithOwner sets the owner of the created directory
+// and active log files.
+func TestOwner(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("changing owners requires root")
+	}
+	const uid, gid = 65534, 65534
+	dirPath := filepath.Join(t.TempDir(), "logs")
+	w, err := New(dirPath, WithMkdirAll(), WithOwner(uid, gid), WithMaxFileSize(4))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		w.WriteString("line\n") // the second flush rotates, creating a new file
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	for _, path := range []string{dirPath, filepath.Join(dirPath, "latest.log")} {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("failed to stat %s: %v", path, err)
+		}
+		if st := fi.Sys().(*syscall.Stat_t); st.Uid != uid || st.Gid != gid {
+			t.Errorf("%s: expected owner %d:%d, got %d:%d", path, uid, gid, st.Uid, st.Gid)
+		}
+	}
+}
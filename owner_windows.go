@@ -0,0 +1,15 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build windows
+
+package rlog
+
+import "os"
+
+// chownFile is a no-op on Windows, which has no numeric owners.
+func chownFile(f *os.File, uid, gid int) error { return nil }
+
+// chownPath is a no-op on Windows, which has no numeric owners.
+func chownPath(path string, uid, gid int) error { return nil }
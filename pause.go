@@ -0,0 +1,76 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import "fmt"
+
+// DefaultPauseLimit is the default number of bytes buffered while paused.
+const DefaultPauseLimit = 64 * 1024 * 1024 // 64 MB
+
+// WithPauseLimit sets how many bytes the Writer buffers in memory while it's
+// paused. Writes beyond the limit are dropped and counted in Stats. The
+// default is DefaultPauseLimit.
+func WithPauseLimit(n int) Option {
+	return func(w *Writer) {
+		w.pauseLimit = n
+	}
+}
+
+// Pause flushes and syncs the Writer, then stops it from writing, rotating, or
+// applying retention until Resume is called, so that backup tooling can copy
+// the log directory in a consistent state. Writes made meanwhile are buffered
+// in memory up to the pause limit (see WithPauseLimit). Archiving of files
+// rotated before the pause may still be running. Calling Pause on a paused
+// Writer does nothing; Close flushes the buffered writes as usual.
+func (w *Writer) Pause() error {
+	if w.mu != nil {
+		w.ioMu.Lock()
+		defer w.ioMu.Unlock()
+		w.mu.Lock()
+		defer w.mu.Unlock()
+	}
+	if w.err != nil {
+		return w.err
+	}
+	if w.paused {
+		return nil
+	}
+	if err := w.flushLocked(false); err != nil {
+		return err
+	}
+	if w.syncDirty && w.file != nil {
+		if err := w.syncFile(); err != nil {
+			return fmt.Errorf("failed to sync log file: %w", diskFull(err))
+		}
+		w.syncDirty = false
+	}
+	w.paused = true
+	return nil
+}
+
+// Resume undoes Pause and flushes the writes buffered while paused.
+func (w *Writer) Resume() error {
+	if w.mu != nil {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+	}
+	w.paused = false
+	if w.err != nil {
+		return w.err
+	}
+	return w.flush()
+}
+
+// pauseFull reports whether a write of n bytes exceeds the pause limit,
+// counting it as dropped if so.
+func (w *Writer) pauseFull(n int) bool {
+	if !w.paused || len(w.buf)+n <= w.pauseLimit {
+		return false
+	}
+	w.stats.overflowWrites.Add(1)
+	w.stats.overflowBytes.Add(uint64(n))
+	w.emit(Event{Kind: EventDrop, Err: errPauseDropped, Bytes: n})
+	return true
+}
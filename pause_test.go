@@ -0,0 +1,34 @@
+package rlog
+
+import "testing"
+
+// TestPause verifies that nothing reaches the log file while paused, that the
+// pause limit drops excess writes, and that Resume flushes the rest.
+func TestPause(t *testing.T) {
+	w, m, err := NewMemory(WithMaxBufSize(4), WithPauseLimit(10))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	w.WriteString("before\n")
+	if err := w.Pause(); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	w.WriteString("during\n")
+	w.WriteString("dropped\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if got, _ := m.ReadFile("latest.log"); string(got) != "before\n" {
+		t.Errorf("expected only %q while paused, got %q", "before\n", got)
+	}
+	if s := w.Stats(); s.OverflowWrites != 1 || s.OverflowBytes != 8 {
+		t.Errorf("expected 1 write of 8 bytes over the limit, got %+v", s)
+	}
+	if err := w.Resume(); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if got, _ := m.ReadFile("latest.log"); string(got) != "before\nduring\n" {
+		t.Errorf("expected %q after Resume, got %q", "before\nduring\n", got)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import "path/filepath"
+
+// RetentionPlan describes what the retention settings would do to a log
+// directory, without doing it.
+type RetentionPlan struct {
+	// Delete lists the paths of the rotated files that WithMaxRotations and
+	// WithMaxAge would delete, oldest first.
+	Delete []string
+	// Bundle maps the path of each day bundle that WithBundling would create
+	// or extend to the paths of the rotated files it would take in.
+	Bundle map[string][]string
+}
+
+// RetentionPlan reports which rotated files the Writer's retention settings
+// would delete or bundle if they were applied now. Nothing is changed, so
+// operators can check settings before relying on them; see also PlanRetention.
+func (w *Writer) RetentionPlan() (RetentionPlan, error) {
+	if w.ioMu != nil {
+		w.ioMu.Lock()
+		defer w.ioMu.Unlock()
+	}
+	return w.retentionPlan()
+}
+
+// PlanRetention is like RetentionPlan, but for the retention settings in opts
+// applied to dirPath, without creating a Writer. It lets a configuration
+// change be validated against a live log directory before it's deployed.
+func PlanRetention(dirPath string, opts ...Option) (RetentionPlan, error) {
+	w := newDefaultWriter(dirPath)
+	for _, opt := range opts {
+		opt(w)
+	}
+	if err := w.validate(); err != nil {
+		return RetentionPlan{}, err
+	}
+	return w.retentionPlan()
+}
+
+// retentionPlan computes the plan the way rotate applies it: deletions first,
+// then bundling of the remaining files.
+func (w *Writer) retentionPlan() (RetentionPlan, error) {
+	var plan RetentionPlan
+	expired, err := w.expiredRotations()
+	if err != nil {
+		return plan, err
+	}
+	plan.Delete = expired
+	skip := w.archiving()
+	for _, path := range expired {
+		skip[filepath.Base(path)] = true
+	}
+	days, err := w.bundleDays(skip)
+	if err != nil {
+		return plan, err
+	}
+	for day, names := range days {
+		if plan.Bundle == nil {
+			plan.Bundle = make(map[string][]string)
+		}
+		paths := make([]string, len(names))
+		for i, name := range names {
+			paths[i] = filepath.Join(w.dirPath, name)
+		}
+		plan.Bundle[filepath.Join(w.dirPath, day+BundleExt)] = paths
+	}
+	return plan, nil
+}
@@ -0,0 +1,51 @@
+package rlog
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestRetentionPlan verifies that the plan lists the files retention would
+// delete and bundle, deletions first, without touching any of them.
+func TestRetentionPlan(t *testing.T) {
+	clock := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	w, m, err := NewMemory(WithMaxRotations(10))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	for _, name := range []string{"20240501-100000.000000.log", "20240501-110000.000000.log", "20240501-120000.000000.log"} {
+		if err := writeFile(m, name, []byte("x\n"), 0o644); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+		clock = clock.Add(time.Hour)
+	}
+	clock = clock.Add(48 * time.Hour)
+
+	if plan, err := w.RetentionPlan(); err != nil || plan.Delete != nil || plan.Bundle != nil {
+		t.Errorf("expected an empty plan within the limits, got %+v (%v)", plan, err)
+	}
+	plan, err := PlanRetention(".", WithFS(m), WithMaxRotations(2), WithBundling(24*time.Hour), WithUTC())
+	if err != nil {
+		t.Fatalf("PlanRetention failed: %v", err)
+	}
+	want := RetentionPlan{
+		Delete: []string{"20240501-100000.000000.log"},
+		Bundle: map[string][]string{
+			"2024-05-01" + BundleExt: {"20240501-110000.000000.log", "20240501-120000.000000.log"},
+		},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("unexpected plan:\ngot  %+v\nwant %+v", plan, want)
+	}
+	if files := m.Files(); len(files) != 4 {
+		t.Errorf("expected planning to leave 4 files, got %v", keys(files))
+	}
+	if _, err := PlanRetention(".", WithFS(m), WithMaxRotations(-1)); err == nil {
+		t.Error("expected invalid options to be rejected")
+	}
+}
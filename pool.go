@@ -0,0 +1,48 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import "sync"
+
+// bufPool recycles flush buffers between flushes and across Writers, e.g. the
+// many Writers of a Manager.
+var bufPool sync.Pool // of *[]byte
+
+// getBuf returns an empty buffer with room for at least maxBufSize bytes.
+func (w *Writer) getBuf() []byte {
+	if bp, ok := bufPool.Get().(*[]byte); ok {
+		if b := *bp; cap(b) >= w.maxBufSize && cap(b) <= w.maxKeptBuf() {
+			return b[:0]
+		}
+	}
+	return make([]byte, 0, w.maxBufSize)
+}
+
+// putBuf recycles b unless it grew well past maxBufSize, e.g. to hold one
+// huge write or a burst that outpaced the disk. Such buffers are left to the
+// garbage collector so that a spike doesn't pin its peak memory forever.
+func (w *Writer) putBuf(b []byte) {
+	if cap(b) < w.maxBufSize || cap(b) > w.maxKeptBuf() {
+		return
+	}
+	b = b[:0]
+	bufPool.Put(&b)
+}
+
+// maxKeptBuf is the largest buffer capacity worth recycling.
+func (w *Writer) maxKeptBuf() int {
+	return 2 * w.maxBufSize
+}
+
+// adoptBuf makes p, handed over by WriteOwned, the buffer in place of the
+// empty one, which is kept as the spare or recycled.
+func (w *Writer) adoptBuf(p []byte) {
+	if w.spare == nil {
+		w.spare = w.buf[:0]
+	} else {
+		w.putBuf(w.buf)
+	}
+	w.buf = p
+}
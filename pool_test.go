@@ -0,0 +1,24 @@
+package rlog
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestBufferShrink verifies that buffers grown by a spike aren't kept after
+// the flush that drains them.
+func TestBufferShrink(t *testing.T) {
+	w, _, err := NewMemory(WithMaxBufSize(64))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	w.WriteString(strings.Repeat("x", 1<<20) + "\n") // flushed right away
+	w.WriteString("small\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if c := max(cap(w.buf), cap(w.spare)); c > w.maxKeptBuf() {
+		t.Errorf("expected buffers of at most %d bytes after the spike, got %d", w.maxKeptBuf(), c)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package rlog
+
+import (
+	"os"
+	"syscall"
+)
+
+// fallocKeepSize is FALLOC_FL_KEEP_SIZE, which allocates blocks without
+// changing the file's size.
+const fallocKeepSize = 0x1
+
+// preallocate reserves size bytes of disk space for f. Errors are ignored as
+// preallocation is only an optimization; filesystems without fallocate support
+// report EOPNOTSUPP and simply grow the file on demand.
+func preallocate(f *os.File, size int64) {
+	for {
+		err := syscall.Fallocate(int(f.Fd()), fallocKeepSize, 0, size)
+		if err != syscall.EINTR {
+			return
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package rlog
+
+import "os"
+
+// preallocate is a no-op on platforms without fallocate.
+func preallocate(f *os.File, size int64) {}
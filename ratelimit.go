@@ -0,0 +1,97 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats holds counters describing a Writer's activity. Counters only ever
+// increase over the Writer's lifetime.
+type Stats struct {
+	DroppedWrites  uint64 // writes discarded by the rate limiter
+	DroppedBytes   uint64 // bytes discarded by the rate limiter
+	SampledWrites  uint64 // writes discarded by sampling
+	SlowFlushes    uint64 // flushes that exceeded the flush deadline
+	OverflowWrites uint64 // writes discarded because the pause limit was reached
+	OverflowBytes  uint64 // bytes discarded because the pause limit was reached
+
+	// Failures the Writer carried on past or returned; see LastError.
+	FlushFailures   uint64 // failed flushes, syncs, and rotations, including those absorbed by the fallback
+	ArchiveFailures uint64 // rotated files left unarchived, after all attempts or because the queue was full
+	SinkFailures    uint64 // failed sink writes
+	VerifyFailures  uint64 // corrupt files found by WithStartupVerification, or failures to list them
+}
+
+// stats holds the live counters behind Stats. They're atomic so Stats can be
+// read without blocking writers.
+type stats struct {
+	droppedWrites  atomic.Uint64
+	droppedBytes   atomic.Uint64
+	sampledWrites  atomic.Uint64
+	slowFlushes    atomic.Uint64
+	overflowWrites atomic.Uint64
+	overflowBytes  atomic.Uint64
+
+	flushFailures   atomic.Uint64
+	archiveFailures atomic.Uint64
+	sinkFailures    atomic.Uint64
+	verifyFailures  atomic.Uint64
+	lastErr         atomic.Pointer[failure]
+}
+
+// Stats returns a snapshot of w's counters. It's safe to call concurrently
+// with other methods, even with WithNoSync.
+func (w *Writer) Stats() Stats {
+	return Stats{
+		DroppedWrites:  w.stats.droppedWrites.Load(),
+		DroppedBytes:   w.stats.droppedBytes.Load(),
+		SampledWrites:  w.stats.sampledWrites.Load(),
+		SlowFlushes:    w.stats.slowFlushes.Load(),
+		OverflowWrites: w.stats.overflowWrites.Load(),
+		OverflowBytes:  w.stats.overflowBytes.Load(),
+
+		FlushFailures:   w.stats.flushFailures.Load(),
+		ArchiveFailures: w.stats.archiveFailures.Load(),
+		SinkFailures:    w.stats.sinkFailures.Load(),
+		VerifyFailures:  w.stats.verifyFailures.Load(),
+	}
+}
+
+// WithRateLimit caps the rate at which data is accepted to bytesPerSec, with
+// bursts of up to burst bytes. Writes beyond the limit are dropped whole and
+// counted in Stats rather than blocking the caller; Write still reports success
+// so that a runaway producer isn't disrupted, only silenced. A single write
+// larger than burst is always dropped.
+func WithRateLimit(bytesPerSec float64, burst int) Option {
+	return func(w *Writer) {
+		w.limiter = &limiter{rate: bytesPerSec, burst: float64(burst), tokens: float64(burst)}
+	}
+}
+
+// limiter is a token bucket measured in bytes.
+type limiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// allow reports whether n bytes may be written at t, consuming them if so.
+func (l *limiter) allow(t time.Time, n int) bool {
+	if !l.last.IsZero() {
+		l.tokens += t.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = t
+	if float64(n) > l.tokens {
+		return false
+	}
+	l.tokens -= float64(n)
+	return true
+}
@@ -0,0 +1,44 @@
+package rlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRateLimit verifies that writes beyond the rate are dropped and counted,
+// and that the bucket refills over time.
+func TestRateLimit(t *testing.T) {
+	clock := time.Now()
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	dirPath := t.TempDir()
+	w, err := New(dirPath, WithRateLimit(10, 20))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for _, s := range []string{"0123456789", "0123456789", "dropped\n"} {
+		if _, err := w.WriteString(s); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	clock = clock.Add(time.Second)
+	if _, err := w.WriteString("refilled\n"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dirPath, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if want := "01234567890123456789refilled\n"; string(data) != want {
+		t.Errorf("content mismatch: got %q, want %q", data, want)
+	}
+	if st := w.Stats(); st.DroppedWrites != 1 || st.DroppedBytes != 8 {
+		t.Errorf("stats mismatch: got %+v, want 1 write and 8 bytes dropped", st)
+	}
+}
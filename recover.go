@@ -0,0 +1,76 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// RecoverResult describes the outcome of Recover.
+type RecoverResult struct {
+	Truncated int64  // number of bytes removed from the end of the file
+	Tail      []byte // the removed bytes, for the caller to report or replay
+}
+
+// Recover scans "latest.log" in dirPath for a torn trailing record, i.e. a
+// final line without its terminating newline as left behind by a crash in the
+// middle of a write, and truncates the file back to the last complete record.
+// Downstream consumers replaying the file then never see a half-written line.
+//
+// Recover must run before a Writer is opened on dirPath. A missing, empty, or
+// cleanly terminated file is left untouched and yields a zero RecoverResult.
+func Recover(dirPath string) (RecoverResult, error) {
+	var res RecoverResult
+	f, err := os.OpenFile(filepath.Join(dirPath, "latest.log"), os.O_RDWR, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return res, nil
+		}
+		return res, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return res, err
+	}
+	// Read backwards in chunks until the last newline is found.
+	const chunkSize = 64 * 1024
+	end := fi.Size()
+	for off := end; off > 0; {
+		n := min(off, chunkSize)
+		off -= n
+		chunk := make([]byte, n)
+		if _, err := f.ReadAt(chunk, off); err != nil && err != io.EOF {
+			return res, err
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			return truncateTail(f, off+int64(i)+1, end)
+		}
+	}
+	return truncateTail(f, 0, end) // no complete record at all
+}
+
+// truncateTail removes the bytes of f between keep and end, returning them.
+func truncateTail(f *os.File, keep, end int64) (RecoverResult, error) {
+	var res RecoverResult
+	if keep == end {
+		return res, nil
+	}
+	res.Tail = make([]byte, end-keep)
+	if _, err := f.ReadAt(res.Tail, keep); err != nil && err != io.EOF {
+		return RecoverResult{}, err
+	}
+	if err := f.Truncate(keep); err != nil {
+		return RecoverResult{}, err
+	}
+	if err := f.Sync(); err != nil {
+		return RecoverResult{}, err
+	}
+	res.Truncated = end - keep
+	return res, nil
+}
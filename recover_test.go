@@ -0,0 +1,41 @@
+package rlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRecover verifies that a torn trailing record is truncated and complete files are untouched.
+func TestRecover(t *testing.T) {
+	tempDir := t.TempDir()
+	logPath := filepath.Join(tempDir, "latest.log")
+
+	if res, err := Recover(tempDir); err != nil || res.Truncated != 0 {
+		t.Fatalf("Recover on missing file: got (%+v, %v), want zero result", res, err)
+	}
+
+	complete := "first record\nsecond record\n"
+	if err := os.WriteFile(logPath, []byte(complete+"torn rec"), 0o644); err != nil {
+		t.Fatalf("failed to write log file: %v", err)
+	}
+	res, err := Recover(tempDir)
+	if err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+	if res.Truncated != int64(len("torn rec")) || string(res.Tail) != "torn rec" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if string(data) != complete {
+		t.Errorf("log content mismatch: got %q, want %q", string(data), complete)
+	}
+
+	// A second pass finds nothing to do.
+	if res, err := Recover(tempDir); err != nil || res.Truncated != 0 {
+		t.Errorf("Recover on clean file: got (%+v, %v), want zero result", res, err)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// RotationStrategy selects how rotation moves the active file aside.
+type RotationStrategy int
+
+const (
+	// RenameRotation renames the active file, falling back to copy and
+	// truncate if renaming keeps failing. It's the default.
+	RenameRotation RotationStrategy = iota
+
+	// VerifiedRenameRotation is RenameRotation for network filesystems such
+	// as NFS. A rename reported as failed is checked against the directory,
+	// since a retransmitted request can fail after the first one succeeded,
+	// and a rename reported as successful is confirmed the same way, since
+	// attribute caches can lag. Failed renames are retried with backoff.
+	VerifiedRenameRotation
+
+	// CopyTruncateRotation copies the active file to its rotated name and
+	// truncates it in place, never renaming it. Handles held by other
+	// processes or clients keep pointing at the active file, at the risk of
+	// losing data written by others between the copy and the truncate.
+	CopyTruncateRotation
+)
+
+// WithRotationStrategy sets how rotation moves the active file aside,
+// RenameRotation by default.
+func WithRotationStrategy(s RotationStrategy) Option {
+	return func(w *Writer) {
+		w.rotation = s
+	}
+}
+
+// verifiedRenameAttempts is the number of times VerifiedRenameRotation tries
+// renaming before falling back to copy and truncate.
+const verifiedRenameAttempts = 5
+
+// renameBackoff is the delay before the second rename attempt. It doubles
+// after each further failure.
+const renameBackoff = 10 * time.Millisecond
+
+// rename is os.Rename, overridable by tests to simulate sharing violations.
+var rename = os.Rename
+
+// renameLog moves the log file at oldPath to newPath. Failed renames are retried
+// renameAttempts times with exponential backoff, since on Windows they usually
+// stem from short lived handles held by antivirus scanners or tailers. If every
+// attempt fails, the contents are copied to newPath and oldPath is truncated
+// instead, which works as long as the other handle permits writes.
+func renameLog(fsys FS, oldPath, newPath string) error {
+	var err error
+	delay := renameBackoff
+	for i := 0; i < renameAttempts; i++ {
+		if err = fsys.Rename(oldPath, newPath); err == nil {
+			return nil
+		}
+		if i < renameAttempts-1 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	if cerr := copyTruncate(fsys, oldPath, newPath); cerr != nil {
+		return fmt.Errorf("%w (copy fallback: %v)", err, cerr)
+	}
+	return nil
+}
+
+// moveLog moves the log file at oldPath to newPath using strategy s.
+func moveLog(fsys FS, s RotationStrategy, oldPath, newPath string) error {
+	switch s {
+	case VerifiedRenameRotation:
+		return renameVerified(fsys, oldPath, newPath)
+	case CopyTruncateRotation:
+		return copyTruncate(fsys, oldPath, newPath)
+	default:
+		return renameLog(fsys, oldPath, newPath)
+	}
+}
+
+// renameVerified moves the log file at oldPath to newPath, judging each
+// rename by whether the file actually moved rather than by the error reported.
+// If it never moves, the contents are copied and oldPath is truncated instead.
+func renameVerified(fsys FS, oldPath, newPath string) error {
+	ofi, err := fsys.Stat(oldPath)
+	if err != nil {
+		return err
+	}
+	delay := renameBackoff
+	for i := 0; i < verifiedRenameAttempts; i++ {
+		err = fsys.Rename(oldPath, newPath)
+		if moved(fsys, ofi, oldPath, newPath) {
+			return nil
+		}
+		if err == nil {
+			err = fmt.Errorf("rename %s %s: reported success but the file did not move", oldPath, newPath)
+		}
+		if i < verifiedRenameAttempts-1 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	if cerr := copyTruncate(fsys, oldPath, newPath); cerr != nil {
+		return fmt.Errorf("%w (copy fallback: %v)", err, cerr)
+	}
+	return nil
+}
+
+// moved reports whether the file described by ofi is now at newPath and gone
+// from oldPath.
+func moved(fsys FS, ofi os.FileInfo, oldPath, newPath string) bool {
+	nfi, err := fsys.Stat(newPath)
+	if err != nil || nfi.Size() != ofi.Size() || !sameFile(ofi, nfi) {
+		return false
+	}
+	_, err = fsys.Stat(oldPath)
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// copyTruncate copies the file at oldPath to the new file newPath, syncs it,
+// and truncates oldPath to zero length.
+func copyTruncate(fsys FS, oldPath, newPath string) error {
+	src, err := fsys.OpenFile(oldPath, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := fsys.OpenFile(newPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		fsys.Remove(newPath)
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		fsys.Remove(newPath)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		fsys.Remove(newPath)
+		return err
+	}
+	return src.Truncate(0)
+}
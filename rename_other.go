@@ -0,0 +1,11 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package rlog
+
+// renameAttempts is the number of times a rotation rename is tried before
+// falling back to copy and truncate. Renames fail transiently only on Windows.
+const renameAttempts = 1
@@ -0,0 +1,161 @@
+package rlog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRenameFallback verifies that rotation falls back to copy and truncate when renames fail.
+func TestRenameFallback(t *testing.T) {
+	attempts := 0
+	rename = func(oldPath, newPath string) error {
+		attempts++
+		return &os.LinkError{Op: "rename", Old: oldPath, New: newPath, Err: errors.New("sharing violation")}
+	}
+	defer func() { rename = os.Rename }()
+
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithMaxFileSize(10))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for _, msg := range []string{"abcdef", "ghijkl"} { // second flush rotates
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if attempts != renameAttempts {
+		t.Errorf("expected %d rename attempts, got %d", renameAttempts, attempts)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read latest.log: %v", err)
+	}
+	if string(data) != "ghijkl" {
+		t.Errorf("latest.log content mismatch: got %q, want %q", string(data), "ghijkl")
+	}
+	names, err := logFiles(OSFS{}, tempDir)
+	if err != nil || len(names) != 2 {
+		t.Fatalf("expected one rotated file and latest.log, got %v (err %v)", names, err)
+	}
+	rotated, err := os.ReadFile(filepath.Join(tempDir, names[0]))
+	if err != nil {
+		t.Fatalf("failed to read rotated file: %v", err)
+	}
+	if string(rotated) != "abcdef" {
+		t.Errorf("rotated file content mismatch: got %q, want %q", string(rotated), "abcdef")
+	}
+}
+
+// TestRotationCollision verifies that rotation never replaces an existing rotated file.
+func TestRotationCollision(t *testing.T) {
+	fixed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.Local)
+	now = func() time.Time { return fixed }
+	defer func() { now = time.Now }()
+
+	tempDir := t.TempDir()
+	leftover := filepath.Join(tempDir, fixed.Format("20060102-150405.000000")+".log")
+	if err := os.WriteFile(leftover, []byte("previous run"), 0o644); err != nil {
+		t.Fatalf("failed to create leftover file: %v", err)
+	}
+	w, err := New(tempDir, WithMaxFileSize(10))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for _, msg := range []string{"abcdef", "ghijkl", "mnopqr"} { // rotates twice
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	names, err := logFiles(OSFS{}, tempDir)
+	if err != nil {
+		t.Fatalf("failed to list log files: %v", err)
+	}
+	want := []string{"previous run", "abcdef", "ghijkl", "mnopqr"}
+	if len(names) != len(want) {
+		t.Fatalf("expected %d log files, got %v", len(want), names)
+	}
+	for i, name := range names {
+		data, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("failed to read %q: %v", name, err)
+		}
+		if string(data) != want[i] {
+			t.Errorf("%s content mismatch: got %q, want %q", name, string(data), want[i])
+		}
+	}
+}
+
+// TestRotationStrategies verifies that VerifiedRenameRotation accepts a rename
+// that succeeded despite an error, and that CopyTruncateRotation never renames.
+func TestRotationStrategies(t *testing.T) {
+	attempts := 0
+	rename = func(oldPath, newPath string) error {
+		attempts++
+		if err := os.Rename(oldPath, newPath); err != nil {
+			return err
+		}
+		// The reply to the first request was lost; the retransmission fails.
+		return &os.LinkError{Op: "rename", Old: oldPath, New: newPath, Err: os.ErrNotExist}
+	}
+	defer func() { rename = os.Rename }()
+
+	for _, tc := range []struct {
+		strategy RotationStrategy
+		attempts int
+	}{
+		{VerifiedRenameRotation, 1},
+		{CopyTruncateRotation, 0},
+	} {
+		attempts = 0
+		tempDir := t.TempDir()
+		w, err := New(tempDir, WithMaxFileSize(10), WithRotationStrategy(tc.strategy))
+		if err != nil {
+			t.Fatalf("failed to create Writer: %v", err)
+		}
+		before, _ := os.Stat(filepath.Join(tempDir, "latest.log"))
+		for _, msg := range []string{"abcdef", "ghijkl"} { // second flush rotates
+			w.WriteString(msg)
+			if err := w.Flush(); err != nil {
+				t.Fatalf("strategy %d: Flush failed: %v", tc.strategy, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if attempts != tc.attempts {
+			t.Errorf("strategy %d: expected %d rename attempts, got %d", tc.strategy, tc.attempts, attempts)
+		}
+		after, _ := os.Stat(filepath.Join(tempDir, "latest.log"))
+		if keep := tc.strategy == CopyTruncateRotation; os.SameFile(before, after) != keep {
+			t.Errorf("strategy %d: expected same active file to be %v", tc.strategy, keep)
+		}
+		names, err := logFiles(OSFS{}, tempDir)
+		if err != nil || len(names) != 2 {
+			t.Fatalf("strategy %d: expected one rotated file and latest.log, got %v (err %v)", tc.strategy, names, err)
+		}
+		if data, _ := os.ReadFile(filepath.Join(tempDir, names[0])); string(data) != "abcdef" {
+			t.Errorf("strategy %d: rotated file content mismatch: got %q", tc.strategy, data)
+		}
+		if data, _ := os.ReadFile(filepath.Join(tempDir, "latest.log")); string(data) != "ghijkl" {
+			t.Errorf("strategy %d: latest.log content mismatch: got %q", tc.strategy, data)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// checkActive detects changes made to the active file behind the Writer's
+// back, given fi from stat'ing the open file. If the file was deleted or
+// replaced, e.g. by logrotate or an operator, appending to the open descriptor
+// would write to an unlinked inode where logs silently vanish, so the file is
+// recreated. A truncated plain file needs no action since writes append at the
+// new end, but a truncated compressed file has lost part of its gzip stream, so
+// it's reopened to start a fresh member. checkActive reports whether the file
+// was reopened.
+func (w *Writer) checkActive(fi os.FileInfo) (bool, error) {
+	var reason string
+	pfi, err := w.fs.Stat(w.activePath())
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		reason = "was removed"
+	case err != nil:
+		return false, nil // can't tell; keep writing to the open file
+	case !sameFile(fi, pfi):
+		reason = "was replaced"
+	case w.compress && fi.Size() < w.activeSize:
+		reason = "was truncated"
+	default:
+		return false, nil
+	}
+	w.reportError(fmt.Errorf("%s %s externally, reopening", w.activePath(), reason))
+	if err := w.closeActive(); err != nil && reason == "was truncated" {
+		return false, fmt.Errorf("failed to close log file: %w", err)
+	}
+	if err := w.openActive(); err != nil {
+		return false, fmt.Errorf("failed to reopen log file: %w", err)
+	}
+	return true, nil
+}
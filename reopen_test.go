@@ -0,0 +1,43 @@
+package rlog
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestReopenRemovedFile verifies that a flush recreates latest.log after it's
+// deleted externally instead of writing to the unlinked file.
+func TestReopenRemovedFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("open files can't be removed on Windows")
+	}
+	dirPath := t.TempDir()
+	w, err := New(dirPath)
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	path := filepath.Join(dirPath, "latest.log")
+	for _, s := range []string{"before\n", "after\n"} {
+		if _, err := w.WriteString(s); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		if s == "before\n" {
+			if err := os.Remove(path); err != nil {
+				t.Fatalf("failed to remove log file: %v", err)
+			}
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected latest.log to be recreated: %v", err)
+	}
+	if string(data) != "after\n" {
+		t.Errorf("content mismatch: got %q, want %q", data, "after\n")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WithMaxRotations limits the number of rotated files kept in the directory.
+// After each rotation the oldest rotated files beyond n are deleted, along with
+// their signature sidecars. Zero, the default, keeps every file.
+func WithMaxRotations(n int) Option {
+	return func(w *Writer) {
+		w.maxRotations = n
+	}
+}
+
+// WithMaxAge deletes rotated files last modified more than d ago. Files are
+// checked after each rotation. Zero, the default, keeps files indefinitely.
+func WithMaxAge(d time.Duration) Option {
+	return func(w *Writer) {
+		w.maxAge = d
+	}
+}
+
+// applyRetention deletes rotated files exceeding maxRotations or maxAge.
+func (w *Writer) applyRetention() error {
+	expired, err := w.expiredRotations()
+	if err != nil {
+		return err
+	}
+	for _, path := range expired {
+		if err := removeRotated(w.fs, path); err != nil {
+			return err
+		}
+		w.emit(Event{Kind: EventRetention, Path: path})
+	}
+	return nil
+}
+
+// expiredRotations returns the paths of the rotated files exceeding
+// maxRotations or maxAge, oldest first. Files being archived are left out;
+// they still count towards maxRotations.
+func (w *Writer) expiredRotations() ([]string, error) {
+	if w.maxRotations <= 0 && w.maxAge <= 0 {
+		return nil, nil
+	}
+	names, err := w.logFiles()
+	if err != nil {
+		return nil, err
+	}
+	var rotated []string
+	for _, name := range names {
+		if !isActiveName(name) {
+			rotated = append(rotated, name)
+		}
+	}
+	excess := 0
+	if w.maxRotations > 0 {
+		excess = max(len(rotated)-w.maxRotations, 0)
+	}
+	busy := w.archiving()
+	var paths []string
+	for i, name := range rotated {
+		if busy[name] {
+			continue
+		}
+		path := filepath.Join(w.dirPath, name)
+		expired := false
+		if i >= excess && w.maxAge > 0 {
+			fi, err := w.fs.Stat(path)
+			if err != nil {
+				return nil, err
+			}
+			expired = now().Sub(fi.ModTime()) > w.maxAge
+		}
+		if i < excess || expired {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// removeRotated deletes the rotated file at path in fsys and its signature sidecar.
+func removeRotated(fsys FS, path string) error {
+	if err := fsys.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := fsys.Remove(path + SignatureExt); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Purge deletes rotated files and bundles last modified more than olderThan
+// ago, along with signature sidecars, and returns how many were removed. Zero
+// removes every one. The active file and files being archived are never
+// touched. Purge holds the Writer's lock unless WithNoSync is set, so it can't
+// race with rotation.
+func (w *Writer) Purge(olderThan time.Duration) (int, error) {
+	if w.ioMu != nil {
+		w.ioMu.Lock()
+		defer w.ioMu.Unlock()
+	}
+	entries, err := w.fs.ReadDir(w.dirPath)
+	if err != nil {
+		return 0, err
+	}
+	busy := w.archiving()
+	removed := 0
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || isActiveName(name) || busy[name] || !(isLogName(name) || strings.HasSuffix(name, BundleExt)) {
+			continue
+		}
+		path := filepath.Join(w.dirPath, name)
+		if olderThan > 0 {
+			fi, err := w.fs.Stat(path)
+			if err != nil {
+				return removed, err
+			}
+			if now().Sub(fi.ModTime()) <= olderThan {
+				continue
+			}
+		}
+		if err := removeRotated(w.fs, path); err != nil {
+			return removed, err
+		}
+		w.emit(Event{Kind: EventRetention, Path: path})
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,86 @@
+package rlog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestPurge verifies that Purge removes old rotated files and their
+// signatures but keeps recent ones and the active file.
+func TestPurge(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	w, m, err := NewMemory(WithMaxFileSize(4))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	for i := 0; i < 4; i++ {
+		fmt.Fprintf(w, "%d\n", i)
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		clock = clock.Add(time.Hour)
+	}
+	writeFile(m, "old.log"+SignatureExt, []byte("sig"), 0o644)
+	writeFile(m, "old.log", []byte("old\n"), 0o644)
+	m.files["old.log"].modTime = clock.Add(-10 * time.Hour)
+
+	n, err := w.Purge(150 * time.Minute)
+	if err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if n != 3 { // two old rotations and old.log
+		t.Errorf("expected 3 files purged, got %d", n)
+	}
+	files := m.Files()
+	if _, ok := files["old.log"+SignatureExt]; ok {
+		t.Errorf("expected signature sidecar to be purged")
+	}
+	if len(files) != 2 { // the recent rotation and latest.log
+		t.Errorf("expected 2 files left, got %v", keys(files))
+	}
+	if n, err := w.Purge(0); err != nil || n != 1 {
+		t.Errorf("Purge(0) returned %d, %v; want 1, nil", n, err)
+	}
+}
+
+// TestPurgeArchiving verifies that Purge skips files being archived.
+func TestPurgeArchiving(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	a := ArchiverFunc(func(ctx context.Context, path string) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+	w, m, err := NewMemory(WithMaxFileSize(4), WithArchiver(a))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	for _, msg := range []string{"ab\n", "cd\n"} { // rotates "ab\n", which the worker picks up
+		if _, err := w.WriteString(msg); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	<-started
+	if n, err := w.Purge(0); err != nil || n != 0 {
+		t.Errorf("Purge(0) returned %d, %v; want 0, nil", n, err)
+	}
+	if len(m.Files()) != 2 {
+		t.Errorf("expected the file being archived to stay, got %v", keys(m.Files()))
+	}
+	close(release)
+	waitArchived(t, w)
+	if n, err := w.Purge(0); err != nil || n != 1 {
+		t.Errorf("Purge(0) returned %d, %v; want 1, nil", n, err)
+	}
+}
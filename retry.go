@@ -0,0 +1,59 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"errors"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed writes and syncs of the active file are
+// retried before the error becomes sticky.
+type RetryPolicy struct {
+	Attempts  int              // total attempts per operation, at least 1
+	Backoff   time.Duration    // delay before the first retry, doubling after each
+	Retriable func(error) bool // reports whether an error is worth retrying; nil means IsTransient
+}
+
+// WithRetryPolicy retries writes and syncs of the active file that fail with
+// a retriable error, so a momentary EIO or network filesystem hiccup doesn't
+// fail the Writer. Retries happen on the flushing goroutine. A write that
+// partially succeeded is resumed where it stopped, so nothing is duplicated.
+// With stream compression only syncs are retried, as a failed gzip stream
+// can't be resumed. By default nothing is retried.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(w *Writer) {
+		w.retry = p
+	}
+}
+
+// IsTransient reports whether err is an I/O error that commonly clears up on
+// its own: EIO, EAGAIN, EINTR, EBUSY, ETIMEDOUT, or ESTALE.
+func IsTransient(err error) bool {
+	for _, errno := range []syscall.Errno{syscall.EIO, syscall.EAGAIN, syscall.EINTR, syscall.EBUSY, syscall.ETIMEDOUT, syscall.ESTALE} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryIO runs op under the Writer's retry policy.
+func (w *Writer) retryIO(op func() error) error {
+	retriable := w.retry.Retriable
+	if retriable == nil {
+		retriable = IsTransient
+	}
+	delay := w.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= w.retry.Attempts || !retriable(err) {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
@@ -0,0 +1,43 @@
+package rlog
+
+import (
+	"io/fs"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestRetryPolicy verifies that transient write and sync errors are retried
+// without duplicating data, and that they stick without a retry policy.
+func TestRetryPolicy(t *testing.T) {
+	fsys := &flakyFS{Memory: NewMemoryFS()}
+	w, err := New(".", WithFS(fsys), WithRetryPolicy(RetryPolicy{Attempts: 3, Backoff: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	fsys.writeFails, fsys.syncFails = 2, 2
+	w.WriteString("hello world\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got, _ := fsys.ReadFile("latest.log"); string(got) != "hello world\n" {
+		t.Errorf("expected %q, got %q", "hello world\n", got)
+	}
+
+	fsys = &flakyFS{Memory: NewMemoryFS()}
+	w, err = New(".", WithFS(fsys))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	fsys.syncFails = 1
+	w.WriteString("hello world\n")
+	if err := w.Flush(); err == nil {
+		t.Errorf("expected the sync error to stick without a retry policy")
+	}
+	if !IsTransient(syscall.EIO) || IsTransient(fs.ErrNotExist) {
+		t.Errorf("IsTransient misclassified errors")
+	}
+}
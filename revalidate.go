@@ -0,0 +1,70 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithRevalidateInterval makes the Writer trust the active file's size as
+// tracked from its own writes, stat'ing the file at most once per interval d
+// rather than on every flush. Each revalidation reconciles the tracked size and
+// line count with changes made by others, e.g. another process appending to a
+// shared file or an external truncation, and detects a removed or replaced
+// file as usual. Between revalidations, such changes go unnoticed, so rotation
+// may happen late by up to d worth of foreign writes.
+//
+// Zero, the default, revalidates on every flush.
+func WithRevalidateInterval(d time.Duration) Option {
+	return func(w *Writer) {
+		w.revalidate = d
+	}
+}
+
+// activeFileSize returns the size of the active file, revalidating it when the
+// interval has elapsed.
+func (w *Writer) activeFileSize() (int64, error) {
+	if w.revalidate > 0 && now().Sub(w.revalidatedAt) < w.revalidate {
+		return w.activeSize, nil
+	}
+	fi, err := w.file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat log file: %w", err)
+	}
+	if reopened, err := w.checkActive(fi); err != nil {
+		return 0, err
+	} else if reopened {
+		if fi, err = w.file.Stat(); err != nil {
+			return 0, fmt.Errorf("failed to stat log file: %w", err)
+		}
+	}
+	if fi.Size() != w.activeSize {
+		w.reconcile(fi.Size())
+	}
+	w.revalidatedAt = now()
+	return w.activeSize, nil
+}
+
+// reconcile adopts size as the active file's size after it was changed behind
+// the Writer's back, recounting its lines if they're limited.
+func (w *Writer) reconcile(size int64) {
+	w.activeSize = size
+	if w.maxLines > 0 {
+		if n, err := countLines(w.fs, w.activePath()); err == nil {
+			w.activeLines = n
+		}
+	}
+}
+
+// activeCounter writes to the active file, tracking its size. With stream
+// compression, the gzip stream writes through it.
+type activeCounter struct{ w *Writer }
+
+func (c activeCounter) Write(p []byte) (int, error) {
+	n, err := c.w.file.Write(p)
+	c.w.activeSize += int64(n)
+	return n, err
+}
@@ -0,0 +1,76 @@
+package rlog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRevalidate verifies that appends by another process are reconciled when
+// the active file is revalidated, and only then with a revalidation interval.
+func TestRevalidate(t *testing.T) {
+	clock := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "latest.log")
+	appendExternal := func(s string) {
+		t.Helper()
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			t.Fatalf("failed to open log file: %v", err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(s); err != nil {
+			t.Fatalf("external write failed: %v", err)
+		}
+	}
+	rotated := func() int {
+		t.Helper()
+		names, err := logFiles(OSFS{}, tempDir)
+		if err != nil {
+			t.Fatalf("failed to list log files: %v", err)
+		}
+		return len(names) - 1
+	}
+
+	t.Run("every flush", func(t *testing.T) {
+		w, err := New(tempDir, WithMaxLines(4), WithFlushOnNewline())
+		if err != nil {
+			t.Fatalf("failed to create Writer: %v", err)
+		}
+		defer w.Close()
+		w.WriteString("a\nb\n")
+		appendExternal("x\ny\n")
+		w.WriteString("c\n") // the external lines count toward the limit
+		if got := rotated(); got != 1 {
+			t.Errorf("expected 1 rotation, got %d", got)
+		}
+		if got := w.CurrentFileSize(); got != 2 {
+			t.Errorf("expected current file size 2, got %d", got)
+		}
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		w, err := New(tempDir, WithMaxFileSize(100), WithRevalidateInterval(time.Minute), WithFlushOnNewline())
+		if err != nil {
+			t.Fatalf("failed to create Writer: %v", err)
+		}
+		defer w.Close()
+		start := rotated()
+		w.WriteString("a\n")
+		appendExternal(strings.Repeat("x", 200) + "\n")
+		w.WriteString("b\n") // the cached size is still trusted
+		if got := rotated() - start; got != 0 {
+			t.Errorf("expected no rotation before revalidating, got %d", got)
+		}
+		clock = clock.Add(time.Minute)
+		w.WriteString("c\n")
+		if got := rotated() - start; got != 1 {
+			t.Errorf("expected 1 rotation after revalidating, got %d", got)
+		}
+	})
+}
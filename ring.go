@@ -0,0 +1,78 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"io"
+	"sync"
+)
+
+// Ring is an io.Writer that keeps only the most recent output in a fixed-size
+// in-memory buffer, without touching the disk until Dump is called. It's meant
+// as a crash-dump buffer, e.g. written to stderr or a file from a panic
+// handler. A Ring is safe for concurrent use.
+type Ring struct {
+	mu   sync.Mutex
+	buf  []byte
+	next int  // index the next byte is written at
+	full bool // whether buf has wrapped around at least once
+}
+
+// NewRing returns a Ring holding the last size bytes written to it. It panics
+// if size isn't positive.
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		panic("rlog: ring size must be positive")
+	}
+	return &Ring{buf: make([]byte, size)}
+}
+
+// Write appends p to the ring, overwriting the oldest data once it's full. It
+// always succeeds.
+func (r *Ring) Write(p []byte) (int, error) {
+	n := len(p)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(p) >= len(r.buf) {
+		// Only the tail of p survives.
+		copy(r.buf, p[len(p)-len(r.buf):])
+		r.next, r.full = 0, true
+		return n, nil
+	}
+	c := copy(r.buf[r.next:], p)
+	if c < len(p) {
+		copy(r.buf, p[c:])
+		r.full = true
+	}
+	r.next = (r.next + len(p)) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+	return n, nil
+}
+
+// Dump writes the ring's contents to w, oldest first. The ring is left as is,
+// so Dump can be called more than once.
+func (r *Ring) Dump(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	if r.full {
+		m, err := w.Write(r.buf[r.next:])
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+	m, err := w.Write(r.buf[:r.next])
+	return n + int64(m), err
+}
+
+// Reset discards the ring's contents.
+func (r *Ring) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.next, r.full = 0, false
+}
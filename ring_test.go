@@ -0,0 +1,36 @@
+package rlog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestRing verifies that a Ring keeps only the most recent bytes, in order.
+func TestRing(t *testing.T) {
+	r := NewRing(8)
+	for _, tc := range []struct{ write, want string }{
+		{"abc", "abc"},
+		{"defgh", "abcdefgh"},
+		{"ij", "cdefghij"},
+		{"klmnopqrstuvwxyz", "stuvwxyz"},
+		{"0123456", "z0123456"},
+	} {
+		if n, err := r.Write([]byte(tc.write)); n != len(tc.write) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v", tc.write, n, err)
+		}
+		var out bytes.Buffer
+		if n, err := r.Dump(&out); err != nil || n != int64(len(tc.want)) {
+			t.Fatalf("Dump = %d, %v", n, err)
+		}
+		if out.String() != tc.want {
+			t.Errorf("after writing %q, expected %q, got %q", tc.write, tc.want, out.String())
+		}
+	}
+	r.Reset()
+	var out strings.Builder
+	r.Dump(&out)
+	if out.Len() != 0 {
+		t.Errorf("expected an empty ring after Reset, got %q", out.String())
+	}
+}
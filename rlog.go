@@ -17,13 +17,12 @@
 // maximum buffer age. If you want that functionality, you should create a
 // separate goroutine that calls Flush() periodically.
 //
-// Note that by default Writer is not safe for concurrent use. Use the WithSync
-// option to enable internal synchronization.
+// Writer is safe for concurrent use unless created with WithNoSync.
 //
 // Usage:
 //
-//	// Create a new synchronized Writer with a maximum file size of 1 GB.
-//	w, err := rlog.New("logs", rlog.WithMaxFileSize(1024*1024), rlog.WithSync())
+//	// Create a new Writer with a maximum file size of 1 MB.
+//	w, err := rlog.New("logs", rlog.WithMaxFileSize(1024*1024))
 //	if err != nil {
 //		log.Fatalf("Failed to create log writer: %v", err)
 //	}
@@ -40,7 +39,7 @@
 //
 //	func main() {
 //		var err error
-//		logWriter, err = rlog.New("logs") // WithSync unnecessary as log.Logger serializes writes
+//		logWriter, err = rlog.New("logs", rlog.WithNoSync()) // log.Logger serializes writes
 //		if err != nil {
 //			log.Fatalf("Failed to create log writer: %v", err)
 //		}
@@ -52,10 +51,20 @@
 package rlog
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -63,8 +72,45 @@ const (
 	DefaultMaxFileSize = 256 * 1024 * 1024 // 256 MB
 	DefaultMaxBufSize  = 4096              // 4 KB
 	DefaultMaxBufAge   = 15 * time.Second  // 15 seconds
+	DefaultDirMode     = 0o755             // rwxr-xr-x
+
+	DefaultRotationTimeLayout = "20060102-150405.000000"
+)
+
+// Errors reported by the Writer. Failures wrap them together with the error
+// that caused them, so callers can branch on either with errors.Is.
+var (
+	// ErrClosed is returned by Write and Flush once the Writer has been closed.
+	ErrClosed = errors.New("writer closed")
+	// ErrRotateFailed wraps any failure to rotate the active file.
+	ErrRotateFailed = errors.New("rotation failed")
+	// ErrDiskFull wraps write and sync failures caused by a full disk or an
+	// exceeded quota.
+	ErrDiskFull = errors.New("disk full")
+	// ErrDropped wraps the reason a write was discarded rather than buffered,
+	// e.g. by the rate limit, the pause limit, or an abandoned WriteContext.
+	ErrDropped = errors.New("write dropped")
 )
 
+// Reasons given with ErrDropped, allocated once as drops happen in floods.
+var (
+	errRateDropped  = fmt.Errorf("%w: rate limit exceeded", ErrDropped)
+	errPauseDropped = fmt.Errorf("%w: pause limit exceeded", ErrDropped)
+)
+
+// diskFull wraps err with ErrDiskFull if it was caused by a full disk.
+func diskFull(err error) error {
+	for _, errno := range diskFullErrnos {
+		if errors.Is(err, errno) {
+			return fmt.Errorf("%w: %w", ErrDiskFull, err)
+		}
+	}
+	return err
+}
+
+// now returns the current time. It's a variable so tests can control the clock.
+var now = time.Now
+
 type noCopy struct{} // see https://github.com/golang/go/issues/8005#issuecomment-190753527
 
 func (*noCopy) Lock()   {}
@@ -77,23 +123,142 @@ type Writer struct {
 	noCopy noCopy
 
 	mu        *sync.Mutex // pointer to allow disabling synchronization using nil
+	ioMu      *sync.Mutex // serializes file I/O; non-nil exactly when mu is
+	noSync    bool        // leave mu and ioMu nil
+	fs        FS
 	err       error
 	buf       []byte
-	file      *os.File
+	spare     []byte       // the previous buffer, reused once its flush completes
+	inflight  int          // bytes taken from buf by a flush that hasn't completed
+	batch     uint64       // number of buffers taken for flushing so far
+	flushed   uint64       // buffers before this number have been flushed
+	pending   atomic.Int64 // len(buf) plus inflight, for readers that can't take mu
+	fileSize  atomic.Int64 // size of the active file as of the last flush
+	flushedAt atomic.Int64 // lastFlush in Unix nanoseconds, for readers that can't take mu
+	file      File
+	gz        *gzip.Writer // non-nil when the active file is compressed
+	gzDirty   bool         // whether gz has been written to since it was opened
 	dirPath   string
+	dirMode   os.FileMode
+	mkdirAll  bool
 	lastFlush time.Time
 
-	maxFileSize int64
-	maxBufSize  int
-	maxBufAge   time.Duration
+	maxFileSize    int64
+	maxBufSize     int
+	maxBufAge      time.Duration
+	flushOnNewline bool
+	chaos          *rand.Rand // non-nil enables randomized flush/rotation decisions
+	compress       bool
+	compressLevel  int
+	activeSize     int64 // size of the active file, tracked from writes
+	revalidate     time.Duration
+	revalidatedAt  time.Time
+	preallocate    bool
+	directIO       bool
+	minFreeSpace   uint64 // bytes HealthCheck requires to be available
+	timeLayout     string // layout of rotated file names
+	nameTag        string // appended to the timestamp of rotated file names
+	naming         NamingStrategy
+	minRotation    time.Duration
+	rotation       RotationStrategy
+	snapshotFiles  int
+	snapshotBytes  int64
+	paused         bool
+	pauseLimit     int
+	owner          *[2]int // uid and gid set by WithOwner
+	maxLines       int
+	activeLines    int    // complete lines in the active file when maxLines is set
+	stampLayout    string // layout of line timestamps, empty when off
+	stampBuf       []byte
+	stampMidLine   bool // whether the last stamped write ended mid-line
+	verifyOnStart  bool
+	lastRotation   time.Time
+	utc            bool
+
+	hashChain    bool
+	chainPrev    [sha256.Size]byte
+	chainPartial []byte // incomplete trailing line awaiting its newline
+
+	signer ed25519.PrivateKey
+
+	maxRotations int
+	maxAge       time.Duration
+	bundleAfter  time.Duration
+
+	archiver        Archiver
+	archiveWorkers  int
+	archiveAttempts int
+	archiveBackoff  time.Duration
+	archiveQueue    int
+	archives        *archivePool
+
+	sinks []Sink
+	retry RetryPolicy
+
+	fallback    io.Writer
+	fallbackErr error     // why the log file failed, nil while it's healthy
+	fallbackAt  time.Time // when the log file last failed or was probed
+
+	syncInterval  time.Duration
+	flushDeadline time.Duration
+	onError       func(error)
+	tracer        Tracer
+	syncDirty     bool // written since the last sync, guarded by ioMu
+	syncStop      chan struct{}
+	syncDone      chan struct{}
+	syncOnce      sync.Once
+
+	maxOpenFiles int
+	budget       *fdBudget // shared by a Manager's streams, nil without a limit
+	dormant      bool      // active file closed by the budget, guarded by ioMu
+
+	filters    []func([]byte) []byte
+	limiter    *limiter // non-nil when WithRateLimit is set
+	sampleRate float64  // fraction of writes kept, 1 when sampling is off
+	sampleAcc  float64
+
+	dedup        bool
+	dedupLast    []byte // last line appended, including its newline
+	dedupCount   int    // repeats of dedupLast not yet summarized
+	dedupPartial []byte // incomplete trailing line awaiting its newline
+
+	stats  stats
+	events events
 }
 
-// New creates and initializes a new Writer for the specified directory.
-// The directory must exist. Additional options can be provided to customize
-// the Writer's behavior.
+// New creates and initializes a new Writer for the specified directory. The
+// directory must exist unless WithMkdirAll is given. Additional options can be
+// provided to customize the Writer's behavior.
 func New(dirPath string, opts ...Option) (*Writer, error) {
-	if fi, err := os.Stat(dirPath); err != nil {
-		if os.IsNotExist(err) {
+	w := newDefaultWriter(dirPath)
+	for _, opt := range opts {
+		opt(w)
+	}
+	if err := w.validate(); err != nil {
+		return nil, err
+	}
+	if !w.noSync || w.syncInterval > 0 {
+		w.mu = &sync.Mutex{}
+		w.ioMu = &sync.Mutex{}
+	}
+	w.flushedAt.Store(w.lastFlush.UnixNano())
+	if w.mkdirAll {
+		exists, err := fileExists(w.fs, dirPath)
+		if err != nil {
+			return nil, err
+		}
+		created := !exists
+		if err := w.fs.MkdirAll(dirPath, w.dirMode); err != nil {
+			return nil, fmt.Errorf("failed to create directory %q: %w", dirPath, err)
+		}
+		if _, ok := w.fs.(OSFS); ok && created && w.owner != nil {
+			if err := chownPath(dirPath, w.owner[0], w.owner[1]); err != nil {
+				return nil, fmt.Errorf("failed to set owner of directory %q: %w", dirPath, err)
+			}
+		}
+	}
+	if fi, err := w.fs.Stat(dirPath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
 			return nil, fmt.Errorf("directory %q does not exist", dirPath)
 		} else {
 			return nil, err
@@ -101,61 +266,68 @@ func New(dirPath string, opts ...Option) (*Writer, error) {
 	} else if !fi.IsDir() {
 		return nil, fmt.Errorf("path %q is not a directory", dirPath)
 	}
-	w := &Writer{
-		buf:         make([]byte, 0, DefaultMaxBufSize),
-		dirPath:     dirPath,
-		lastFlush:   time.Now(),
-		maxFileSize: DefaultMaxFileSize,
-		maxBufSize:  DefaultMaxBufSize,
-		maxBufAge:   DefaultMaxBufAge,
+	if err := w.openActive(); err != nil {
+		return nil, err
 	}
-	for _, opt := range opts {
-		opt(w)
+	if w.hashChain {
+		if err := w.resumeChain(); err != nil {
+			w.closeActive()
+			return nil, fmt.Errorf("failed to resume hash chain: %w", err)
+		}
 	}
-	var err error
-	if w.file, err = os.OpenFile(filepath.Join(w.dirPath, "latest.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
-		return nil, err
+	// An existing file may already exceed maxFileSize, e.g. after a crash or a
+	// reduced limit. Rotate it now rather than growing it until the next flush.
+	fi, err := w.file.Stat()
+	if err != nil {
+		w.closeActive()
+		return nil, fmt.Errorf("failed to stat log file: %w", err)
 	}
-	return w, nil
-}
-
-// options
-
-// Option defines a function that configures a Writer.
-type Option func(*Writer)
-
-// WithMaxFileSize sets the maximum size of the log file before it's rotated.
-func WithMaxFileSize(size int64) Option {
-	return func(w *Writer) {
-		w.maxFileSize = size
+	if w.archiver != nil {
+		w.archives = newArchivePool(w.archiver, w.archiveWorkers, w.archiveAttempts, w.archiveQueue, w.archiveBackoff, func(err error) {
+			w.fail(&w.stats.archiveFailures, err)
+		})
 	}
-}
-
-// WithMaxBufSize sets the maximum size of the internal buffer before flushing.
-func WithMaxBufSize(size int) Option {
-	return func(w *Writer) {
-		w.maxBufSize = size
-		if cap(w.buf) < size {
-			newBuf := make([]byte, len(w.buf), size)
-			copy(newBuf, w.buf) // in case there's data in the buffer
-			w.buf = newBuf
+	if fi.Size() > 0 && fi.Size() >= w.maxFileSize {
+		if err := w.rotate(); err != nil {
+			if w.file != nil {
+				w.closeActive()
+			}
+			if w.archives != nil {
+				w.archives.close()
+			}
+			return nil, err
 		}
 	}
-}
-
-// WithMaxBufAge sets the age of the buffer. On Write(), if the buffer is older
-// than maxBufAge, it will be flushed.
-func WithMaxBufAge(d time.Duration) Option {
-	return func(w *Writer) {
-		w.maxBufAge = d
+	if w.verifyOnStart {
+		w.reportCorrupt()
 	}
+	w.startSyncLoop()
+	return w, nil
 }
 
-// WithSync configures the Writer to be safe for concurrent use by enabling
-// internal synchronization via a mutex.
-func WithSync() Option {
-	return func(w *Writer) {
-		w.mu = &sync.Mutex{}
+// newDefaultWriter returns an unopened Writer for dirPath with default settings.
+func newDefaultWriter(dirPath string) *Writer {
+	return &Writer{
+		fs:          OSFS{},
+		buf:         make([]byte, 0, DefaultMaxBufSize),
+		dirPath:     dirPath,
+		dirMode:     DefaultDirMode,
+		lastFlush:   now(),
+		maxFileSize: DefaultMaxFileSize,
+		maxBufSize:  DefaultMaxBufSize,
+		maxBufAge:   DefaultMaxBufAge,
+		sampleRate:  1,
+		timeLayout:  DefaultRotationTimeLayout,
+
+		compressLevel: gzip.DefaultCompression,
+		retry:         RetryPolicy{Attempts: 1},
+
+		pauseLimit:      DefaultPauseLimit,
+		snapshotFiles:   DefaultSnapshotFiles,
+		snapshotBytes:   DefaultSnapshotBytes,
+		archiveWorkers:  DefaultArchiveWorkers,
+		archiveAttempts: DefaultArchiveAttempts,
+		archiveBackoff:  DefaultArchiveBackoff,
 	}
 }
 
@@ -174,13 +346,95 @@ func (w *Writer) Flush() error {
 	return w.flush()
 }
 
+// WriteContext is like Write but stops waiting once ctx is done, so request
+// handlers can't hang on a stalled log disk. If p was buffered by then, e.g.
+// when the wait was for the flush it triggered, it returns len(p) along with
+// the context's error and the flush keeps running in the background as with
+// FlushContext; otherwise it returns 0 and p is never written.
+//
+// The Writer stays usable after an abandoned write, unless it was created with
+// WithNoSync, in which case it must not be used again.
+func (w *Writer) WriteContext(ctx context.Context, p []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	type result struct {
+		n   int
+		err error
+	}
+	var state atomic.Int32
+	done := make(chan result, 1)
+	go func() {
+		n, err := w.write(p, &state, false)
+		done <- result{n, err}
+	}()
+	select {
+	case r := <-done:
+		return r.n, r.err
+	case <-ctx.Done():
+		if state.CompareAndSwap(writePending, writeAbandoned) {
+			return 0, fmt.Errorf("%w: %w", ErrDropped, ctx.Err())
+		}
+		return len(p), fmt.Errorf("write abandoned during flush: %w", ctx.Err())
+	}
+}
+
+// FlushContext is like Flush but stops waiting once ctx is done, letting
+// callers on latency sensitive paths bound how long they block on disk I/O.
+//
+// An abandoned flush keeps running in the background and its data remains
+// buffered until it completes. The Writer stays usable and later calls simply
+// wait their turn, unless it was created with WithNoSync, in which case it
+// must not be used again.
+func (w *Writer) FlushContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Flush()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("flush abandoned: %w", ctx.Err())
+	}
+}
+
 // Write appends the contents of p to the Writer's buffer.
 // When the buffer's size exceeds maxBufSize or the time since the last flush
-// exceeds maxBufAge, the buffer is flushed to disk.
+// exceeds maxBufAge, the buffer is flushed to disk. If WithFlushOnNewline is
+// set, a p ending in '\n' also triggers a flush.
 //
 // Write implements the io.Writer interface and returns the length of p on success.
 // Partial writes are not supported.
 func (w *Writer) Write(p []byte) (int, error) {
+	return w.write(p, nil, false)
+}
+
+// WriteOwned is like Write but takes ownership of p: the caller must not
+// modify or reuse it after the call, even if an error is returned. When p
+// would start a new buffer, e.g. right after a flush, the Writer buffers p
+// itself rather than copying it, so callers that batch entries into their own
+// buffers hand them over without a copy. Timestamps, hash chaining,
+// deduplication, and filters that change p still copy it.
+func (w *Writer) WriteOwned(p []byte) (int, error) {
+	return w.write(p, nil, true)
+}
+
+// Write states shared between WriteContext and the write it abandons.
+const (
+	writePending int32 = iota
+	writeAccepted
+	writeAbandoned
+)
+
+// write implements Write and WriteOwned, which sets owned. If state is
+// non-nil, p is only read if state can be moved from writePending to
+// writeAccepted, so that WriteContext can tell whether an abandoned write will
+// still happen.
+func (w *Writer) write(p []byte, state *atomic.Int32, owned bool) (int, error) {
 	if w.mu != nil {
 		w.mu.Lock()
 		defer w.mu.Unlock()
@@ -188,13 +442,44 @@ func (w *Writer) Write(p []byte) (int, error) {
 	if w.err != nil {
 		return 0, w.err
 	}
-	w.buf = append(w.buf, p...)
-	if len(w.buf) >= w.maxBufSize || time.Since(w.lastFlush) >= w.maxBufAge {
+	// Claim p before anything reads it: once abandoned, the caller may reuse it.
+	if state != nil && !state.CompareAndSwap(writePending, writeAccepted) {
+		return 0, nil // abandoned by WriteContext
+	}
+	n, orig := len(p), p
+	if w.sampleRate < 1 && !w.sample() {
+		w.stats.sampledWrites.Add(1)
+		return n, nil
+	}
+	for _, filter := range w.filters {
+		if p = filter(p); len(p) == 0 {
+			return n, nil
+		}
+	}
+	if w.limiter != nil && !w.limiter.allow(now(), len(p)) {
+		w.stats.droppedWrites.Add(1)
+		w.stats.droppedBytes.Add(uint64(len(p)))
+		w.emit(Event{Kind: EventDrop, Err: errRateDropped, Bytes: len(p)})
+		return n, nil
+	}
+	if w.pauseFull(len(p)) {
+		return n, nil
+	}
+	switch {
+	case w.dedup:
+		w.appendDedup(p)
+	case owned && len(w.buf) == 0 && len(p) > 0 && &p[0] == &orig[0] && w.stampLayout == "" && !w.hashChain:
+		w.adoptBuf(p)
+	default:
+		w.appendBuf(p)
+	}
+	w.pending.Store(int64(len(w.buf) + w.inflight))
+	if w.shouldFlush(p) {
 		if err := w.flush(); err != nil {
 			return 0, err
 		}
 	}
-	return len(p), nil
+	return n, nil
 }
 
 // WriteString is a convenience method that wraps Write() for string data.
@@ -204,90 +489,414 @@ func (w *Writer) WriteString(s string) (int, error) {
 }
 
 // Close flushes any remaining buffered data to disk and closes the underlying file.
-// It should be called when the Writer is no longer needed.
+// It should be called when the Writer is no longer needed. Calling Close again
+// is a no-op returning nil, so it's safe to both defer Close and call it explicitly.
 func (w *Writer) Close() error {
+	w.stopSyncLoop()
 	if w.mu != nil {
+		w.ioMu.Lock()
+		defer w.ioMu.Unlock()
 		w.mu.Lock()
 		defer w.mu.Unlock()
 	}
-	if w.err != nil {
-		return w.err
+	if w.err == ErrClosed {
+		return nil
 	}
-	if err := w.flush(); err != nil {
-		return err
+	if w.budget != nil {
+		defer w.budget.remove(w)
+	}
+	// Resources are released even after a failure, returning the first error.
+	err := w.err
+	if err == nil {
+		if len(w.dedupPartial) > 0 {
+			w.dedupRepeat()
+			w.appendBuf(w.dedupPartial) // keep the incomplete line as is
+			w.dedupPartial = w.dedupPartial[:0]
+		}
+		if w.hashChain && len(w.chainPartial) > 0 {
+			w.appendChainLine(w.chainPartial) // terminate the incomplete line
+			w.chainPartial = w.chainPartial[:0]
+		}
+		err = w.flushLocked(false)
+	}
+	if w.file != nil {
+		if cerr := w.closeActive(); err == nil {
+			err = cerr
+		}
+	}
+	if serr := w.closeSinks(); err == nil {
+		err = serr
+	}
+	if w.archives != nil {
+		w.archives.close()
+	}
+	w.putBuf(w.buf)
+	w.putBuf(w.spare)
+	w.buf, w.spare = nil, nil
+	w.closeEvents()
+	w.err = ErrClosed
+	return err
+}
+
+// CloseContext is like Close but stops waiting once ctx is done. This bounds
+// shutdown time when the final flush hangs, e.g. on a stalled network filesystem.
+//
+// If ctx expires first, CloseContext returns the number of buffered bytes that
+// may not have reached disk along with ctx's error. The close continues in the
+// background; the Writer must not be used afterwards. On success it returns 0
+// and the result of Close.
+func (w *Writer) CloseContext(ctx context.Context) (int, error) {
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Close()
+	}()
+	select {
+	case err := <-done:
+		return 0, err
+	case <-ctx.Done():
+		return int(w.pending.Load()), fmt.Errorf("close abandoned with data possibly unwritten: %w", ctx.Err())
 	}
-	return w.file.Close()
 }
 
 // internal methods
 
-// flush writes the contents of the buffer to the latest log file.
-// If writing the buffer would cause the file to exceed maxFileSize,
-// the file is rotated before writing. After a successful flush, the buffer
-// is reset and lastFlush is updated.
+// shouldFlush reports whether the buffer should be flushed after appending p.
+func (w *Writer) shouldFlush(p []byte) bool {
+	flush := len(w.buf) >= w.maxBufSize || now().Sub(w.lastFlush) >= w.maxBufAge ||
+		(w.flushOnNewline && len(p) > 0 && p[len(p)-1] == '\n')
+	if w.chaos != nil {
+		switch w.chaos.Intn(4) {
+		case 0:
+			return true // flush early
+		case 1:
+			return false // hold the buffer a little longer
+		}
+	}
+	return flush
+}
+
+// flush writes the contents of the buffer to the latest log file. The caller
+// must hold mu, if any.
 //
-// flush returns an error if the write, file sync, or rotation fails.
+// Unless WithNoSync is set, flushes are coalesced: mu is released while waiting
+// for and performing I/O, so other goroutines keep appending meanwhile. Once
+// the current flush completes, the next one writes everything they appended in
+// one go, and those whose data it covered return without flushing again.
 func (w *Writer) flush() error {
+	if w.paused {
+		return nil // held until Resume
+	}
+	if w.ioMu == nil {
+		return w.flushLocked(false)
+	}
+	batch := w.batch // our data is in the buffer that will be taken as this batch
+	w.mu.Unlock()
+	w.ioMu.Lock()
+	defer w.ioMu.Unlock()
+	w.mu.Lock()
+	if w.flushed > batch {
+		return w.err // another goroutine flushed our data while we waited
+	}
+	return w.flushLocked(true)
+}
+
+// flushLocked writes the buffer to the active file, rotating it first if it
+// would exceed maxFileSize. The caller must hold mu and ioMu, if any; with
+// release set, mu is released during I/O. After a successful flush the
+// buffer is reset and lastFlush is updated.
+//
+// flushLocked returns an error if the write, file sync, or rotation fails.
+func (w *Writer) flushLocked(release bool) error {
 	if w.err != nil {
 		return w.err
 	}
-	if w.file == nil {
-		w.err = fmt.Errorf("log file %q is closed", filepath.Join(w.dirPath, "latest.log"))
+	if w.file == nil && w.fallback == nil && !w.dormant {
+		w.err = fmt.Errorf("log file %q is closed", w.activePath())
 		return w.err
 	}
+	w.dedupRepeat()
 	if len(w.buf) == 0 {
 		return nil
 	}
+	// Take the buffer so that writers can keep appending to a fresh one.
+	buf := w.buf
+	if w.spare != nil {
+		w.buf, w.spare = w.spare, nil
+	} else {
+		w.buf = w.getBuf()
+	}
+	w.inflight = len(buf)
+	w.batch++
+	done := w.batch
+	earlyRotate := w.chaos != nil && w.chaos.Intn(8) == 0 // decided under mu as chaos isn't thread safe
+	if release {
+		w.mu.Unlock()
+	}
+	start := w.traceStart(TraceFlush)
+	err := w.deliver(buf, earlyRotate)
+	w.traceEnd(TraceFlush, start, err)
+	if release {
+		w.mu.Lock()
+	}
+	w.flushed = done
+	w.inflight = 0
+	w.pending.Store(int64(len(w.buf)))
+	if err != nil {
+		w.err = err
+		w.reportError(err)
+		return err
+	}
+	if cap(buf) <= w.maxKeptBuf() {
+		w.spare = buf[:0]
+	}
+	w.lastFlush = now()
+	w.flushedAt.Store(w.lastFlush.UnixNano())
+	return nil
+}
+
+// reportError passes err to the error handler, if any.
+func (w *Writer) reportError(err error) {
+	w.fail(&w.stats.flushFailures, err)
+	w.emit(Event{Kind: EventFlushError, Err: err})
+}
+
+// writeOut writes buf to the active file and syncs it, rotating first if
+// needed. It only touches state guarded by ioMu.
+func (w *Writer) writeOut(buf []byte, earlyRotate bool) error {
 	// Determine if the file needs to be rotated.
-	fi, err := w.file.Stat()
+	size, err := w.activeFileSize()
 	if err != nil {
-		w.err = fmt.Errorf("failed to stat log file: %v", err)
-		return w.err
+		return err
+	}
+	rotate := size+int64(len(buf)) >= w.maxFileSize
+	if earlyRotate && size > 0 {
+		rotate = true // rotate early
+	}
+	if w.maxLines > 0 && w.activeLines >= w.maxLines {
+		rotate = true
 	}
-	if fi.Size()+int64(len(w.buf)) >= w.maxFileSize {
+	if rotate && !w.rotationAllowed() {
+		rotate = false // overshoot the size limit rather than rotate too often
+	}
+	if rotate {
 		if err := w.rotate(); err != nil {
 			return err
 		}
 	}
-	// Write the buffer to the file and sync.
-	if _, err := w.file.Write(w.buf); err != nil {
-		w.err = fmt.Errorf("failed to write to log file: %v", err)
-		return w.err
+	// Split the buffer at the line limit, rotating between the parts.
+	out := buf
+	for w.maxLines > 0 && w.rotationAllowed() {
+		i := lineEnd(out, w.maxLines-w.activeLines)
+		if i < 0 || i == len(out) {
+			break
+		}
+		if err := w.writeChunk(out[:i]); err != nil {
+			return err
+		}
+		if err := w.rotate(); err != nil {
+			return err
+		}
+		out = out[i:]
 	}
-	if err := w.file.Sync(); err != nil {
-		w.err = fmt.Errorf("failed to sync log file: %v", err)
-		return w.err
+	if err := w.writeChunk(out); err != nil {
+		return err
 	}
-	w.buf = w.buf[:0]
-	w.lastFlush = time.Now()
+	w.writeSinks(buf)
+	w.fileSize.Store(w.activeSize)
 	return nil
 }
 
-// rotate renames the latest log file with a timestamp and creates a new
-// "latest.log" file for subsequent writes. The timestamp includes sub-second
-// precision to avoid naming collisions in high-frequency rotation scenarios.
-func (w *Writer) rotate() error {
-	if w.err != nil {
-		return w.err
+// writeChunk writes p to the active file and syncs it.
+func (w *Writer) writeChunk(p []byte) error {
+	if err := w.writeActive(p); err != nil {
+		return fmt.Errorf("failed to write to log file: %w", diskFull(err))
 	}
-	if w.file != nil {
-		if err := w.file.Close(); err != nil {
-			w.err = fmt.Errorf("failed to close log file: %v", err)
-			return w.err
+	if w.maxLines > 0 {
+		w.activeLines += bytes.Count(p, []byte("\n"))
+	}
+	if w.syncInterval > 0 {
+		w.syncDirty = true // left to syncLoop
+	} else if err := w.syncFile(); err != nil {
+		return fmt.Errorf("failed to sync log file: %w", diskFull(err))
+	}
+	return nil
+}
+
+// rotationAllowed reports whether the minimum rotation interval has elapsed.
+func (w *Writer) rotationAllowed() bool {
+	return w.minRotation <= 0 || now().Sub(w.lastRotation) >= w.minRotation
+}
+
+// ext returns the extension of log files, including the compression suffix.
+func (w *Writer) ext() string {
+	if w.compress {
+		return ".log.gz"
+	}
+	return ".log"
+}
+
+// activePath returns the path of the log file currently being written.
+func (w *Writer) activePath() string {
+	return filepath.Join(w.dirPath, "latest"+w.ext())
+}
+
+// openActive opens the active log file for appending, creating it if needed.
+// With stream compression, a new gzip member is started on top of it.
+func (w *Writer) openActive() error {
+	var created bool
+	if w.owner != nil {
+		exists, err := fileExists(w.fs, w.activePath())
+		if err != nil {
+			return err
 		}
-		w.file = nil
+		created = !exists
 	}
-	oldPath := filepath.Join(w.dirPath, "latest.log")
-	ts := time.Now().Format("20060102-150405.000000")
-	newPath := filepath.Join(w.dirPath, fmt.Sprintf("%s.log", ts))
-	if err := os.Rename(oldPath, newPath); err != nil {
-		w.err = fmt.Errorf("failed to rename log file: %v", err)
+	f, err := w.fs.OpenFile(w.activePath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
 		return err
 	}
-	var err error
-	if w.file, err = os.OpenFile(oldPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
-		w.err = fmt.Errorf("failed to create new log file: %v", err)
+	if f, ok := f.(*os.File); ok && created {
+		if err := chownFile(f, w.owner[0], w.owner[1]); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to set owner of log file: %w", err)
+		}
+	}
+	w.file = f
+	w.activeSize = 0
+	w.activeLines = 0
+	w.revalidatedAt = now()
+	if fi, err := f.Stat(); err == nil {
+		w.activeSize = fi.Size()
+		w.fileSize.Store(fi.Size())
+		if w.maxLines > 0 && fi.Size() > 0 {
+			if w.activeLines, err = countLines(w.fs, w.activePath()); err != nil {
+				f.Close()
+				w.file = nil
+				return fmt.Errorf("failed to count lines of log file: %w", err)
+			}
+		}
+	}
+	if f, ok := f.(*os.File); ok && w.preallocate {
+		preallocate(f, w.maxFileSize)
+	}
+	if of, ok := f.(*os.File); ok && w.directIO {
+		f = directIO(of)
+		w.file = f
+	}
+	if w.compress {
+		w.gz, _ = gzip.NewWriterLevel(activeCounter{w}, w.compressLevel) // level is validated by New
+		w.gzDirty = false
+	}
+	if w.budget != nil {
+		w.budget.touch(w)
+	}
+	return nil
+}
+
+// writeActive writes p to the active log file. With stream compression, the
+// stream is flushed afterwards so that everything written so far can be
+// decompressed even if the process dies before the member is finished.
+func (w *Writer) writeActive(p []byte) error {
+	if w.gz == nil {
+		return w.retryIO(func() error {
+			n, err := activeCounter{w}.Write(p)
+			p = p[n:]
+			return err
+		})
+	}
+	w.gzDirty = true
+	if _, err := w.gz.Write(p); err != nil {
 		return err
 	}
+	return w.gz.Flush()
+}
+
+// closeActive finishes any compression stream and closes the active log file.
+func (w *Writer) closeActive() error {
+	var err error
+	if w.gz != nil {
+		if w.gzDirty { // don't append empty members for files opened but never written
+			err = w.gz.Close()
+		}
+		w.gz = nil
+	}
+	if w.syncDirty {
+		start := w.traceStart(TraceSync)
+		serr := w.file.Sync()
+		w.traceEnd(TraceSync, start, serr)
+		if err == nil {
+			err = serr
+		}
+		w.syncDirty = false
+	}
+	if cerr := w.file.Close(); err == nil {
+		err = cerr
+	}
+	w.file = nil
+	return err
+}
+
+// rotate renames the latest log file to a name from the naming strategy and
+// creates a new "latest.log" file for subsequent writes. The default timestamp
+// includes sub-second precision to avoid naming collisions in high-frequency
+// rotation scenarios. Should the name still be taken, e.g. by a previous run
+// with a skewed clock, the strategy is asked again with an increasing seq
+// rather than replacing the existing file.
+func (w *Writer) rotate() error {
+	start := w.traceStart(TraceRotate)
+	err := w.rotateFile()
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrRotateFailed, diskFull(err))
+	}
+	w.traceEnd(TraceRotate, start, err)
+	return err
+}
+
+// rotateFile implements rotate.
+func (w *Writer) rotateFile() error {
+	if w.file != nil {
+		if err := w.closeActive(); err != nil {
+			return fmt.Errorf("failed to close log file: %w", err)
+		}
+	}
+	oldPath := w.activePath()
+	t := now()
+	w.lastRotation = t
+	naming := w.namer()
+	newPath := filepath.Join(w.dirPath, naming.Name(t, 0)+w.ext())
+	for seq := 1; ; seq++ {
+		exists, err := fileExists(w.fs, newPath)
+		if err != nil {
+			return fmt.Errorf("failed to check for rotated log file: %w", err)
+		}
+		if !exists {
+			break
+		}
+		newPath = filepath.Join(w.dirPath, naming.Name(t, seq)+w.ext())
+	}
+	if err := moveLog(w.fs, w.rotation, oldPath, newPath); err != nil {
+		return fmt.Errorf("failed to rename log file: %w", err)
+	}
+	if w.signer != nil {
+		if err := signFile(w.fs, newPath, w.signer); err != nil {
+			return fmt.Errorf("failed to sign rotated log file: %w", err)
+		}
+	}
+	w.emit(Event{Kind: EventRotation, Path: newPath})
+	if w.archives != nil && !w.archives.push(newPath) {
+		w.fail(&w.stats.archiveFailures, fmt.Errorf("archive queue full, leaving %q in place", newPath))
+	}
+	if err := w.openActive(); err != nil {
+		return fmt.Errorf("failed to create new log file: %w", err)
+	}
+	if err := syncDir(w.fs, w.dirPath); err != nil {
+		return fmt.Errorf("failed to sync log directory: %w", err)
+	}
+	if err := w.applyRetention(); err != nil {
+		return fmt.Errorf("failed to apply retention: %w", err)
+	}
+	if err := w.applyBundling(); err != nil {
+		return fmt.Errorf("failed to bundle rotated files: %w", err)
+	}
 	return nil
 }
@@ -1,10 +1,18 @@
 package rlog
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -128,11 +136,11 @@ func TestRotation(t *testing.T) {
 	}
 }
 
-// TestConcurrentWrites verifies that concurrent writes work correctly when WithSync is enabled.
+// TestConcurrentWrites verifies that concurrent writes work correctly with the
+// default internal synchronization.
 func TestConcurrentWrites(t *testing.T) {
 	tempDir := t.TempDir()
-	// Use WithSync to enable internal synchronization.
-	w, err := New(tempDir, WithSync())
+	w, err := New(tempDir)
 	if err != nil {
 		t.Fatalf("failed to create Writer: %v", err)
 	}
@@ -181,3 +189,571 @@ func TestConcurrentWrites(t *testing.T) {
 		t.Errorf("concurrent writes length mismatch: got %d bytes, want %d", len(data), expectedBytes)
 	}
 }
+
+// TestNoSync verifies that Writers lock by default, that WithNoSync opts out,
+// and that WithSyncInterval keeps the locks its goroutine needs.
+func TestNoSync(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		opts   []Option
+		locked bool
+	}{
+		{"default", nil, true},
+		{"deprecated WithSync", []Option{WithSync()}, true},
+		{"WithNoSync", []Option{WithNoSync()}, false},
+		{"WithNoSync and WithSyncInterval", []Option{WithNoSync(), WithSyncInterval(time.Second)}, true},
+	} {
+		w, err := New(t.TempDir(), tt.opts...)
+		if err != nil {
+			t.Fatalf("%s: failed to create Writer: %v", tt.name, err)
+		}
+		if locked := w.mu != nil && w.ioMu != nil; locked != tt.locked {
+			t.Errorf("%s: expected locked %v, got %v", tt.name, tt.locked, locked)
+		}
+		w.Close()
+	}
+}
+
+// TestFlushOnNewline verifies that a write ending in a newline is flushed immediately.
+func TestFlushOnNewline(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithFlushOnNewline())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+
+	logPath := filepath.Join(tempDir, "latest.log")
+	if _, err := w.Write([]byte("partial")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if len(data) != 0 {
+		t.Fatalf("expected partial line to stay buffered, got %q", string(data))
+	}
+
+	if _, err := w.Write([]byte(" line\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	data, err = os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if string(data) != "partial line\n" {
+		t.Errorf("log content mismatch: got %q, want %q", string(data), "partial line\n")
+	}
+}
+
+// TestChaos verifies that randomized flush and rotation timing never loses or reorders data.
+func TestChaos(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithChaos(1), WithMaxFileSize(64))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+
+	var want strings.Builder
+	for i := 0; i < 100; i++ {
+		line := "chaos line\n"
+		want.WriteString(line)
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("failed to list directory: %v", err)
+	}
+	var got strings.Builder
+	for _, entry := range entries { // timestamps sort chronologically, "latest.log" sorts last
+		data, err := os.ReadFile(filepath.Join(tempDir, entry.Name()))
+		if err != nil {
+			t.Fatalf("failed to read %q: %v", entry.Name(), err)
+		}
+		got.Write(data)
+	}
+	if got.String() != want.String() {
+		t.Errorf("content mismatch across %d files: got %d bytes, want %d", len(entries), got.Len(), want.Len())
+	}
+}
+
+// TestCloseContext verifies that CloseContext closes normally and reports no loss when ctx is live.
+func TestCloseContext(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := New(tempDir)
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	message := "closing\n"
+	if _, err := w.Write([]byte(message)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	lost, err := w.CloseContext(ctx)
+	if err != nil || lost != 0 {
+		t.Fatalf("CloseContext: got (%d, %v), want (0, nil)", lost, err)
+	}
+	data, err := os.ReadFile(filepath.Join(tempDir, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if string(data) != message {
+		t.Errorf("log content mismatch: got %q, want %q", string(data), message)
+	}
+
+	// A hung flush is simulated by holding the Writer's lock.
+	w, err = New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	if _, err := w.Write([]byte(message)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	w.mu.Lock()
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	lost, err = w.CloseContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if lost != len(message) {
+		t.Errorf("expected %d lost bytes, got %d", len(message), lost)
+	}
+	w.mu.Unlock()
+}
+
+// TestFlushContext verifies that FlushContext flushes normally and gives up on a blocked flush.
+func TestFlushContext(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := New(tempDir)
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+
+	message := "flush me\n"
+	if _, err := w.Write([]byte(message)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.FlushContext(context.Background()); err != nil {
+		t.Fatalf("FlushContext failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tempDir, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if string(data) != message {
+		t.Errorf("log content mismatch: got %q, want %q", string(data), message)
+	}
+
+	// A stalled flush is simulated by holding the Writer's lock.
+	w.mu.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := w.FlushContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	w.mu.Unlock()
+}
+
+// TestWriteContext verifies that WriteContext reports whether an abandoned
+// write was buffered, and that a write abandoned before that never lands.
+func TestWriteContext(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithFlushOnNewline())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	if n, err := w.WriteContext(context.Background(), []byte("one\n")); n != 4 || err != nil {
+		t.Fatalf("WriteContext: got (%d, %v), want (4, nil)", n, err)
+	}
+
+	// A stalled flush is simulated by holding the I/O lock; the write is
+	// buffered before the flush it triggers blocks.
+	w.ioMu.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if n, err := w.WriteContext(ctx, []byte("two\n")); n != 4 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected (4, deadline exceeded), got (%d, %v)", n, err)
+	}
+	w.ioMu.Unlock()
+
+	// Holding the buffer lock keeps the write from being buffered at all.
+	w.mu.Lock()
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if n, err := w.WriteContext(ctx, []byte("three\n")); n != 0 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected (0, deadline exceeded), got (%d, %v)", n, err)
+	}
+	w.mu.Unlock()
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tempDir, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if string(data) != "one\ntwo\n" {
+		t.Errorf("log content mismatch: got %q, want %q", data, "one\ntwo\n")
+	}
+}
+
+// TestWriteOwned verifies that WriteOwned buffers a slice starting a new
+// buffer in place, and copies it otherwise.
+func TestWriteOwned(t *testing.T) {
+	w, m, err := NewMemory()
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	batch := []byte("one\ntwo\n")
+	if n, err := w.WriteOwned(batch); n != len(batch) || err != nil {
+		t.Fatalf("WriteOwned: got (%d, %v), want (%d, nil)", n, err, len(batch))
+	}
+	if &w.buf[0] != &batch[0] {
+		t.Errorf("expected the batch to be buffered without a copy")
+	}
+	w.WriteOwned([]byte("three\n")) // appended to the batch
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	w.WriteString("four\n")
+	last := []byte("five\n")
+	w.WriteOwned(last)
+	if &w.buf[len(w.buf)-len(last)] == &last[0] {
+		t.Errorf("expected a write to a non-empty buffer to be copied")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if data, _ := m.ReadFile("latest.log"); string(data) != "one\ntwo\nthree\nfour\nfive\n" {
+		t.Errorf("log content mismatch: got %q", data)
+	}
+}
+
+// TestStartupRotation verifies that an oversized latest.log is rotated when the Writer is created.
+func TestStartupRotation(t *testing.T) {
+	tempDir := t.TempDir()
+	previous := "previous run output"
+	if err := os.WriteFile(filepath.Join(tempDir, "latest.log"), []byte(previous), 0o644); err != nil {
+		t.Fatalf("failed to create latest.log: %v", err)
+	}
+	w, err := New(tempDir, WithMaxFileSize(10))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read latest.log: %v", err)
+	}
+	if len(data) != 0 {
+		t.Errorf("expected empty latest.log after startup rotation, got %q", string(data))
+	}
+	names, err := logFiles(OSFS{}, tempDir)
+	if err != nil || len(names) != 2 {
+		t.Fatalf("expected one rotated file and latest.log, got %v (err %v)", names, err)
+	}
+	rotated, err := os.ReadFile(filepath.Join(tempDir, names[0]))
+	if err != nil {
+		t.Fatalf("failed to read rotated file: %v", err)
+	}
+	if string(rotated) != previous {
+		t.Errorf("rotated file content mismatch: got %q, want %q", string(rotated), previous)
+	}
+}
+
+// TestMkdirAll verifies that WithMkdirAll creates a missing directory tree.
+func TestMkdirAll(t *testing.T) {
+	dirPath := filepath.Join(t.TempDir(), "nested", "logs")
+	w, err := New(dirPath, WithMkdirAll(), WithDirMode(0o700))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	fi, err := os.Stat(dirPath)
+	if err != nil {
+		t.Fatalf("expected directory to exist: %v", err)
+	}
+	if !fi.IsDir() {
+		t.Fatalf("expected %q to be a directory", dirPath)
+	}
+	if runtime.GOOS != "windows" && fi.Mode().Perm() != 0o700 {
+		t.Errorf("directory mode mismatch: got %v, want %v", fi.Mode().Perm(), os.FileMode(0o700))
+	}
+}
+
+// TestRetention verifies that rotated files beyond WithMaxRotations are deleted.
+func TestRetention(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithMaxFileSize(10), WithMaxRotations(2))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for _, msg := range []string{"aaaaaa", "bbbbbb", "cccccc", "dddddd", "eeeeee"} {
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	names, err := logFiles(OSFS{}, tempDir)
+	if err != nil {
+		t.Fatalf("failed to list log files: %v", err)
+	}
+	want := []string{"cccccc", "dddddd", "eeeeee"}
+	if len(names) != len(want) {
+		t.Fatalf("expected %d files, got %v", len(want), names)
+	}
+	for i, name := range names {
+		data, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("failed to read %q: %v", name, err)
+		}
+		if string(data) != want[i] {
+			t.Errorf("%s content mismatch: got %q, want %q", name, string(data), want[i])
+		}
+	}
+}
+
+// TestDoubleClose verifies that Close is idempotent and later calls report ErrClosed.
+func TestDoubleClose(t *testing.T) {
+	w, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("first Close failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close returned %v, want nil", err)
+	}
+	if _, err := w.Write([]byte("late")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write after Close: got %v, want ErrClosed", err)
+	}
+	if err := w.Flush(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Flush after Close: got %v, want ErrClosed", err)
+	}
+}
+
+// TestCloseAfterError verifies that Close releases the file after a failure,
+// returns the error once, and is then a no-op.
+func TestCloseAfterError(t *testing.T) {
+	fsys := &flakyFS{Memory: NewMemoryFS()}
+	w, err := New(".", WithFS(fsys))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	fsys.down = true
+	w.WriteString("hello\n")
+	if err := w.Flush(); err == nil {
+		t.Fatal("expected Flush to fail")
+	}
+	if err := w.Close(); err == nil {
+		t.Error("expected first Close to return the write error")
+	}
+	if w.file != nil {
+		t.Error("expected Close to close the file after a failure")
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close returned %v, want nil", err)
+	}
+	if _, err := w.Write([]byte("late")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write after Close: got %v, want ErrClosed", err)
+	}
+}
+
+// TestPreallocate verifies that preallocation doesn't change the file's
+// reported size, which rotation depends on.
+func TestPreallocate(t *testing.T) {
+	dirPath := t.TempDir()
+	w, err := New(dirPath, WithPreallocate(), WithMaxFileSize(1024*1024))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	if _, err := w.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	fi, err := os.Stat(filepath.Join(dirPath, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to stat log file: %v", err)
+	}
+	if fi.Size() != 6 {
+		t.Errorf("file size mismatch: got %d, want 6", fi.Size())
+	}
+}
+
+// TestRotationTimeLayout verifies that rotated names use the configured layout in UTC.
+func TestRotationTimeLayout(t *testing.T) {
+	fixed := time.Date(2024, 3, 9, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600))
+	now = func() time.Time { return fixed }
+	defer func() { now = time.Now }()
+
+	dirPath := t.TempDir()
+	w, err := New(dirPath, WithMaxFileSize(4), WithRotationTimeLayout("2006-01-02T15-04"), WithUTC())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := w.Write([]byte("line\n")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if ok, _ := fileExists(OSFS{}, filepath.Join(dirPath, "2024-03-10T04-30.log")); !ok {
+		t.Errorf("expected rotated file named with the UTC layout")
+	}
+}
+
+// TestMinRotationInterval verifies that rotations are suppressed until the
+// minimum interval has elapsed.
+func TestMinRotationInterval(t *testing.T) {
+	clock := time.Now()
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	dirPath := t.TempDir()
+	w, err := New(dirPath, WithMaxFileSize(4), WithMinRotationInterval(time.Minute))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	flush := func() {
+		t.Helper()
+		if _, err := w.Write([]byte("line\n")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	rotated := func() int {
+		files, err := logFiles(OSFS{}, dirPath)
+		if err != nil {
+			t.Fatalf("failed to list log files: %v", err)
+		}
+		return len(files) - 1
+	}
+	for i := 0; i < 3; i++ {
+		flush()
+		clock = clock.Add(time.Second)
+	}
+	if n := rotated(); n != 1 {
+		t.Fatalf("expected 1 rotation within the interval, got %d", n)
+	}
+	clock = clock.Add(time.Minute)
+	flush()
+	if n := rotated(); n != 2 {
+		t.Errorf("expected 2 rotations after the interval, got %d", n)
+	}
+}
+
+// TestFilter verifies that filters run in order and can discard writes.
+func TestFilter(t *testing.T) {
+	redact := func(p []byte) []byte {
+		return regexp.MustCompile(`Bearer \S+`).ReplaceAll(p, []byte("Bearer [REDACTED]"))
+	}
+	dropHealth := func(p []byte) []byte {
+		if bytes.Contains(p, []byte("/healthz")) {
+			return nil
+		}
+		return p
+	}
+	prefix := func(p []byte) []byte { return append([]byte("app: "), p...) }
+
+	w, m, err := NewMemory(WithFilter(redact), WithFilter(dropHealth), WithFilter(prefix))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for _, s := range []string{"auth: Bearer abc.def\n", "GET /healthz\n", "done\n"} {
+		if n, err := w.WriteString(s); err != nil || n != len(s) {
+			t.Fatalf("Write returned %d, %v; want %d, nil", n, err, len(s))
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, _ := m.ReadFile("latest.log")
+	if want := "app: auth: Bearer [REDACTED]\napp: done\n"; string(data) != want {
+		t.Errorf("content mismatch: got %q, want %q", data, want)
+	}
+}
+
+// slowSyncFS is an FS whose files count and delay syncs, like a slow disk.
+type slowSyncFS struct {
+	*Memory
+	syncs atomic.Int32
+}
+
+func (s *slowSyncFS) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	f, err := s.Memory.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return slowSyncFile{f, s}, nil
+}
+
+type slowSyncFile struct {
+	File
+	fs *slowSyncFS
+}
+
+func (f slowSyncFile) Sync() error {
+	f.fs.syncs.Add(1)
+	time.Sleep(5 * time.Millisecond)
+	return f.File.Sync()
+}
+
+// TestFlushCoalescing verifies that concurrent writers that all need a flush
+// share a few flushes instead of each paying for a sync.
+func TestFlushCoalescing(t *testing.T) {
+	fsys := &slowSyncFS{Memory: NewMemoryFS()}
+	w, err := New(".", WithFS(fsys), WithFlushOnNewline())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	const writers = 50
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := fmt.Fprintf(w, "line %d\n", i); err != nil {
+				t.Errorf("Write failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, _ := fsys.ReadFile("latest.log")
+	if lines := strings.Count(string(data), "\n"); lines != writers {
+		t.Fatalf("expected %d lines, got %d", writers, lines)
+	}
+	if n := fsys.syncs.Load(); n >= writers/2 {
+		t.Errorf("expected flushes to coalesce, got %d syncs for %d writes", n, writers)
+	}
+}
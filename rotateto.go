@@ -0,0 +1,85 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// RotateTo flushes the buffer and moves the active log file to path, then
+// starts a new active file. It seals the log so far under a name of the
+// caller's choosing, e.g. in a folder named after an incident's case ID. path
+// must not exist yet, though its directory must.
+//
+// The sealed file is signed if WithSigner is set and reported as an
+// EventRotation, but as it lives outside the usual naming scheme, retention,
+// bundling, and archiving leave it alone. RotateTo fails while the Writer is
+// paused. A failure to move the file isn't sticky; logging carries on in the
+// active file.
+func (w *Writer) RotateTo(path string) error {
+	if w.ioMu != nil {
+		w.ioMu.Lock()
+		defer w.ioMu.Unlock()
+	}
+	if w.mu != nil {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+	}
+	if w.paused {
+		return fmt.Errorf("%w: writer is paused", ErrRotateFailed)
+	}
+	if err := w.flushLocked(false); err != nil {
+		return err
+	}
+	if w.dormant {
+		if err := w.wake(); err != nil {
+			return fmt.Errorf("%w: %w", ErrRotateFailed, err)
+		}
+	}
+	if w.file == nil { // writing to the fallback
+		return fmt.Errorf("%w: %w", ErrRotateFailed, w.fallbackErr)
+	}
+	if exists, err := fileExists(w.fs, path); err != nil {
+		return fmt.Errorf("%w: %w", ErrRotateFailed, err)
+	} else if exists {
+		return fmt.Errorf("%w: %w", ErrRotateFailed, &fs.PathError{Op: "rotate", Path: path, Err: fs.ErrExist})
+	}
+	start := w.traceStart(TraceRotate)
+	err := w.rotateTo(path)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrRotateFailed, diskFull(err))
+	}
+	w.traceEnd(TraceRotate, start, err)
+	return err
+}
+
+// rotateTo implements RotateTo once the buffer has been flushed.
+func (w *Writer) rotateTo(path string) error {
+	if err := w.closeActive(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	moveErr := moveLog(w.fs, w.rotation, w.activePath(), path)
+	if moveErr == nil && w.signer != nil {
+		if err := signFile(w.fs, path, w.signer); err != nil {
+			moveErr = fmt.Errorf("failed to sign sealed log file: %w", err)
+		}
+	}
+	if err := w.openActive(); err != nil {
+		w.err = fmt.Errorf("failed to create new log file: %w", err)
+		return w.err
+	}
+	if moveErr != nil {
+		return fmt.Errorf("failed to move log file: %w", moveErr)
+	}
+	for _, dir := range []string{w.dirPath, filepath.Dir(path)} {
+		if err := syncDir(w.fs, dir); err != nil {
+			return fmt.Errorf("failed to sync directory: %w", err)
+		}
+	}
+	w.emit(Event{Kind: EventRotation, Path: path})
+	return nil
+}
@@ -0,0 +1,52 @@
+package rlog
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRotateTo verifies that RotateTo seals the active file at the given path,
+// refuses to overwrite, and leaves the sealed file out of the rotations.
+func TestRotateTo(t *testing.T) {
+	tempDir := t.TempDir()
+	caseDir := t.TempDir()
+	sealed := filepath.Join(caseDir, "case-42.log")
+	w, err := New(tempDir)
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	events := w.Events()
+
+	w.WriteString("before\n")
+	if err := w.RotateTo(sealed); err != nil {
+		t.Fatalf("RotateTo failed: %v", err)
+	}
+	if ev := <-events; ev.Kind != EventRotation || ev.Path != sealed {
+		t.Errorf("expected a rotation event for %s, got %+v", sealed, ev)
+	}
+	w.WriteString("after\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if got, _ := os.ReadFile(sealed); string(got) != "before\n" {
+		t.Errorf("sealed file: got %q, want %q", got, "before\n")
+	}
+	if got, _ := os.ReadFile(filepath.Join(tempDir, "latest.log")); string(got) != "after\n" {
+		t.Errorf("active file: got %q, want %q", got, "after\n")
+	}
+	if rots, err := w.ListRotations(); err != nil || len(rots) != 0 {
+		t.Errorf("expected no rotations, got %v (%v)", rots, err)
+	}
+
+	err = w.RotateTo(sealed)
+	if !errors.Is(err, ErrRotateFailed) || !errors.Is(err, fs.ErrExist) {
+		t.Errorf("expected ErrRotateFailed wrapping fs.ErrExist, got %v", err)
+	}
+	if _, err := w.WriteString("still logging\n"); err != nil {
+		t.Errorf("Write after a refused RotateTo failed: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Rotation describes a rotated log file.
+type Rotation struct {
+	Name       string    // file name within the log directory
+	Path       string    // path of the file, the directory joined with Name
+	Time       time.Time // rotation time parsed from Name; zero if the naming strategy doesn't recognize it
+	ModTime    time.Time // time the file was last modified
+	Size       int64     // size in bytes, compressed size for compressed files
+	Compressed bool      // whether the file is gzip compressed
+	Signed     bool      // whether a signature sidecar exists
+}
+
+// ListRotations returns the rotated files in the Writer's directory, oldest
+// first. Bundles created by WithBundling aren't included.
+func (w *Writer) ListRotations() ([]Rotation, error) {
+	if w.ioMu != nil {
+		w.ioMu.Lock()
+		defer w.ioMu.Unlock()
+	}
+	names, err := w.logFiles()
+	if err != nil {
+		return nil, err
+	}
+	naming := w.namer()
+	var rotations []Rotation
+	for _, name := range names {
+		if isActiveName(name) {
+			continue
+		}
+		path := filepath.Join(w.dirPath, name)
+		fi, err := w.fs.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		signed, err := fileExists(w.fs, path+SignatureExt)
+		if err != nil {
+			return nil, err
+		}
+		t, _, _ := naming.Parse(trimLogExt(name))
+		rotations = append(rotations, Rotation{
+			Name:       name,
+			Path:       path,
+			Time:       t,
+			ModTime:    fi.ModTime(),
+			Size:       fi.Size(),
+			Compressed: strings.HasSuffix(name, ".gz"),
+			Signed:     signed,
+		})
+	}
+	return rotations, nil
+}
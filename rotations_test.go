@@ -0,0 +1,43 @@
+package rlog
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestListRotations verifies that rotations are listed oldest first with
+// metadata parsed from their names.
+func TestListRotations(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	w, _, err := NewMemory(WithMaxFileSize(4), WithUTC())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	for i := 0; i < 3; i++ {
+		fmt.Fprintf(w, "%d\n", i)
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		clock = clock.Add(time.Minute)
+	}
+	rotations, err := w.ListRotations()
+	if err != nil {
+		t.Fatalf("ListRotations failed: %v", err)
+	}
+	if len(rotations) != 2 {
+		t.Fatalf("expected 2 rotations, got %d", len(rotations))
+	}
+	for i, r := range rotations {
+		if want := time.Date(2024, 1, 1, 12, i+1, 0, 0, time.UTC); !r.Time.Equal(want) {
+			t.Errorf("rotation %d: time mismatch: got %v, want %v", i, r.Time, want)
+		}
+		if r.Size != 2 || r.Compressed || r.Signed {
+			t.Errorf("rotation %d: unexpected metadata %+v", i, r)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+// Package s3 implements an rlog.Uploader for Amazon S3 and S3-compatible
+// object stores such as MinIO, Ceph, or Cloudflare R2. Requests are signed
+// with AWS Signature Version 4 using only the standard library.
+//
+// Usage:
+//
+//	up, err := s3.New(s3.Config{
+//		Endpoint:        "https://s3.us-east-1.amazonaws.com",
+//		Region:          "us-east-1",
+//		Bucket:          "my-logs",
+//		Prefix:          "web-1/",
+//		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+//		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	w, err := rlog.New("logs", rlog.WithUploader(up))
+package s3
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Config describes the bucket rotated files are uploaded to.
+type Config struct {
+	Endpoint        string // base URL, e.g. "https://s3.us-east-1.amazonaws.com"
+	Region          string // signing region, e.g. "us-east-1"
+	Bucket          string
+	Prefix          string // prepended to each file's base name to form its key
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string       // optional, for temporary credentials
+	VirtualHosted   bool         // address the bucket as a subdomain instead of a path
+	Client          *http.Client // defaults to http.DefaultClient
+}
+
+// Uploader uploads files to an S3-compatible bucket with PUT Object requests.
+type Uploader struct {
+	cfg  Config
+	base *url.URL
+}
+
+// New returns an Uploader for cfg.
+func New(cfg Config) (*Uploader, error) {
+	if cfg.Endpoint == "" || cfg.Region == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3: endpoint, region, and bucket are required")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3: credentials are required")
+	}
+	base, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("s3: invalid endpoint: %w", err)
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("s3: endpoint %q must be an absolute URL", cfg.Endpoint)
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	return &Uploader{cfg: cfg, base: base}, nil
+}
+
+// Upload stores the file at path under the key Prefix + base name of path.
+func (u *Uploader) Upload(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.objectURL(u.cfg.Prefix+filepath.Base(path)), f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = fi.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if u.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", u.cfg.SessionToken)
+	}
+	sign(req, hex.EncodeToString(h.Sum(nil)), u.cfg.AccessKeyID, u.cfg.SecretAccessKey, u.cfg.Region, time.Now())
+
+	resp, err := u.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3: upload of %q failed: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// objectURL returns the URL of key in the configured bucket.
+func (u *Uploader) objectURL(key string) string {
+	o := *u.base
+	if u.cfg.VirtualHosted {
+		o.Host = u.cfg.Bucket + "." + o.Host
+		o.Path = strings.TrimSuffix(o.Path, "/") + "/" + key
+	} else {
+		o.Path = strings.TrimSuffix(o.Path, "/") + "/" + u.cfg.Bucket + "/" + key
+	}
+	o.RawPath = ""
+	return o.String()
+}
+
+// sign adds AWS Signature Version 4 headers to req. Every header already set
+// on req is signed, along with the host.
+func sign(req *http.Request, payloadHash, accessKey, secretKey, region string, t time.Time) {
+	t = t.UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonHeaders strings.Builder
+	for _, k := range names {
+		canonHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	crHash := sha256.Sum256([]byte(canonRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(crHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+// canonicalQuery encodes q with sorted keys as required by SigV4.
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		vals := append([]string{}, q[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes s per SigV4, leaving unreserved characters as is.
+// Slashes are encoded only if encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
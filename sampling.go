@@ -0,0 +1,30 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+// WithSampling keeps only the given fraction of writes, discarding the rest,
+// so the output of chatty producers can be thinned without changing them.
+// Sampling is deterministic and evenly spaced: a fraction of 0.25 keeps every
+// fourth write, starting with the first. Each write is kept or discarded whole,
+// so it's most useful when every write is a complete line, as with log.Logger.
+//
+// Discarded writes are counted in Stats and still reported as successful.
+// fraction must be in (0, 1].
+func WithSampling(fraction float64) Option {
+	return func(w *Writer) {
+		w.sampleRate = fraction
+		w.sampleAcc = 1 - fraction // keep the first write
+	}
+}
+
+// sample reports whether the current write should be kept.
+func (w *Writer) sample() bool {
+	w.sampleAcc += w.sampleRate
+	if w.sampleAcc >= 1 {
+		w.sampleAcc--
+		return true
+	}
+	return false
+}
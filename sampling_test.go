@@ -0,0 +1,34 @@
+package rlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSampling verifies that sampling keeps evenly spaced writes and counts the rest.
+func TestSampling(t *testing.T) {
+	dirPath := t.TempDir()
+	w, err := New(dirPath, WithSampling(1.0/3))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for _, s := range []string{"a\n", "b\n", "c\n", "d\n", "e\n", "f\n", "g\n"} {
+		if _, err := w.WriteString(s); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dirPath, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if want := "a\nd\ng\n"; string(data) != want {
+		t.Errorf("content mismatch: got %q, want %q", data, want)
+	}
+	if got := w.Stats().SampledWrites; got != 4 {
+		t.Errorf("sampled writes mismatch: got %d, want 4", got)
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/Data-Corruption/rlog/sentryrlog
+
+go 1.22.4
+
+require (
+	github.com/Data-Corruption/rlog v0.0.0
+	github.com/getsentry/sentry-go v0.29.1
+)
+
+require (
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/Data-Corruption/rlog => ../
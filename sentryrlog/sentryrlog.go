@@ -0,0 +1,89 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+// Package sentryrlog reports logger entries at error level and above to
+// Sentry, so crashes are visible without a second logging stack. It's the
+// reference logger.Reporter, and lives in its own module to keep rlog itself
+// free of dependencies.
+//
+// Each entry becomes a Sentry event with the entry's message, time, and level.
+// Fields are recorded as extra data, the caller under the "caller" tag, and
+// the name of a named logger as the event's logger.
+//
+// Usage:
+//
+//	if err := sentry.Init(sentry.ClientOptions{Dsn: dsn}); err != nil {
+//		log.Fatal(err)
+//	}
+//	l.AddHook(logger.NewReporterHook(sentryrlog.New(nil, 2*time.Second), 20, 5*time.Second))
+package sentryrlog
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Data-Corruption/rlog/logger"
+	"github.com/getsentry/sentry-go"
+)
+
+// Reporter is a logger.Reporter that sends entries to Sentry.
+type Reporter struct {
+	hub     *sentry.Hub
+	timeout time.Duration
+}
+
+// New returns a Reporter that captures events with hub, or the current hub if
+// nil, and waits up to timeout for each batch to be delivered.
+func New(hub *sentry.Hub, timeout time.Duration) *Reporter {
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	return &Reporter{hub: hub, timeout: timeout}
+}
+
+// Report implements logger.Reporter.
+func (r *Reporter) Report(entries []logger.Entry) error {
+	for i := range entries {
+		r.hub.CaptureEvent(event(&entries[i]))
+	}
+	if !r.hub.Flush(r.timeout) {
+		return errors.New("sentryrlog: timed out delivering events")
+	}
+	return nil
+}
+
+// event converts an entry to a Sentry event.
+func event(e *logger.Entry) *sentry.Event {
+	ev := sentry.NewEvent()
+	ev.Message = e.Msg
+	ev.Timestamp = e.Time
+	ev.Level = sentry.LevelError
+	if e.Level == "panic" || e.Level == "fatal" {
+		ev.Level = sentry.LevelFatal
+	}
+	if e.Caller != "" {
+		ev.Tags["caller"] = e.Caller
+	}
+	kv := e.Fields
+	for len(kv) > 0 {
+		key, ok := kv[0].(string)
+		if !ok || len(kv) == 1 {
+			key = "!BADKEY"
+		} else {
+			kv = kv[1:]
+		}
+		value := kv[0]
+		kv = kv[1:]
+		if key == "logger" {
+			ev.Logger = fmt.Sprint(value)
+			continue
+		}
+		if err, ok := value.(error); ok {
+			value = err.Error() // errors usually have no exported fields to marshal
+		}
+		ev.Extra[key] = value
+	}
+	return ev
+}
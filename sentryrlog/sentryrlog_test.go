@@ -0,0 +1,73 @@
+package sentryrlog
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Data-Corruption/rlog/logger"
+	"github.com/getsentry/sentry-go"
+)
+
+// recordingTransport keeps the events it's sent.
+type recordingTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *recordingTransport) Configure(sentry.ClientOptions) {}
+
+func (t *recordingTransport) Flush(time.Duration) bool { return true }
+
+func (t *recordingTransport) SendEvent(e *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, e)
+}
+
+// TestReporter verifies that error entries reach Sentry as events with their
+// message, level, fields, caller, and logger name, and that Fatal delivers its
+// entry before exiting.
+func TestReporter(t *testing.T) {
+	transport := &recordingTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	exited := false
+	l, err := logger.New(t.TempDir(), "info", logger.WithExitFunc(func(int) { exited = true }))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer l.Close()
+	l.AddHook(logger.NewReporterHook(New(hub, time.Second), 10, time.Hour))
+
+	l.Info("not reported")
+	l.Named("db").Errorw("query failed", "table", "users", "err", errors.New("timeout"))
+	l.Fatal("giving up")
+	if !exited {
+		t.Fatal("expected Fatal to exit")
+	}
+
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	if len(transport.events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(transport.events))
+	}
+	ev := transport.events[0]
+	if ev.Message != "query failed" || ev.Level != sentry.LevelError || ev.Logger != "db" {
+		t.Errorf("unexpected event: message %q, level %q, logger %q", ev.Message, ev.Level, ev.Logger)
+	}
+	if ev.Extra["table"] != "users" || ev.Extra["err"] != "timeout" {
+		t.Errorf("unexpected extra: %v", ev.Extra)
+	}
+	if ev.Tags["caller"] == "" {
+		t.Error("expected a caller tag")
+	}
+	if ev := transport.events[1]; ev.Message != "giving up" || ev.Level != sentry.LevelFatal {
+		t.Errorf("unexpected fatal event: message %q, level %q", ev.Message, ev.Level)
+	}
+}
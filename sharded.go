@@ -0,0 +1,183 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ShardedWriter spreads writes across several independently locked buffers to
+// reduce lock contention when many goroutines log at once. Shards are merged
+// into an underlying Writer when they fill up, once their oldest data is older
+// than the Writer's maximum buffer age, and on Flush and Close, so a single
+// Write call is never split, but writes from different goroutines may
+// reach the file in a different order than they were made.
+//
+// ShardedWriter is safe for concurrent use.
+type ShardedWriter struct {
+	w      *Writer
+	shards []shard
+	next   atomic.Uint32
+	limit  int
+	maxAge time.Duration
+
+	stop     chan struct{} // closed by Close to end mergeLoop
+	done     chan struct{}
+	stopOnce sync.Once
+	closed   atomic.Bool // set by Close before the final merge
+}
+
+// shard is a single buffer, padded so neighbouring shards don't share a cache line.
+type shard struct {
+	mu    sync.Mutex
+	buf   []byte
+	since time.Time // time of the oldest write in buf
+	_     [64]byte
+}
+
+// NewSharded creates a ShardedWriter with n shards in front of a Writer for
+// dirPath. If n is not positive, runtime.GOMAXPROCS(0) shards are used. opts
+// configure the underlying Writer; WithNoSync is ignored. Each shard holds
+// up to the Writer's maximum buffer size, for up to its maximum buffer age,
+// before it's merged.
+func NewSharded(dirPath string, n int, opts ...Option) (*ShardedWriter, error) {
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	w, err := New(dirPath, append(append([]Option{}, opts...), withLocks())...)
+	if err != nil {
+		return nil, err
+	}
+	s := &ShardedWriter{
+		w:      w,
+		shards: make([]shard, n),
+		limit:  w.maxBufSize,
+		maxAge: w.maxBufAge,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	for i := range s.shards {
+		s.shards[i].buf = make([]byte, 0, s.limit)
+	}
+	go s.mergeLoop()
+	return s, nil
+}
+
+// Write appends p to one of the shards, preferring one that isn't in use.
+// Writes smaller than the shard size are only merged into the underlying
+// Writer, and thus flushed, once their shard fills up or ages past the
+// maximum buffer age, or Flush is called. After Close, it returns ErrClosed.
+func (s *ShardedWriter) Write(p []byte) (int, error) {
+	sh := s.acquire()
+	defer sh.mu.Unlock()
+	if s.closed.Load() {
+		return 0, ErrClosed // checked under the shard lock, so Close merges earlier writes
+	}
+	t := now()
+	if len(sh.buf) == 0 {
+		sh.since = t
+	}
+	sh.buf = append(sh.buf, p...)
+	if len(sh.buf) >= s.limit || t.Sub(sh.since) >= s.maxAge {
+		if err := s.merge(sh); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// WriteString is a convenience method that wraps Write() for string data.
+func (s *ShardedWriter) WriteString(str string) (int, error) {
+	return s.Write([]byte(str))
+}
+
+// Flush merges every shard into the underlying Writer and flushes it.
+func (s *ShardedWriter) Flush() error {
+	if err := s.mergeAll(); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// Close merges every shard and closes the underlying Writer.
+func (s *ShardedWriter) Close() error {
+	s.closed.Store(true)
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+	if err := s.mergeAll(); err != nil && err != ErrClosed {
+		return err
+	}
+	return s.w.Close()
+}
+
+// acquire returns a locked shard. Starting from a rotating hint, it takes the
+// first free shard and only blocks if all of them are busy.
+func (s *ShardedWriter) acquire() *shard {
+	n := uint32(len(s.shards))
+	start := s.next.Add(1)
+	for i := uint32(0); i < n; i++ {
+		sh := &s.shards[(start+i)%n]
+		if sh.mu.TryLock() {
+			return sh
+		}
+	}
+	sh := &s.shards[start%n]
+	sh.mu.Lock()
+	return sh
+}
+
+// merge moves sh's contents into the underlying Writer. sh must be locked.
+func (s *ShardedWriter) merge(sh *shard) error {
+	if len(sh.buf) == 0 {
+		return nil
+	}
+	_, err := s.w.Write(sh.buf)
+	sh.buf = sh.buf[:0]
+	return err
+}
+
+func (s *ShardedWriter) mergeAll() error {
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		err := s.merge(sh)
+		sh.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeLoop merges shards whose oldest data exceeds maxAge, so data written
+// by a quiet process still reaches the file, until Close is called.
+func (s *ShardedWriter) mergeLoop() {
+	defer close(s.done)
+	t := time.NewTicker(s.maxAge)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-t.C:
+		}
+		merged := false
+		for i := range s.shards {
+			sh := &s.shards[i]
+			sh.mu.Lock()
+			if len(sh.buf) > 0 && now().Sub(sh.since) >= s.maxAge {
+				s.merge(sh) // errors resurface on the next Write, Flush, or Close
+				merged = true
+			}
+			sh.mu.Unlock()
+		}
+		if merged {
+			s.w.Flush()
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package rlog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestShardedWriter verifies that concurrent writes all reach the file intact.
+func TestShardedWriter(t *testing.T) {
+	dirPath := t.TempDir()
+	s, err := NewSharded(dirPath, 4, WithMaxBufSize(64))
+	if err != nil {
+		t.Fatalf("failed to create ShardedWriter: %v", err)
+	}
+	const goroutines, writes = 16, 200
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < writes; i++ {
+				if _, err := fmt.Fprintf(s, "g%d-%d\n", g, i); err != nil {
+					t.Errorf("write failed: %v", err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := s.WriteString("late\n"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write after Close: got %v, want ErrClosed", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dirPath, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, line := range bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n")) {
+		seen[string(line)] = true
+	}
+	for g := 0; g < goroutines; g++ {
+		for i := 0; i < writes; i++ {
+			if line := fmt.Sprintf("g%d-%d", g, i); !seen[line] {
+				t.Fatalf("missing line %q", line)
+			}
+		}
+	}
+	if len(seen) != goroutines*writes {
+		t.Errorf("line count mismatch: got %d, want %d", len(seen), goroutines*writes)
+	}
+}
+
+// TestShardedWriterMaxAge verifies that a partly filled shard reaches the
+// file once it's older than the maximum buffer age, without further writes.
+func TestShardedWriterMaxAge(t *testing.T) {
+	dirPath := t.TempDir()
+	s, err := NewSharded(dirPath, 4, WithMaxBufAge(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create ShardedWriter: %v", err)
+	}
+	defer s.Close()
+	if _, err := s.WriteString("quiet\n"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(filepath.Join(dirPath, "latest.log"))
+		if err != nil {
+			t.Fatalf("failed to read log file: %v", err)
+		}
+		if string(data) == "quiet\n" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the shard to be merged and flushed, file holds %q", data)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func BenchmarkShardedWriter(b *testing.B) {
+	s, err := NewSharded(b.TempDir(), 0)
+	if err != nil {
+		b.Fatalf("failed to create ShardedWriter: %v", err)
+	}
+	defer s.Close()
+	line := []byte("benchmark log line with a little bit of payload\n")
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Write(line)
+		}
+	})
+}
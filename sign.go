@@ -0,0 +1,117 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// SignatureExt is appended to a rotated file's path to name its detached signature.
+const SignatureExt = ".sig"
+
+// ErrBadSignature is returned by VerifyFile when a signature doesn't match the file.
+var ErrBadSignature = errors.New("signature verification failed")
+
+// signOpts selects Ed25519ph, which signs a SHA-512 digest so files can be
+// streamed rather than loaded into memory.
+var signOpts = &ed25519.Options{Hash: crypto.SHA512}
+
+// WithSigner configures the Writer to sign every rotated file with key. The
+// hex encoded Ed25519ph signature is written to a sidecar file named after the
+// rotated file with SignatureExt appended, and can be checked with VerifyFile
+// or the rlog CLI. Signing happens during rotation; a failure is treated like
+// any other write error.
+func WithSigner(key ed25519.PrivateKey) Option {
+	return func(w *Writer) {
+		w.signer = key
+	}
+}
+
+// signFile writes a detached signature for the file at path.
+func signFile(fsys FS, path string, key ed25519.PrivateKey) error {
+	digest, err := fileDigest(fsys, path)
+	if err != nil {
+		return err
+	}
+	sig, err := key.Sign(nil, digest, signOpts)
+	if err != nil {
+		return err
+	}
+	return writeFile(fsys, path+SignatureExt, hex.AppendEncode(nil, sig), 0o644)
+}
+
+// VerifyFile checks the file at path against its detached signature, read
+// from path with SignatureExt appended, using the public key pub. A signature
+// that doesn't match is reported as an error wrapping ErrBadSignature.
+func VerifyFile(path string, pub ed25519.PublicKey) error {
+	return verifyFile(OSFS{}, path, pub)
+}
+
+// verifyFile is VerifyFile for a file in fsys.
+func verifyFile(fsys FS, path string, pub ed25519.PublicKey) error {
+	if len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("public key must be %d bytes, got %d", ed25519.PublicKeySize, len(pub))
+	}
+	encoded, err := readFile(fsys, path+SignatureExt)
+	if err != nil {
+		return err
+	}
+	sig, err := hex.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil {
+		return fmt.Errorf("malformed signature %q: %w", path+SignatureExt, ErrBadSignature)
+	}
+	digest, err := fileDigest(fsys, path)
+	if err != nil {
+		return err
+	}
+	if err := ed25519.VerifyWithOptions(pub, digest, sig, signOpts); err == nil {
+		return nil
+	}
+	// Files compressed after rotation, e.g. by CompressArchiver, are signed
+	// over their uncompressed contents.
+	if strings.HasSuffix(path, ".gz") {
+		if digest, err := logDigest(fsys, path); err == nil && ed25519.VerifyWithOptions(pub, digest, sig, signOpts) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %w", path, ErrBadSignature)
+}
+
+// logDigest returns the SHA-512 digest of the decompressed log file at path.
+func logDigest(fsys FS, path string) ([]byte, error) {
+	rc, err := openLog(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	h := sha512.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// fileDigest returns the SHA-512 digest of the file at path.
+func fileDigest(fsys FS, path string) ([]byte, error) {
+	f, err := fsys.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha512.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,54 @@
+package rlog
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSigner verifies that rotated files are signed and that modifications are detected.
+func TestSigner(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tempDir := t.TempDir()
+	w, err := New(tempDir, WithSigner(priv), WithMaxFileSize(10))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for _, msg := range []string{"abcdef", "ghijkl"} { // second flush rotates
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(tempDir, "*.log"+SignatureExt))
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("expected one signature file, got %v (err %v)", matches, err)
+	}
+	rotated := strings.TrimSuffix(matches[0], SignatureExt)
+	if err := VerifyFile(rotated, pub); err != nil {
+		t.Fatalf("VerifyFile failed on untampered file: %v", err)
+	}
+
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	if err := VerifyFile(rotated, otherPub); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature for wrong key, got %v", err)
+	}
+	if err := os.WriteFile(rotated, []byte("abcdeX"), 0o644); err != nil {
+		t.Fatalf("failed to tamper with file: %v", err)
+	}
+	if err := VerifyFile(rotated, pub); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature after tampering, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// raise re-delivers sig to the current process. It's a variable so tests can
+// observe it without terminating the test binary.
+var raise = func(sig os.Signal) {
+	p, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = p.Signal(sig)
+	}
+	if err != nil {
+		os.Exit(1) // re-raising isn't supported for sig on this platform
+	}
+}
+
+// InstallSignalHandler flushes and closes w when the process receives one of
+// sigs, defaulting to os.Interrupt and syscall.SIGTERM when none are given.
+//
+// After closing w, the handler stops intercepting sigs and re-raises the signal
+// so its default action, usually termination, still takes place. Where a signal
+// can't be re-raised (e.g. os.Interrupt on Windows) the process exits with
+// status 1. Applications that handle sigs themselves via signal.Notify will see
+// the signal a second time.
+//
+// A failure to close w is reported as a flush failure, through the error
+// handler and LastError. Since the handler closes w from its own goroutine, w
+// must not be created with WithNoSync. The returned function uninstalls the
+// handler without closing w.
+func InstallSignalHandler(w *Writer, sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+	go func() {
+		select {
+		case sig := <-ch:
+			if err := w.Close(); err != nil {
+				w.reportError(fmt.Errorf("failed to close log writer on %v: %w", sig, err))
+			}
+			signal.Stop(ch)
+			raise(sig)
+		case <-done:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
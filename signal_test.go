@@ -0,0 +1,89 @@
+//go:build unix
+
+package rlog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestInstallSignalHandler verifies that a received signal flushes and closes the Writer
+// before the signal is re-raised.
+func TestInstallSignalHandler(t *testing.T) {
+	raised := make(chan os.Signal, 1)
+	origRaise := raise
+	raise = func(sig os.Signal) { raised <- sig }
+	defer func() { raise = origRaise }()
+
+	tempDir := t.TempDir()
+	w, err := New(tempDir)
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	stop := InstallSignalHandler(w, syscall.SIGUSR1)
+	defer stop()
+
+	message := "before signal\n"
+	if _, err := w.Write([]byte(message)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("failed to send signal: %v", err)
+	}
+	select {
+	case sig := <-raised:
+		if sig != syscall.SIGUSR1 {
+			t.Errorf("re-raised %v, want %v", sig, syscall.SIGUSR1)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("signal was not handled")
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "latest.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if string(data) != message {
+		t.Errorf("log content mismatch: got %q, want %q", string(data), message)
+	}
+}
+
+// TestInstallSignalHandlerCloseError verifies that a failure to close the
+// Writer is reported through the error handler and LastError.
+func TestInstallSignalHandlerCloseError(t *testing.T) {
+	raised := make(chan os.Signal, 1)
+	origRaise := raise
+	raise = func(sig os.Signal) { raised <- sig }
+	defer func() { raise = origRaise }()
+
+	fsys := &flakyFS{Memory: NewMemoryFS()}
+	handled := make(chan error, 4)
+	w, err := New(".", WithFS(fsys), WithErrorHandler(func(err error) { handled <- err }))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	w.WriteString("lost\n")
+	fsys.down = true // before the handler goroutine starts, which then reads it
+	stop := InstallSignalHandler(w, syscall.SIGUSR1)
+	defer stop()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("failed to send signal: %v", err)
+	}
+	select {
+	case <-raised:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("signal was not handled")
+	}
+	if err := w.LastError(); err == nil || !strings.Contains(err.Error(), "failed to close log writer on") || !errors.Is(err, syscall.EIO) {
+		t.Errorf("unexpected LastError %v", err)
+	}
+	if len(handled) == 0 {
+		t.Errorf("expected the error handler to be called")
+	}
+}
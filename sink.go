@@ -0,0 +1,50 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"fmt"
+	"io"
+)
+
+// Sink receives a copy of every chunk the Writer flushes to disk, e.g. to
+// forward logs to a remote collector alongside the local file. See the netsink
+// and loki packages for implementations.
+//
+// Write is called on the flush path and must not block on I/O; sinks are
+// expected to queue data internally. Write must not retain p. Sink errors
+// don't affect the Writer; they're counted in Stats, kept by LastError, and
+// passed to the error handler. Close is called by
+// the Writer's Close after the final flush.
+type Sink interface {
+	io.WriteCloser
+}
+
+// WithSink adds s to the Writer's sinks. It may be given more than once.
+func WithSink(s Sink) Option {
+	return func(w *Writer) {
+		w.sinks = append(w.sinks, s)
+	}
+}
+
+// writeSinks hands p to every sink.
+func (w *Writer) writeSinks(p []byte) {
+	for _, s := range w.sinks {
+		if _, err := s.Write(p); err != nil {
+			w.fail(&w.stats.sinkFailures, fmt.Errorf("sink write failed: %w", err))
+		}
+	}
+}
+
+// closeSinks closes every sink, returning the first error.
+func (w *Writer) closeSinks() error {
+	var first error
+	for _, s := range w.sinks {
+		if err := s.Close(); err != nil && first == nil {
+			first = fmt.Errorf("failed to close sink: %w", err)
+		}
+	}
+	return first
+}
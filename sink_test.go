@@ -0,0 +1,38 @@
+package rlog
+
+import (
+	"bytes"
+	"testing"
+)
+
+// bufSink is a Sink collecting everything written to it.
+type bufSink struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (s *bufSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+// TestSink verifies that sinks receive flushed data and are closed with the Writer.
+func TestSink(t *testing.T) {
+	s := &bufSink{}
+	w, err := New(t.TempDir(), WithSink(s))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	if _, err := w.Write([]byte("to the sink\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if s.Len() != 0 {
+		t.Errorf("expected sink to receive data only on flush, got %q", s.String())
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if s.String() != "to the sink\n" || !s.closed {
+		t.Errorf("unexpected sink state: content %q, closed %v", s.String(), s.closed)
+	}
+}
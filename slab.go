@@ -0,0 +1,67 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// slabSize is the size of the slabs copies are carved from. Copies larger than
+// a quarter slab are allocated on their own to keep waste low.
+const slabSize = 64 * 1024
+
+// slabPool recycles slabs once every copy carved from them is released.
+var slabPool = sync.Pool{New: func() any { return &slab{buf: make([]byte, slabSize)} }}
+
+// slabAllocator copies small byte slices into shared slabs, replacing one
+// allocation per copy with one per slab. Each copy holds a reference to its
+// slab until released, and a slab that's been filled and had every copy
+// released goes back to slabPool, so a steady stream of copies allocates
+// nothing. It's safe for concurrent use.
+type slabAllocator struct {
+	mu  sync.Mutex
+	cur *slab
+}
+
+type slab struct {
+	buf  []byte
+	off  int          // bytes handed out, guarded by the allocator's mu
+	refs atomic.Int64 // unreleased copies, plus one while the allocator's current slab
+}
+
+// copy returns a copy of p and the slab it was carved from, which must be
+// released once the copy is no longer needed. Copies too large for a slab
+// are allocated on their own and come with a nil slab.
+func (a *slabAllocator) copy(p []byte) ([]byte, *slab) {
+	n := len(p)
+	if n > slabSize/4 {
+		return append([]byte(nil), p...), nil
+	}
+	a.mu.Lock()
+	s, full := a.cur, (*slab)(nil)
+	if s == nil || s.off+n > len(s.buf) {
+		full = s
+		s = slabPool.Get().(*slab)
+		s.off = 0
+		s.refs.Store(1)
+		a.cur = s
+	}
+	dst := s.buf[s.off : s.off+n : s.off+n] // capped so appends can't spill into neighbors
+	s.off += n
+	s.refs.Add(1)
+	a.mu.Unlock()
+	full.release()
+	copy(dst, p)
+	return dst, s
+}
+
+// release drops a reference to s, recycling it once none are left. It does
+// nothing for a nil s.
+func (s *slab) release() {
+	if s != nil && s.refs.Add(-1) == 0 {
+		slabPool.Put(s)
+	}
+}
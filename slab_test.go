@@ -0,0 +1,46 @@
+package rlog
+
+import "testing"
+
+// TestSlabAllocator verifies that copies are carved from shared slabs and that
+// a filled slab is recycled once every copy from it is released.
+func TestSlabAllocator(t *testing.T) {
+	var a slabAllocator
+	p := make([]byte, slabSize/4)
+	for i := range p {
+		p[i] = byte(i)
+	}
+	var copies [4][]byte
+	var first *slab
+	for i := range copies {
+		var s *slab
+		copies[i], s = a.copy(p)
+		if first == nil {
+			first = s
+		} else if s != first {
+			t.Fatalf("copy %d: expected the first slab to be shared", i)
+		}
+		if string(copies[i]) != string(p) || cap(copies[i]) != len(p) {
+			t.Fatalf("copy %d: expected an exact, capped copy", i)
+		}
+	}
+	_, next := a.copy(p[:1]) // the first slab is full
+	if next == first {
+		t.Fatalf("expected a new slab once the first is full")
+	}
+	for range copies {
+		if n := first.refs.Load(); n == 0 {
+			t.Fatalf("slab recycled with copies outstanding")
+		}
+		first.release()
+	}
+	if n := first.refs.Load(); n != 0 {
+		t.Errorf("expected no references to the full slab after release, got %d", n)
+	}
+	if n := next.refs.Load(); n != 2 { // the allocator's and the copy's
+		t.Errorf("expected 2 references to the current slab, got %d", n)
+	}
+	if big, s := a.copy(make([]byte, slabSize)); s != nil || len(big) != slabSize {
+		t.Errorf("expected a large copy to be allocated on its own")
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+// Package slogrlog implements a log/slog Handler that writes records through
+// an *rlog.Writer, so slog users get rlog's buffering, rotation, and retention
+// underneath.
+//
+// Records are rendered by slog's own text or JSON handler, one line per
+// record, with levels, attributes, and groups as usual. Records at or above
+// Options.FlushLevel are flushed to disk before Handle returns, so the lines
+// that matter most survive a crash even with a large buffer.
+//
+// Usage:
+//
+//	w, err := rlog.New("logs")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer w.Close()
+//	logger := slog.New(slogrlog.New(w, &slogrlog.Options{JSON: true}))
+//	logger.Info("started", "port", 8080)
+package slogrlog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Data-Corruption/rlog"
+)
+
+// Options configures a Handler. The zero value logs at slog.LevelInfo and
+// above as text and flushes at slog.LevelError and above.
+type Options struct {
+	// Level is the minimum level logged, slog.LevelInfo if nil.
+	Level slog.Leveler
+
+	// FlushLevel is the minimum level flushed immediately, slog.LevelError if
+	// nil. Other records are flushed by the Writer's usual buffer limits.
+	FlushLevel slog.Leveler
+
+	// AddSource adds the caller's file and line to every record.
+	AddSource bool
+
+	// JSON selects slog's JSON format instead of its text format.
+	JSON bool
+
+	// ReplaceAttr rewrites or drops attributes, as in slog.HandlerOptions.
+	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+}
+
+// Handler is a slog.Handler that writes to an *rlog.Writer. The Writer must
+// not be created with rlog.WithNoSync, as a Handler may be used concurrently.
+type Handler struct {
+	h          slog.Handler
+	w          *rlog.Writer
+	flushLevel slog.Leveler
+}
+
+// New returns a Handler writing to w. opts may be nil for the defaults.
+func New(w *rlog.Writer, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+	ho := &slog.HandlerOptions{Level: opts.Level, AddSource: opts.AddSource, ReplaceAttr: opts.ReplaceAttr}
+	var h slog.Handler
+	if opts.JSON {
+		h = slog.NewJSONHandler(w, ho)
+	} else {
+		h = slog.NewTextHandler(w, ho)
+	}
+	flushLevel := opts.FlushLevel
+	if flushLevel == nil {
+		flushLevel = slog.LevelError
+	}
+	return &Handler{h: h, w: w, flushLevel: flushLevel}
+}
+
+// Enabled reports whether records at level are logged.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.h.Enabled(ctx, level)
+}
+
+// Handle writes r to the Writer as one line, flushing it if r is at or above
+// the flush level.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if err := h.h.Handle(ctx, r); err != nil {
+		return err
+	}
+	if r.Level >= h.flushLevel.Level() {
+		return h.w.Flush()
+	}
+	return nil
+}
+
+// WithAttrs returns a Handler that adds attrs to every record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{h: h.h.WithAttrs(attrs), w: h.w, flushLevel: h.flushLevel}
+}
+
+// WithGroup returns a Handler that qualifies later attributes with name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{h: h.h.WithGroup(name), w: h.w, flushLevel: h.flushLevel}
+}
@@ -0,0 +1,48 @@
+package slogrlog
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Data-Corruption/rlog"
+)
+
+// TestHandler verifies that records are written with their attributes and
+// groups, and that only records at the flush level are flushed right away.
+func TestHandler(t *testing.T) {
+	w, m, err := rlog.NewMemory()
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	logger := slog.New(New(w, &Options{JSON: true, Level: slog.LevelDebug}))
+	logger = logger.With("app", "api").WithGroup("req")
+
+	logger.Debug("hidden until flushed", "id", 1)
+	if got := m.Files()["latest.log"]; len(got) != 0 {
+		t.Fatalf("expected debug records to stay buffered, got %q", got)
+	}
+	logger.Error("failed", "id", 2)
+	lines := strings.Split(strings.TrimSpace(string(m.Files()["latest.log"])), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected both records flushed by the error, got %q", lines)
+	}
+	var rec struct {
+		Level string
+		Msg   string
+		App   string
+		Req   struct{ ID int }
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatalf("failed to decode %q: %v", lines[1], err)
+	}
+	if rec.Level != "ERROR" || rec.Msg != "failed" || rec.App != "api" || rec.Req.ID != 2 {
+		t.Errorf("unexpected record %q", lines[1])
+	}
+	if New(w, nil).Enabled(context.Background(), slog.LevelDebug) {
+		t.Errorf("expected debug to be disabled by default")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	DefaultSnapshotFiles = 10
+	DefaultSnapshotBytes = 100 * 1024 * 1024 // 100 MB
+)
+
+// WithSnapshotLimits bounds the rotated files included by Snapshot to the
+// newest maxFiles whose sizes add up to at most maxBytes. The active file is
+// always included. The defaults are DefaultSnapshotFiles and
+// DefaultSnapshotBytes.
+func WithSnapshotLimits(maxFiles int, maxBytes int64) Option {
+	return func(w *Writer) {
+		w.snapshotFiles = maxFiles
+		w.snapshotBytes = maxBytes
+	}
+}
+
+// snapshotFile is a file opened for Snapshot, read up to its size when opened.
+type snapshotFile struct {
+	name string
+	f    File
+	fi   os.FileInfo
+}
+
+// Snapshot flushes the Writer and writes a zip archive of the active file and
+// the most recent rotated files (see WithSnapshotLimits) to dst, e.g. for a
+// support bundle. Logging is only paused while the files are opened; they're
+// streamed afterwards, so the active file is included up to the point of the
+// flush. Compressed files are stored as is. The archive is written even if the
+// flush fails, e.g. after Close or a disk error, so that it covers whatever
+// reached the disk.
+func (w *Writer) Snapshot(ctx context.Context, dst io.Writer) error {
+	if err := w.FlushContext(ctx); err != nil && ctx.Err() != nil {
+		return err
+	}
+	files, err := w.openSnapshot()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, sf := range files {
+			sf.f.Close()
+		}
+	}()
+	zw := zip.NewWriter(dst)
+	for _, sf := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hdr := &zip.FileHeader{Name: sf.name, Method: zip.Deflate, Modified: sf.fi.ModTime()}
+		if strings.HasSuffix(sf.name, ".gz") {
+			hdr.Method = zip.Store
+		}
+		hdr.SetMode(sf.fi.Mode())
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(fw, ctxReader{ctx, io.LimitReader(sf.f, sf.fi.Size())}); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", sf.name, err)
+		}
+	}
+	return zw.Close()
+}
+
+// openSnapshot opens the files to include in a snapshot, oldest first, holding
+// ioMu so that none is rotated midway.
+func (w *Writer) openSnapshot() (files []snapshotFile, err error) {
+	if w.ioMu != nil {
+		w.ioMu.Lock()
+		defer w.ioMu.Unlock()
+	}
+	defer func() {
+		if err != nil {
+			for _, sf := range files {
+				sf.f.Close()
+			}
+			files = nil
+		}
+	}()
+	names, err := w.logFiles()
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	rotated := 0
+	for i := len(names) - 1; i >= 0; i-- {
+		active := isActiveName(names[i])
+		if !active && rotated >= w.snapshotFiles {
+			break
+		}
+		f, err := w.fs.OpenFile(filepath.Join(w.dirPath, names[i]), os.O_RDONLY, 0)
+		if errors.Is(err, os.ErrNotExist) && active {
+			continue // e.g. the other active name after toggling compression
+		} else if err != nil {
+			return files, err
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return files, err
+		}
+		if !active {
+			if total+fi.Size() > w.snapshotBytes {
+				f.Close()
+				break
+			}
+			total += fi.Size()
+			rotated++
+		}
+		files = append(files, snapshotFile{names[i], f, fi})
+	}
+	for i, j := 0, len(files)-1; i < j; i, j = i+1, j-1 {
+		files[i], files[j] = files[j], files[i]
+	}
+	return files, nil
+}
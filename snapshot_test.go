@@ -0,0 +1,66 @@
+package rlog
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+// TestSnapshot verifies that Snapshot zips the active file and the newest
+// rotated files within the limits, including unflushed data.
+func TestSnapshot(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	w, _, err := NewMemory(WithMaxFileSize(4), WithSnapshotLimits(2, 1024))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(w, "%d\n", i)
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		clock = clock.Add(time.Minute)
+	}
+	fmt.Fprintf(w, "buffered\n")
+
+	var buf bytes.Buffer
+	if err := w.Snapshot(context.Background(), &buf); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to read zip: %v", err)
+	}
+	var got []string
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		got = append(got, f.Name+": "+string(data))
+	}
+	want := []string{
+		"20240101-120400.000000.log: 3\n",
+		"20240101-120500.000000.log: 4\n",
+		"latest.log: buffered\n",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected entries %q, got %q", want, got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := w.Snapshot(ctx, io.Discard); err == nil {
+		t.Errorf("expected an error for a canceled context")
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"bytes"
+	"time"
+)
+
+// WithTimestamps prefixes every line with the time it was written, formatted
+// with layout and followed by a space, or time.RFC3339 if layout is empty.
+// This gives time information to output that has none, e.g. a subprocess's
+// stdout piped through the Writer. Lines split across writes are stamped once,
+// when their first byte arrives. Timestamps use local time, or UTC with
+// WithUTC. With WithDedup, lines are compared before they're stamped.
+func WithTimestamps(layout string) Option {
+	return func(w *Writer) {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		w.stampLayout = layout
+	}
+}
+
+// stamp returns p with a timestamp inserted at the start of each line. The
+// result is only valid until the next call.
+func (w *Writer) stamp(p []byte) []byte {
+	t := now()
+	if w.utc {
+		t = t.UTC()
+	}
+	out := w.stampBuf[:0]
+	for len(p) > 0 {
+		if !w.stampMidLine {
+			out = t.AppendFormat(out, w.stampLayout)
+			out = append(out, ' ')
+			w.stampMidLine = true
+		}
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			out = append(out, p...)
+			break
+		}
+		out = append(out, p[:i+1]...)
+		p = p[i+1:]
+		w.stampMidLine = false
+	}
+	w.stampBuf = out
+	return out
+}
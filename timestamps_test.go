@@ -0,0 +1,29 @@
+package rlog
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTimestamps verifies that each line is stamped once, even when it spans
+// several writes.
+func TestTimestamps(t *testing.T) {
+	clock := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	w, m, err := NewMemory(WithTimestamps("15:04:05"), WithUTC())
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	w.WriteString("one\ntw")
+	clock = clock.Add(time.Second)
+	w.WriteString("o\nthree\n")
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	want := "03:04:05 one\n03:04:05 two\n03:04:06 three\n"
+	if got, _ := m.ReadFile("latest.log"); string(got) != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
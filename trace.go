@@ -0,0 +1,75 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import "time"
+
+// TraceOp identifies an operation reported to a Tracer.
+type TraceOp int
+
+const (
+	TraceFlush  TraceOp = iota + 1 // writing a flushed buffer out to the active file
+	TraceSync                      // syncing the active file to disk
+	TraceRotate                    // rotating the active file, including retention
+)
+
+func (op TraceOp) String() string {
+	switch op {
+	case TraceFlush:
+		return "flush"
+	case TraceSync:
+		return "sync"
+	case TraceRotate:
+		return "rotate"
+	}
+	return "unknown"
+}
+
+// Tracer observes the Writer's I/O operations, e.g. to emit OpenTelemetry
+// spans showing when logging contributes to request latency.
+//
+// Operations are serialized and nest: syncs and rotations usually happen
+// within a flush, so a tracer can keep its open spans on a stack. The methods
+// are called with the Writer's locks held, possibly from a background
+// goroutine, so they must return quickly and not call the Writer's methods.
+type Tracer interface {
+	// TraceStart is called when op begins.
+	TraceStart(op TraceOp)
+	// TraceEnd is called when op finishes, with how long it took and the
+	// error it failed with, if any.
+	TraceEnd(op TraceOp, d time.Duration, err error)
+}
+
+// WithTracer reports flushes, syncs, and rotations to t.
+func WithTracer(t Tracer) Option {
+	return func(w *Writer) {
+		w.tracer = t
+	}
+}
+
+// traceStart reports the start of op to the tracer, if any, returning the
+// time to pass on to traceEnd.
+func (w *Writer) traceStart(op TraceOp) time.Time {
+	if w.tracer == nil {
+		return time.Time{}
+	}
+	w.tracer.TraceStart(op)
+	return time.Now()
+}
+
+// traceEnd reports the end of op, started at start, to the tracer, if any.
+func (w *Writer) traceEnd(op TraceOp, start time.Time, err error) {
+	if w.tracer != nil {
+		w.tracer.TraceEnd(op, time.Since(start), err)
+	}
+}
+
+// syncFile syncs the active file under the retry policy.
+func (w *Writer) syncFile() error {
+	start := w.traceStart(TraceSync)
+	err := w.retryIO(w.file.Sync)
+	w.traceEnd(TraceSync, start, err)
+	return err
+}
@@ -0,0 +1,60 @@
+package rlog
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingTracer records the operations reported to it.
+type recordingTracer struct {
+	ops  []string
+	errs []error
+}
+
+func (r *recordingTracer) TraceStart(op TraceOp) {
+	r.ops = append(r.ops, "start "+op.String())
+}
+
+func (r *recordingTracer) TraceEnd(op TraceOp, d time.Duration, err error) {
+	r.ops = append(r.ops, "end "+op.String())
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%v: %w", op, err))
+	}
+}
+
+// TestTracer verifies that flushes, syncs, and rotations are reported in
+// nesting order, along with their errors.
+func TestTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	fsys := &flakyFS{Memory: NewMemoryFS()}
+	w, err := New(".", WithFS(fsys), WithMaxFileSize(8), WithTracer(tracer))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	for _, s := range []string{"hello\n", "world\n"} {
+		w.WriteString(s)
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	want := []string{
+		"start flush", "start sync", "end sync", "end flush",
+		"start flush", "start rotate", "end rotate", "start sync", "end sync", "end flush",
+	}
+	if !reflect.DeepEqual(tracer.ops, want) {
+		t.Errorf("unexpected trace:\ngot  %v\nwant %v", tracer.ops, want)
+	}
+
+	fsys.full = true
+	w.WriteString("!\n")
+	if err := w.Flush(); err == nil {
+		t.Fatal("expected Flush to fail")
+	}
+	if len(tracer.errs) != 1 || !strings.HasPrefix(tracer.errs[0].Error(), "flush: ") {
+		t.Errorf("expected the failed flush to be traced, got %v", tracer.errs)
+	}
+}
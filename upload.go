@@ -0,0 +1,47 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"context"
+)
+
+// Uploader ships a rotated log file to long-term storage.
+//
+// Upload must return nil only once the file at path is durably stored, as the
+// Writer deletes its local copy afterwards.
+type Uploader interface {
+	Upload(ctx context.Context, path string) error
+}
+
+// WithUploader configures the Writer to upload every rotated file, followed
+// by its signature sidecar if any, with u. Once a file and its sidecar are
+// uploaded, both are deleted locally. Uploads run as the Writer's Archiver, so
+// they happen in the background with the configured workers and retries.
+func WithUploader(u Uploader) Option {
+	return WithArchiver(UploadArchiver(u))
+}
+
+// UploadArchiver returns an Archiver that uploads a rotated file and its
+// signature sidecar with u, then deletes both locally. It can be wrapped by
+// custom Archivers that, e.g., compress files before uploading them.
+func UploadArchiver(u Uploader) Archiver {
+	return ArchiverFunc(func(ctx context.Context, path string) error {
+		if err := u.Upload(ctx, path); err != nil {
+			return err
+		}
+		sig := path + SignatureExt
+		signed, err := fileExists(OSFS{}, sig)
+		if err != nil {
+			return err
+		}
+		if signed {
+			if err := u.Upload(ctx, sig); err != nil {
+				return err
+			}
+		}
+		return removeRotated(OSFS{}, path)
+	})
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+package rlog
+
+import (
+	"archive/tar"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrCorrupt is wrapped by the errors VerifyRotations reports for files that
+// fail their integrity checks.
+var ErrCorrupt = errors.New("corrupt rotated file")
+
+// CorruptFile is a rotated file or bundle that failed VerifyRotations.
+type CorruptFile struct {
+	Path string // path of the file, the directory joined with its name
+	Err  error  // what failed, wrapping ErrCorrupt and the underlying error
+}
+
+// WithStartupVerification makes New run VerifyRotations and report every
+// corrupt file to the error handler and LastError, counting them in Stats as
+// VerifyFailures. Corrupt files don't stop the Writer from being created.
+func WithStartupVerification() Option {
+	return func(w *Writer) {
+		w.verifyOnStart = true
+	}
+}
+
+// VerifyRotations checks the integrity of the rotated files and bundles in the
+// Writer's directory and returns those that fail, so corrupt archives are
+// caught before they're shipped to cold storage. Compressed files and bundles
+// are decompressed in full, which verifies gzip's checksums, and with
+// WithSigner, files with a signature are checked against it. Plain files
+// without a signature have nothing to check. Each corrupt file is also emitted
+// as an EventCorruption. The returned error is only set when the directory
+// itself can't be read.
+//
+// The Writer's lock is only held to list the files, so flushes carry on while
+// they're read. A file that fails is checked again under the lock before being
+// reported, so one that rotation, bundling, or retention was changing at the
+// time isn't mistaken for corrupt, and one removed meanwhile is skipped.
+func (w *Writer) VerifyRotations() ([]CorruptFile, error) {
+	paths, err := w.rotatedPaths()
+	if err != nil {
+		return nil, err
+	}
+	var pub ed25519.PublicKey
+	if w.signer != nil {
+		pub = w.signer.Public().(ed25519.PublicKey)
+	}
+	var corrupt []CorruptFile
+	for _, path := range paths {
+		if w.verifyRotated(path, pub) == nil {
+			continue
+		}
+		if err := w.verifyRotatedLocked(path, pub); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			err = fmt.Errorf("%w: %s: %w", ErrCorrupt, path, err)
+			corrupt = append(corrupt, CorruptFile{path, err})
+			w.emit(Event{Kind: EventCorruption, Path: path, Err: err})
+		}
+	}
+	return corrupt, nil
+}
+
+// rotatedPaths lists the rotated files and bundles in the Writer's directory
+// under its lock.
+func (w *Writer) rotatedPaths() ([]string, error) {
+	if w.ioMu != nil {
+		w.ioMu.Lock()
+		defer w.ioMu.Unlock()
+	}
+	entries, err := w.fs.ReadDir(w.dirPath)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || isActiveName(name) || !(isLogName(name) || strings.HasSuffix(name, BundleExt)) {
+			continue
+		}
+		paths = append(paths, filepath.Join(w.dirPath, name))
+	}
+	return paths, nil
+}
+
+// verifyRotatedLocked is verifyRotated under the Writer's lock.
+func (w *Writer) verifyRotatedLocked(path string, pub ed25519.PublicKey) error {
+	if w.ioMu != nil {
+		w.ioMu.Lock()
+		defer w.ioMu.Unlock()
+	}
+	return w.verifyRotated(path, pub)
+}
+
+// verifyRotated checks the rotated file or bundle at path, and its signature
+// if pub is set and one exists.
+func (w *Writer) verifyRotated(path string, pub ed25519.PublicKey) error {
+	switch {
+	case strings.HasSuffix(path, BundleExt):
+		f, err := w.fs.OpenFile(path, os.O_RDONLY, 0)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return copyBundle(tar.NewWriter(io.Discard), f)
+	case strings.HasSuffix(path, ".gz"):
+		rc, err := openLog(w.fs, path)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		if _, err := io.Copy(io.Discard, rc); err != nil {
+			return err
+		}
+	}
+	if pub == nil {
+		return nil
+	}
+	if signed, err := fileExists(w.fs, path+SignatureExt); err != nil || !signed {
+		return err
+	}
+	return verifyFile(w.fs, path, pub)
+}
+
+// reportCorrupt runs VerifyRotations for WithStartupVerification.
+func (w *Writer) reportCorrupt() {
+	corrupt, err := w.VerifyRotations()
+	if err != nil {
+		w.fail(&w.stats.verifyFailures, fmt.Errorf("failed to verify rotated files: %w", err))
+		return
+	}
+	for _, c := range corrupt {
+		w.fail(&w.stats.verifyFailures, c.Err)
+	}
+}
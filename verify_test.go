@@ -0,0 +1,121 @@
+package rlog
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestVerifyRotations verifies that truncated archives and tampered signed
+// files are reported, and that intact ones aren't.
+func TestVerifyRotations(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { clock = clock.Add(time.Second); return clock }
+	defer func() { now = time.Now }()
+
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	w, m, err := NewMemory(WithStreamCompression(), WithMaxFileSize(16), WithSigner(key))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		fmt.Fprintf(w, "line %d\n", i)
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	rotations, err := w.ListRotations()
+	if err != nil || len(rotations) < 3 {
+		t.Fatalf("expected at least 3 rotations, got %d (err %v)", len(rotations), err)
+	}
+	truncated, _ := m.ReadFile(rotations[0].Name)
+	writeFile(m, rotations[0].Name, truncated[:len(truncated)/2], 0o644)
+	writeFile(m, rotations[1].Name+SignatureExt, []byte("00"), 0o644)
+
+	var reported []error
+	w, err = New(".", WithFS(m), WithSigner(key), WithStartupVerification(),
+		WithErrorHandler(func(err error) { reported = append(reported, err) }))
+	if err != nil {
+		t.Fatalf("failed to reopen Writer: %v", err)
+	}
+	defer w.Close()
+	if s := w.Stats(); s.VerifyFailures != 2 || s.FlushFailures != 0 {
+		t.Errorf("expected 2 verify failures and no flush failures, got %+v", s)
+	}
+	events := w.Events()
+	corrupt, err := w.VerifyRotations()
+	if err != nil {
+		t.Fatalf("VerifyRotations failed: %v", err)
+	}
+	if len(corrupt) != 2 || corrupt[0].Path != rotations[0].Path || corrupt[1].Path != rotations[1].Path {
+		t.Fatalf("expected the first two rotations to be corrupt, got %v", corrupt)
+	}
+	for _, c := range corrupt {
+		if !errors.Is(c.Err, ErrCorrupt) {
+			t.Errorf("%s: expected ErrCorrupt, got %v", c.Path, c.Err)
+		}
+	}
+	if !errors.Is(corrupt[1].Err, ErrBadSignature) {
+		t.Errorf("expected a signature failure, got %v", corrupt[1].Err)
+	}
+	if len(reported) != 2 {
+		t.Errorf("expected 2 corrupt files reported at startup, got %v", reported)
+	}
+	for _, c := range corrupt {
+		if e := <-events; e.Kind != EventCorruption || e.Path != c.Path || e.Err != c.Err {
+			t.Errorf("expected a corruption event for %s, got %+v", c.Path, e)
+		}
+	}
+}
+
+// gatedFS blocks opening files for reading with the given suffix until
+// released.
+type gatedFS struct {
+	*Memory
+	suffix  string
+	opened  chan struct{}
+	release chan struct{}
+}
+
+func (g *gatedFS) OpenFile(name string, flag int, perm fs.FileMode) (File, error) {
+	if flag == os.O_RDONLY && strings.HasSuffix(name, g.suffix) {
+		g.opened <- struct{}{}
+		<-g.release
+	}
+	return g.Memory.OpenFile(name, flag, perm)
+}
+
+// TestVerifyRotationsUnlocked verifies that flushes aren't blocked while
+// VerifyRotations reads the rotated files.
+func TestVerifyRotationsUnlocked(t *testing.T) {
+	g := &gatedFS{Memory: NewMemoryFS(), suffix: ".log.gz", opened: make(chan struct{}), release: make(chan struct{})}
+	writeFile(g, "20240101-120000.000000.log.gz", []byte("not gzip"), 0o644)
+	w, err := New(".", WithFS(g))
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	done := make(chan []CorruptFile, 1)
+	go func() {
+		corrupt, _ := w.VerifyRotations()
+		done <- corrupt
+	}()
+	<-g.opened
+	w.WriteString("during\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	close(g.release)
+	<-g.opened // checked again under the lock
+	if corrupt := <-done; len(corrupt) != 1 {
+		t.Errorf("expected 1 corrupt file, got %v", corrupt)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/Data-Corruption/rlog/zaprlog
+
+go 1.22.4
+
+require (
+	github.com/Data-Corruption/rlog v0.0.0
+	go.uber.org/zap v1.27.0
+)
+
+require go.uber.org/multierr v1.10.0 // indirect
+
+replace github.com/Data-Corruption/rlog => ../
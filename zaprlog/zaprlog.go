@@ -0,0 +1,51 @@
+// Copyright 2025 Matthew Pombo. All rights reserved.
+// Use of this source code is governed by an Mozilla Public License, version 2.0
+// license that can be found in the LICENSE file.
+
+// Package zaprlog adapts an *rlog.Writer for zap, so zap-based services can
+// adopt rlog's buffering and rotation by swapping their core. It lives in its
+// own module to keep rlog itself free of dependencies.
+//
+// Usage:
+//
+//	w, err := rlog.New("logs")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	logger := zap.New(zaprlog.NewCore(w, zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zap.InfoLevel))
+//	defer w.Close()
+//	defer logger.Sync()
+package zaprlog
+
+import (
+	"github.com/Data-Corruption/rlog"
+	"go.uber.org/zap/zapcore"
+)
+
+// WriteSyncer returns w as a zapcore.WriteSyncer. Each zap entry reaches w in
+// a single Write, and Sync flushes w's buffer to disk. zap syncs on its own
+// before exiting for Panic and Fatal entries, so those lines aren't lost.
+//
+// w must not be created with rlog.WithNoSync, as zap writes concurrently.
+func WriteSyncer(w *rlog.Writer) zapcore.WriteSyncer {
+	return writeSyncer{w}
+}
+
+type writeSyncer struct {
+	w *rlog.Writer
+}
+
+func (s writeSyncer) Write(p []byte) (int, error) {
+	return s.w.Write(p)
+}
+
+// Sync flushes the Writer's buffer.
+func (s writeSyncer) Sync() error {
+	return s.w.Flush()
+}
+
+// NewCore returns a zapcore.Core that encodes entries enabled by level with
+// enc and writes them to w.
+func NewCore(w *rlog.Writer, enc zapcore.Encoder, level zapcore.LevelEnabler) zapcore.Core {
+	return zapcore.NewCore(enc, WriteSyncer(w), level)
+}
@@ -0,0 +1,35 @@
+package zaprlog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Data-Corruption/rlog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestCore verifies that zap entries are written through the Writer and that
+// Sync flushes them.
+func TestCore(t *testing.T) {
+	w, m, err := rlog.NewMemory()
+	if err != nil {
+		t.Fatalf("failed to create Writer: %v", err)
+	}
+	defer w.Close()
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	logger := zap.New(NewCore(w, enc, zap.InfoLevel))
+
+	logger.Debug("hidden")
+	logger.Info("started", zap.Int("port", 8080))
+	if got := m.Files()["latest.log"]; len(got) != 0 {
+		t.Fatalf("expected the entry to stay buffered until Sync, got %q", got)
+	}
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	got := string(m.Files()["latest.log"])
+	if strings.Count(got, "\n") != 1 || !strings.Contains(got, `"msg":"started"`) || !strings.Contains(got, `"port":8080`) {
+		t.Errorf("unexpected output %q", got)
+	}
+}